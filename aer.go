@@ -48,9 +48,14 @@ func (aer *AER) ToGeodetic(ref Geodetic) Geodetic {
 
 // 转地心惯性坐标系
 func (aer *AER) ToECI(ref Geodetic, t time.Time) ECI {
+	return aer.ToECIWith(ref, t, DefaultTransformer())
+}
+
+// ToECIWith 使用转换器 tr 转地心惯性坐标系
+func (aer *AER) ToECIWith(ref Geodetic, t time.Time, tr *Transformer) ECI {
 	east, north, up := AER2ENU(aer.Azimuth, aer.Elevation, aer.SRange)
 	x, y, z := ENU2ECEF(east, north, up, ref.Latitude, ref.Longitude, ref.Altitude, aer.Ell)
-	xECI, yECI, zECI := ECEF2ECI(x, y, z, t)
+	xECI, yECI, zECI := tr.ECEF2ECI(x, y, z, t)
 	return ECI{
 		X:   xECI,
 		Y:   yECI,
@@ -0,0 +1,159 @@
+package gomap3d
+
+import (
+	"math"
+	"sort"
+)
+
+// ============================================================
+// 大气密度模型（用于大气阻力摄动）
+//
+//   ExponentialAtmosphere  分段指数模型，Vallado 表 8-4，0–1000 km
+//   HarrisPriester         Harris-Priester 模型，Montenbruck & Gill 表 3.8，
+//                          100–1000 km，计入周日密度隆起（平均太阳活动）
+// ============================================================
+
+// Atmosphere 大气密度模型
+type Atmosphere interface {
+	// Density 大气密度 (kg/m³)
+	// h 为大地高 (m)，r、sun 为目标与太阳在同一惯性系中的位置 (m)
+	Density(h float64, r, sun [3]float64) float64
+}
+
+// ExponentialAtmosphere 分段指数大气模型 ρ = ρ0·exp(-(h-h0)/H)
+type ExponentialAtmosphere struct{}
+
+// expAtmTable 基准高度 h0 (km)、基准密度 ρ0 (kg/m³)、标高 H (km)
+var expAtmTable = [...]struct{ h0, rho0, H float64 }{
+	{0, 1.225, 7.249},
+	{25, 3.899e-2, 6.349},
+	{30, 1.774e-2, 6.682},
+	{40, 3.972e-3, 7.554},
+	{50, 1.057e-3, 8.382},
+	{60, 3.206e-4, 7.714},
+	{70, 8.770e-5, 6.549},
+	{80, 1.905e-5, 5.799},
+	{90, 3.396e-6, 5.382},
+	{100, 5.297e-7, 5.877},
+	{110, 9.661e-8, 7.263},
+	{120, 2.438e-8, 9.473},
+	{130, 8.484e-9, 12.636},
+	{140, 3.845e-9, 16.149},
+	{150, 2.070e-9, 22.523},
+	{180, 5.464e-10, 29.740},
+	{200, 2.789e-10, 37.105},
+	{250, 7.248e-11, 45.546},
+	{300, 2.418e-11, 53.628},
+	{350, 9.518e-12, 53.298},
+	{400, 3.725e-12, 58.515},
+	{450, 1.585e-12, 60.828},
+	{500, 6.967e-13, 63.822},
+	{600, 1.454e-13, 71.835},
+	{700, 3.614e-14, 88.667},
+	{800, 1.170e-14, 124.64},
+	{900, 5.245e-15, 181.05},
+	{1000, 3.019e-15, 268.00},
+}
+
+// Density 实现 Atmosphere，低于 0 km 按海平面、高于 1000 km 按最后一段外推
+func (ExponentialAtmosphere) Density(h float64, r, sun [3]float64) float64 {
+	hk := math.Max(h/1000, 0)
+	i := sort.Search(len(expAtmTable), func(i int) bool { return expAtmTable[i].h0 > hk }) - 1
+	e := expAtmTable[i]
+	return e.rho0 * math.Exp(-(hk-e.h0)/e.H)
+}
+
+// HarrisPriester Harris-Priester 大气模型
+// 密度在周日隆起方向 (太阳方向滞后 30°) 取最大值，在反方向取最小值：
+// ρ = ρmin + (ρmax - ρmin)·cos^N(ψ/2)
+type HarrisPriester struct {
+	N float64 // 隆起指数，低倾角轨道取 2，极轨取 6；零值按 2 处理
+}
+
+// hpTable 高度 (km)、最小/最大密度 (g/km³ = 1e-12 kg/m³)，平均太阳活动
+var hpTable = [...]struct{ h, min, max float64 }{
+	{100, 497400.0, 497400.0},
+	{120, 24900.0, 24900.0},
+	{130, 8377.0, 8710.0},
+	{140, 3899.0, 4059.0},
+	{150, 2122.0, 2215.0},
+	{160, 1263.0, 1344.0},
+	{170, 800.8, 875.8},
+	{180, 528.3, 601.0},
+	{190, 361.7, 429.7},
+	{200, 255.7, 316.2},
+	{210, 183.9, 239.6},
+	{220, 134.1, 185.3},
+	{230, 99.49, 145.5},
+	{240, 74.88, 115.7},
+	{250, 57.09, 93.08},
+	{260, 44.03, 75.55},
+	{270, 34.30, 61.82},
+	{280, 26.97, 50.95},
+	{290, 21.39, 42.26},
+	{300, 17.08, 35.26},
+	{320, 10.99, 25.11},
+	{340, 7.214, 18.19},
+	{360, 4.824, 13.37},
+	{380, 3.274, 9.955},
+	{400, 2.249, 7.492},
+	{420, 1.558, 5.684},
+	{440, 1.091, 4.355},
+	{460, 0.7701, 3.362},
+	{480, 0.5474, 2.612},
+	{500, 0.3916, 2.042},
+	{520, 0.2819, 1.605},
+	{540, 0.2042, 1.267},
+	{560, 0.1488, 1.005},
+	{580, 0.1092, 0.7997},
+	{600, 0.08070, 0.6390},
+	{620, 0.06012, 0.5123},
+	{640, 0.04519, 0.4121},
+	{660, 0.03430, 0.3325},
+	{680, 0.02632, 0.2691},
+	{700, 0.02043, 0.2185},
+	{720, 0.01607, 0.1779},
+	{740, 0.01281, 0.1452},
+	{760, 0.01036, 0.1190},
+	{780, 0.008496, 0.09776},
+	{800, 0.007069, 0.08059},
+	{840, 0.004680, 0.05741},
+	{880, 0.003200, 0.04210},
+	{920, 0.002210, 0.03130},
+	{960, 0.001560, 0.02360},
+	{1000, 0.001150, 0.01810},
+}
+
+// hpLag 周日隆起相对太阳的赤经滞后 (rad)
+const hpLag = 30 * math.Pi / 180
+
+// Density 实现 Atmosphere，100 km 以下与 1000 km 以上返回 0
+func (hp HarrisPriester) Density(h float64, r, sun [3]float64) float64 {
+	hk := h / 1000
+	last := len(hpTable) - 1
+	if hk < hpTable[0].h || hk > hpTable[last].h {
+		return 0
+	}
+	i := sort.Search(last, func(i int) bool { return hpTable[i+1].h >= hk })
+	lo, hi := hpTable[i], hpTable[i+1]
+
+	// 相邻高度间按指数插值
+	dh := hk - lo.h
+	hMin := (lo.h - hi.h) / math.Log(hi.min/lo.min)
+	hMax := (lo.h - hi.h) / math.Log(hi.max/lo.max)
+	rhoMin := lo.min * math.Exp(-dh/hMin)
+	rhoMax := lo.max * math.Exp(-dh/hMax)
+
+	// 周日隆起方向
+	ra := math.Atan2(sun[1], sun[0])
+	dec := math.Atan2(sun[2], math.Hypot(sun[0], sun[1]))
+	eb := [3]float64{math.Cos(dec) * math.Cos(ra+hpLag), math.Cos(dec) * math.Sin(ra+hpLag), math.Sin(dec)}
+	cosPsi := dot3(r, eb) / norm3(r)
+	n := hp.N
+	if n == 0 {
+		n = 2
+	}
+	// cos^n(ψ/2) = ((1+cosψ)/2)^(n/2)
+	c := math.Pow(math.Max(0.5+0.5*cosPsi, 0), n/2)
+	return (rhoMin + (rhoMax-rhoMin)*c) * 1e-12
+}
@@ -0,0 +1,326 @@
+package gomap3d
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ============================================================
+// 弹道目标发射点 / 落点估计
+//
+// 由单次雷达测量 (AER + ECI 速度或 AER 变化率) 得到惯性系状态，
+// 按二体开普勒圆锥曲线外推，与椭球面 (可加高度偏置) 求交：
+//   向前第一个交点为落点，向后第一个交点为发射点；
+//   向前不与椭球相交的目标判为轨道目标。
+// 椭球绕 z 轴旋转对称，大地高不受地球自转影响，故交点时刻可在惯性系内求出，
+// 交点经纬度再按交点时刻的 ECI → ECEF 变换计算，即计入地球自转。
+// 可选以 RK4 数值积分计入 J2 带谐项修正交点。
+// ============================================================
+
+// EarthJ2 地球二阶带谐系数 J2 (EGM96)
+const EarthJ2 = 1.08262668355e-3
+
+// ballisticStepNu 开普勒模式下搜索交点的真近点角步长 (rad)
+const ballisticStepNu = 0.1 * math.Pi / 180
+
+// BallisticOptions 弹道估计选项，nil 等价于零值
+type BallisticOptions struct {
+	LaunchAltitude float64      // 发射点椭球高 (m)
+	ImpactAltitude float64      // 落点椭球高 (m)
+	J2             float64      // 非零时以数值积分计入 J2 带谐项，地球取 EarthJ2
+	Step           float64      // 数值积分步长 (s)，默认 1
+	Transformer    *Transformer // ECI ↔ ECEF 转换器，nil 为默认转换器
+}
+
+// BallisticResult 弹道估计结果
+type BallisticResult struct {
+	Orbital         bool            // 轨道目标：沿轨道向前不与椭球相交
+	Elements        OrbitalElements // 测量时刻的密切轨道根数
+	TimeSinceLaunch float64         // 自发射点起的飞行时间 (s)，无发射点时为 NaN
+	TimeToImpact    float64         // 距落地的剩余时间 (s)，无落点时为 NaN
+	Launch          *Geodetic       // 发射点，无交点时为 nil
+	Impact          *Geodetic       // 落点，轨道目标为 nil
+}
+
+// EstimateBallisticAER 由测站 station 的 AER 测量与目标 ECI 速度 (m/s) 估计发射点与落点
+func EstimateBallisticAER(station Geodetic, aer AER, vx, vy, vz float64, t time.Time, opts *BallisticOptions) (*BallisticResult, error) {
+	tr := opts.transformer()
+	if aer.Ell == nil {
+		aer.Ell = station.Ell
+	}
+	eci := aer.ToECIWith(station, t, tr)
+	return EstimateBallistic(eci.X, eci.Y, eci.Z, vx, vy, vz, t, station.Ell, opts)
+}
+
+// EstimateBallisticAERDeriv 由测站 station 的 AER 测量与 AER 变化率估计发射点与落点
+//
+// 输入:
+//   - dR: 斜距变化率 (m/s)
+//   - dAzDeg, dElDeg: 方位角、俯仰角变化率 (deg/s)
+func EstimateBallisticAERDeriv(station Geodetic, aer AER, dR, dAzDeg, dElDeg float64, t time.Time, opts *BallisticOptions) (*BallisticResult, error) {
+	tr := opts.transformer()
+	vx, vy, vz := tr.AERDeriv2ECIVel(aer.SRange, aer.Azimuth, aer.Elevation, dR, dAzDeg, dElDeg,
+		station.Latitude, station.Longitude, station.Altitude, t)
+	return EstimateBallisticAER(station, aer, vx, vy, vz, t, opts)
+}
+
+// EstimateBallistic 由时刻 t 的 ECI 位置 (m) 与速度 (m/s) 估计发射点与落点，GM 取自 ell
+func EstimateBallistic(x, y, z, vx, vy, vz float64, t time.Time, ell *Ellipsoid, opts *BallisticOptions) (*BallisticResult, error) {
+	if ell == nil || ell.GM <= 0 {
+		return nil, fmt.Errorf("ballistic: ellipsoid with positive GM required")
+	}
+	if opts == nil {
+		opts = &BallisticOptions{}
+	}
+	el, err := StateToElements(x, y, z, vx, vy, vz, ell.GM)
+	if err != nil {
+		return nil, err
+	}
+	b := &ballistic{
+		ell:  ell,
+		tr:   opts.transformer(),
+		t0:   t,
+		m0:   opts.transformer().Matrix(t),
+		r0:   [3]float64{x, y, z},
+		v0:   [3]float64{vx, vy, vz},
+		opts: opts,
+	}
+	if alt := b.altitude(b.r0); alt < opts.ImpactAltitude || alt < opts.LaunchAltitude {
+		return nil, fmt.Errorf("ballistic: target altitude %.1f m below launch/impact surface", alt)
+	}
+	c := newConic(b.r0, b.v0, ell.GM)
+
+	res := &BallisticResult{Elements: el, TimeSinceLaunch: math.NaN(), TimeToImpact: math.NaN()}
+	dtImpact, rImpact, okImpact := c.crossing(b, 1, opts.ImpactAltitude)
+	dtLaunch, rLaunch, okLaunch := c.crossing(b, -1, opts.LaunchAltitude)
+	res.Orbital = !okImpact
+
+	if opts.J2 != 0 {
+		// 以开普勒交点时间的 1.5 倍加 10 min 为积分上限
+		if okImpact {
+			if dtImpact, rImpact, okImpact = b.integrate(1, opts.ImpactAltitude, 1.5*dtImpact+600); !okImpact {
+				return nil, fmt.Errorf("ballistic: J2 trajectory does not reach impact altitude")
+			}
+		}
+		if okLaunch {
+			if dtLaunch, rLaunch, okLaunch = b.integrate(-1, opts.LaunchAltitude, -1.5*dtLaunch+600); !okLaunch {
+				return nil, fmt.Errorf("ballistic: J2 trajectory does not reach launch altitude")
+			}
+		}
+	}
+
+	if okImpact {
+		res.TimeToImpact = dtImpact
+		res.Impact = b.point(rImpact, dtImpact)
+	}
+	if okLaunch {
+		res.TimeSinceLaunch = -dtLaunch
+		res.Launch = b.point(rLaunch, dtLaunch)
+	}
+	return res, nil
+}
+
+func (opts *BallisticOptions) transformer() *Transformer {
+	if opts == nil || opts.Transformer == nil {
+		return DefaultTransformer()
+	}
+	return opts.Transformer
+}
+
+// ballistic 单次估计的上下文
+type ballistic struct {
+	ell    *Ellipsoid
+	tr     *Transformer
+	t0     time.Time
+	m0     [3][3]float64 // 测量时刻 ECI → ECEF 矩阵，仅用于计算大地高
+	r0, v0 [3]float64
+	opts   *BallisticOptions
+}
+
+// altitude 惯性系位置的大地高 (m)
+// 交点附近的地球自转只改变经度，岁差章动在飞行时间内可忽略，故统一使用 m0
+func (b *ballistic) altitude(r [3]float64) float64 {
+	e := multiplyMatrixVector(b.m0, r)
+	_, _, alt := ECEF2Geodetic(e[0], e[1], e[2], b.ell)
+	return alt
+}
+
+// point 相对测量时刻 dt (s) 的惯性系交点 r 的大地坐标
+func (b *ballistic) point(r [3]float64, dt float64) *Geodetic {
+	t := b.t0.Add(time.Duration(dt * float64(time.Second)))
+	x, y, z := b.tr.ECI2ECEF(r[0], r[1], r[2], t)
+	lat, lon, alt := ECEF2Geodetic(x, y, z, b.ell)
+	return &Geodetic{Latitude: lat, Longitude: lon, Altitude: alt, Ell: b.ell}
+}
+
+// ============================================================
+// 开普勒圆锥曲线
+// ============================================================
+
+// conic 以近地点方向 P̂、半通径方向 Q̂ 描述的圆锥曲线，ν0 为测量时刻真近点角
+type conic struct {
+	p, e, gm float64
+	pHat     [3]float64
+	qHat     [3]float64
+	nu0, t0  float64 // 测量时刻真近点角与距近地点时间
+}
+
+func newConic(r, v [3]float64, gm float64) *conic {
+	h := cross3(r, v)
+	hn := norm3(h)
+	rn := norm3(r)
+	rv := dot3(r, v)
+	c1 := dot3(v, v) - gm/rn
+	var ev [3]float64
+	for i := range ev {
+		ev[i] = (c1*r[i] - rv*v[i]) / gm
+	}
+	c := &conic{p: hn * hn / gm, e: norm3(ev), gm: gm}
+	// 圆轨道以测量位置为参考方向
+	if c.e < orbitTol {
+		c.e = 0
+		ev = r
+	}
+	en := norm3(ev)
+	for i := range ev {
+		c.pHat[i] = ev[i] / en
+	}
+	w := [3]float64{h[0] / hn, h[1] / hn, h[2] / hn}
+	c.qHat = cross3(w, c.pHat)
+	c.nu0 = math.Atan2(dot3(r, c.qHat), dot3(r, c.pHat))
+	c.t0 = c.timeAt(c.nu0)
+	return c
+}
+
+// position 真近点角 ν 处的位置
+func (c *conic) position(nu float64) [3]float64 {
+	rn := c.p / (1 + c.e*math.Cos(nu))
+	cosNu, sinNu := math.Cos(nu), math.Sin(nu)
+	var r [3]float64
+	for i := range r {
+		r[i] = rn * (cosNu*c.pHat[i] + sinNu*c.qHat[i])
+	}
+	return r
+}
+
+// timeAt 真近点角 ν 处距近地点的时间 (s)，对 ν 连续（椭圆轨道跨周累加）
+func (c *conic) timeAt(nu float64) float64 {
+	e, p := c.e, c.p
+	switch {
+	case math.Abs(e-1) < orbitTol:
+		// Barker: t = ½·√(p³/μ)·(D + D³/3)
+		return 0.5 * math.Sqrt(p*p*p/c.gm) * EccentricToMean(TrueToEccentric(nu, 1), 1)
+	case e < 1:
+		a := p / (1 - e*e)
+		ea := TrueToEccentric(nu, e)
+		ea += tau * math.Round((nu-ea)/tau)
+		return EccentricToMean(ea, e) / math.Sqrt(c.gm/(a*a*a))
+	default:
+		a := p / (e*e - 1)
+		return EccentricToMean(TrueToEccentric(nu, e), e) / math.Sqrt(c.gm/(a*a*a))
+	}
+}
+
+// crossing 沿 dir (+1 向前、-1 向后) 搜索大地高降至 h 的第一个交点，
+// 返回交点相对测量时刻的时间 (s) 与惯性系位置
+func (c *conic) crossing(b *ballistic, dir float64, h float64) (float64, [3]float64, bool) {
+	// 椭圆搜索一整周，双曲/抛物线搜索至渐近线方向
+	span := tau
+	if c.e >= 1 {
+		span = math.Acos(-1/c.e) - dir*c.nu0
+		span -= 1e-9
+	}
+	f := func(nu float64) float64 { return b.altitude(c.position(nu)) - h }
+
+	prev := c.nu0
+	for s := ballisticStepNu; ; s += ballisticStepNu {
+		if s > span {
+			s = span
+		}
+		nu := c.nu0 + dir*s
+		if f(nu) < 0 {
+			lo, hi := prev, nu // f(lo) ≥ 0 > f(hi)
+			for i := 0; i < 60; i++ {
+				mid := (lo + hi) / 2
+				if f(mid) < 0 {
+					hi = mid
+				} else {
+					lo = mid
+				}
+			}
+			nu = (lo + hi) / 2
+			return c.timeAt(nu) - c.t0, c.position(nu), true
+		}
+		if s >= span {
+			return 0, [3]float64{}, false
+		}
+		prev = nu
+	}
+}
+
+// ============================================================
+// J2 数值积分
+// ============================================================
+
+// accel 二体 + J2 加速度，极轴取测量时刻 ECEF z 轴在惯性系中的方向
+func (b *ballistic) accel(r [3]float64) [3]float64 {
+	k := b.m0[2]
+	rn := norm3(r)
+	re := b.ell.SemimajorAxis
+	gm := b.ell.GM
+	zk := dot3(r, k) / rn
+	c0 := -gm / (rn * rn * rn)
+	cj := -1.5 * b.opts.J2 * gm * re * re / math.Pow(rn, 5)
+	var a [3]float64
+	for i := range a {
+		a[i] = c0*r[i] + cj*((1-5*zk*zk)*r[i]+2*zk*rn*k[i])
+	}
+	return a
+}
+
+// rk4 单步 RK4
+func (b *ballistic) rk4(r, v [3]float64, h float64) (rn, vn [3]float64) {
+	add := func(a, d [3]float64, s float64) [3]float64 {
+		return [3]float64{a[0] + s*d[0], a[1] + s*d[1], a[2] + s*d[2]}
+	}
+	k1r, k1v := v, b.accel(r)
+	k2r, k2v := add(v, k1v, h/2), b.accel(add(r, k1r, h/2))
+	k3r, k3v := add(v, k2v, h/2), b.accel(add(r, k2r, h/2))
+	k4r, k4v := add(v, k3v, h), b.accel(add(r, k3r, h))
+	for i := 0; i < 3; i++ {
+		rn[i] = r[i] + h/6*(k1r[i]+2*k2r[i]+2*k3r[i]+k4r[i])
+		vn[i] = v[i] + h/6*(k1v[i]+2*k2v[i]+2*k3v[i]+k4v[i])
+	}
+	return rn, vn
+}
+
+// integrate 沿 dir 积分至大地高降至 h，最长 limit 秒，返回交点距测量时刻的时间 (s) 与位置
+func (b *ballistic) integrate(dir, h, limit float64) (float64, [3]float64, bool) {
+	step := b.opts.Step
+	if step <= 0 {
+		step = 1
+	}
+	r, v := b.r0, b.v0
+	for t := 0.0; t < limit; t += step {
+		rn, vn := b.rk4(r, v, dir*step)
+		if b.altitude(rn)-h < 0 {
+			// 在最后一步内二分
+			lo, hi := 0.0, step
+			var rc [3]float64
+			for i := 0; i < 60; i++ {
+				mid := (lo + hi) / 2
+				rc, _ = b.rk4(r, v, dir*mid)
+				if b.altitude(rc)-h < 0 {
+					hi = mid
+				} else {
+					lo = mid
+				}
+			}
+			rc, _ = b.rk4(r, v, dir*(lo+hi)/2)
+			return dir * (t + (lo+hi)/2), rc, true
+		}
+		r, v = rn, vn
+	}
+	return 0, [3]float64{}, false
+}
@@ -0,0 +1,161 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// ballisticLaunch 由发射点、ENU 发射速度构造惯性系初态，并按二体轨道外推 dt 秒
+func ballisticLaunch(t *testing.T, launch Geodetic, vE, vN, vU float64, t0 time.Time, dt float64) (r, v [3]float64) {
+	t.Helper()
+	x, y, z := Geodetic2ECEF(launch.Latitude, launch.Longitude, launch.Altitude, launch.Ell)
+	ve, vn, vu := ENUVel2ECEFVel(vE, vN, vU, launch.Latitude, launch.Longitude)
+	xi, yi, zi := ECEF2ECI(x, y, z, t0)
+	vxi, vyi, vzi := ECEFVel2ECIVel(ve, vn, vu, x, y, z, t0)
+
+	el, err := StateToElements(xi, yi, zi, vxi, vyi, vzi, launch.Ell.GM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := el.MeanMotion()
+	m := el.MeanAnomaly() + n*dt*180/math.Pi
+	el2 := ElementsFromMeanAnomaly(el.SemimajorAxis, el.Eccentricity, el.Inclination, el.RAAN, el.ArgPerigee, m, el.GM)
+	x2, y2, z2, vx2, vy2, vz2, err := el2.ToState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return [3]float64{x2, y2, z2}, [3]float64{vx2, vy2, vz2}
+}
+
+func TestEstimateBallisticKepler(t *testing.T) {
+	ell, _ := NewEllipsoid("wgs84")
+	t0 := time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)
+	launch := Geodetic{Latitude: 30, Longitude: 100, Altitude: 0, Ell: ell}
+	const flight = 300.0
+
+	// 地固系 5 km/s、45° 仰角向东北发射
+	r, v := ballisticLaunch(t, launch, 2500, 2500, 3535.5, t0, flight)
+	tm := t0.Add(time.Duration(flight * float64(time.Second)))
+	res, err := EstimateBallistic(r[0], r[1], r[2], v[0], v[1], v[2], tm, ell, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Orbital || res.Launch == nil || res.Impact == nil {
+		t.Fatalf("unexpected classification: %+v", res)
+	}
+	if math.Abs(res.TimeSinceLaunch-flight) > 1e-3 {
+		t.Errorf("time since launch = %.6f s, want %.0f", res.TimeSinceLaunch, flight)
+	}
+	if d := vecDist(geoECEF(res.Launch), geoECEF(&launch)); d > 0.01 {
+		t.Errorf("launch point error = %.3e m: %+v", d, *res.Launch)
+	}
+	if math.Abs(res.Impact.Altitude) > 1e-3 || res.TimeToImpact <= 0 {
+		t.Errorf("impact = %+v after %.1f s", *res.Impact, res.TimeToImpact)
+	}
+
+	// 由落点反推：从落点时刻回溯同一轨道应回到测量位置
+	r2, _ := ballisticLaunch(t, launch, 2500, 2500, 3535.5, t0, flight+res.TimeToImpact)
+	tImp := tm.Add(time.Duration(res.TimeToImpact * float64(time.Second)))
+	xe, ye, ze := ECI2ECEF(r2[0], r2[1], r2[2], tImp)
+	if d := vecDist([3]float64{xe, ye, ze}, geoECEF(res.Impact)); d > 0.01 {
+		t.Errorf("impact point error = %.3e m", d)
+	}
+
+	// 发射/落点高度偏置
+	res2, err := EstimateBallistic(r[0], r[1], r[2], v[0], v[1], v[2], tm, ell,
+		&BallisticOptions{LaunchAltitude: 0, ImpactAltitude: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(res2.Impact.Altitude-10000) > 1e-3 || res2.TimeToImpact >= res.TimeToImpact {
+		t.Errorf("impact at 10 km: %+v after %.1f s", *res2.Impact, res2.TimeToImpact)
+	}
+}
+
+func TestEstimateBallisticOrbital(t *testing.T) {
+	ell, _ := NewEllipsoid("wgs84")
+	tm := time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)
+
+	// 400 km 圆轨道
+	rn := ell.SemimajorAxis + 400e3
+	vc := math.Sqrt(ell.GM / rn)
+	res, err := EstimateBallistic(rn, 0, 0, 0, vc*math.Cos(0.9), vc*math.Sin(0.9), tm, ell, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Orbital || res.Launch != nil || res.Impact != nil || !math.IsNaN(res.TimeToImpact) {
+		t.Errorf("circular orbit: %+v", res)
+	}
+
+	// 逃逸双曲轨道：无落点，但向后可回溯到发射点
+	res, err = EstimateBallistic(rn, 0, 0, 3000, 11000, 0, tm, ell, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Orbital || res.Impact != nil || res.Launch == nil || res.Elements.Eccentricity <= 1 {
+		t.Errorf("hyperbolic: %+v", res)
+	}
+
+	// 低于地面的目标
+	if _, err := EstimateBallistic(6300e3, 0, 0, 0, 7000, 0, tm, ell, nil); err == nil {
+		t.Error("expected error for target below surface")
+	}
+}
+
+func TestEstimateBallisticJ2AndAER(t *testing.T) {
+	ell, _ := NewEllipsoid("wgs84")
+	t0 := time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)
+	launch := Geodetic{Latitude: 40, Longitude: 120, Altitude: 0, Ell: ell}
+	const flight = 400.0
+	r, v := ballisticLaunch(t, launch, -4000, 3000, 3500, t0, flight)
+	tm := t0.Add(time.Duration(flight * float64(time.Second)))
+
+	kep, err := EstimateBallistic(r[0], r[1], r[2], v[0], v[1], v[2], tm, ell, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j2, err := EstimateBallistic(r[0], r[1], r[2], v[0], v[1], v[2], tm, ell, &BallisticOptions{J2: EarthJ2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// J2 修正为公里量级，时间为秒量级
+	dLaunch := vecDist(geoECEF(kep.Launch), geoECEF(j2.Launch))
+	dImpact := vecDist(geoECEF(kep.Impact), geoECEF(j2.Impact))
+	if dLaunch < 10 || dLaunch > 20e3 || dImpact < 10 || dImpact > 20e3 {
+		t.Errorf("J2 correction: launch %.1f m, impact %.1f m", dLaunch, dImpact)
+	}
+	if math.Abs(kep.TimeToImpact-j2.TimeToImpact) > 10 || math.Abs(kep.TimeSinceLaunch-j2.TimeSinceLaunch) > 10 {
+		t.Errorf("J2 times: %.2f/%.2f vs %.2f/%.2f", j2.TimeSinceLaunch, j2.TimeToImpact, kep.TimeSinceLaunch, kep.TimeToImpact)
+	}
+	// 步长减半结果基本不变
+	j2h, _ := EstimateBallistic(r[0], r[1], r[2], v[0], v[1], v[2], tm, ell, &BallisticOptions{J2: EarthJ2, Step: 0.5})
+	if d := vecDist(geoECEF(j2.Impact), geoECEF(j2h.Impact)); d > 0.01 {
+		t.Errorf("J2 step dependence = %.3e m", d)
+	}
+
+	// 雷达测量：AER + ECI 速度 与 AER + 变化率 得到相同结果
+	station := Geodetic{Latitude: 41, Longitude: 121, Altitude: 50, Ell: ell}
+	eci := ECI{X: r[0], Y: r[1], Z: r[2], T: tm, Ell: ell}
+	aer := eci.ToAER(station)
+	byVel, err := EstimateBallisticAER(station, aer, v[0], v[1], v[2], tm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := vecDist(geoECEF(byVel.Launch), geoECEF(kep.Launch)); d > 0.01 {
+		t.Errorf("AER launch differs by %.3e m", d)
+	}
+	dR, dAz, dEl := ECIVel2AERDeriv(v[0], v[1], v[2], r[0], r[1], r[2], station.Latitude, station.Longitude, station.Altitude, tm)
+	byRate, err := EstimateBallisticAERDeriv(station, aer, dR, dAz, dEl, tm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := vecDist(geoECEF(byRate.Impact), geoECEF(kep.Impact)); d > 1 {
+		t.Errorf("AER rate impact differs by %.3e m", d)
+	}
+}
+
+func geoECEF(g *Geodetic) [3]float64 {
+	e := g.ToECEF()
+	return [3]float64{e.X, e.Y, e.Z}
+}
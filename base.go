@@ -127,13 +127,23 @@ func ENU2ECEF(e, n, u, lat0, lon0, h0 float64, ell *Ellipsoid) (x, y, z float64)
 }
 
 // GMST 模式控制
-// useSimpleGMST 为 true 时，ECI↔ECEF 仅用 Rz(GMST) 旋转，不含岁差章动（对标 Octave 实现）
-var useSimpleGMST = true
-
-// SetGMSTMode 设置是否仅用简单 GMST 旋转（不含岁差章动）
+// SetGMSTMode 设置默认转换器是否仅用简单 GMST 旋转（不含岁差章动）
 //   true  = 仅用 Rz(GMST)，对标 Octave/MATLAB Aerospace GMST 模式
-//   false = 完整 IAU-2006/2000B 链（frame bias + 岁差 + 章动 + GAST）
-func SetGMSTMode(simple bool) { useSimpleGMST = simple }
+//   false = 完整 IAU-2006/2000B 链（frame bias + 岁差 + 章动 + GAST），
+//           已 SetNutation2000A 时为 IAU-2006/2000A CIO 链
+// 该设置作用于整个进程；并发场景下不同模式请各自使用 NewTransformer。
+func SetGMSTMode(simple bool) {
+	updateDefaultOptions(func(o *Options) {
+		switch {
+		case simple:
+			o.Model = ModelGMST
+		case o.Nutation != nil:
+			o.Model = ModelIAU2006A
+		default:
+			o.Model = ModelIAU2006B
+		}
+	})
+}
 
 const tau = 2 * math.Pi
 
@@ -164,9 +174,9 @@ func juliandate(t time.Time) float64 {
 
 }
 
-// greenwichsrt 计算格林威治恒星时（弧度）
-func greenwichsrt(jd float64) float64 {
-	tUT1 := (jd - 2451545.0) / 36525.0
+// greenwichsrt 计算格林威治平恒星时（弧度），jdUT1 为 UT1 儒略日
+func greenwichsrt(jdUT1 float64) float64 {
+	tUT1 := (jdUT1 - 2451545.0) / 36525.0
 
 	gmstSec := 67310.54841 +
 		(876600*3600+8640184.812866)*tUT1 +
@@ -207,53 +217,16 @@ func multiplyMatrixVector(m [3][3]float64, v [3]float64) [3]float64 {
 }
 
 // ECI2ECEF 将ECI坐标(GCRF)转换为ECEF坐标(ITRF)
-// 默认仅用简单 GMST 旋转 Rz(GMST)（对标 Octave 实现），
-// 可通过 SetGMSTMode(false) 切换到完整 IAU-2006/2000B 归算链。
+// 使用默认转换器：默认仅用简单 GMST 旋转 Rz(GMST)（对标 Octave 实现），
+// 可通过 SetGMSTMode(false) 切换到完整 IAU-2006/2000B 归算链，
+// 并可通过 SetEOP 引入 UT1-UTC、极移和天极偏差修正。
+// 需要指定模型时使用 Transformer.ECI2ECEF。
 func ECI2ECEF(x, y, z float64, t time.Time) (xEcef, yEcef, zEcef float64) {
-	jd := juliandate(t)
-
-	var M [3][3]float64
-	if useSimpleGMST {
-		gmst := greenwichsrt(jd)
-		cosG := math.Cos(gmst)
-		sinG := math.Sin(gmst)
-		M = [3][3]float64{
-			{cosG, -sinG, 0},
-			{sinG, cosG, 0},
-			{0, 0, 1},
-		}
-	} else {
-		M = GCRF2ITRF(jd)
-	}
-
-	eciVec := [3]float64{x, y, z}
-	ecefVec := multiplyMatrixVector(M, eciVec)
-	return ecefVec[0], ecefVec[1], ecefVec[2]
+	return DefaultTransformer().ECI2ECEF(x, y, z, t)
 }
 
 // ECEF2ECI 将ECEF坐标(ITRF)转换为ECI坐标(GCRF)
-// 默认仅用简单 GMST 旋转（逆变换），
-// 可通过 SetGMSTMode(false) 切换到完整 IAU-2006/2000B 归算链。
+// 为 ECI2ECEF 的逆变换，模式与 EOP 设置同 ECI2ECEF。
 func ECEF2ECI(x, y, z float64, t time.Time) (xEci, yEci, zEci float64) {
-	jd := juliandate(t)
-
-	var M [3][3]float64
-	if useSimpleGMST {
-		gmst := greenwichsrt(jd)
-		cosG := math.Cos(gmst)
-		sinG := math.Sin(gmst)
-		// Rz(+GMST): [ct, -st; st, ct; 0, 0, 1]
-		M = [3][3]float64{
-			{cosG, sinG, 0},
-			{-sinG, cosG, 0},
-			{0, 0, 1},
-		}
-	} else {
-		M = GCRF2ITRF(jd)
-		M = transpose(M)
-	}
-
-	ecefVec := [3]float64{x, y, z}
-	eciVec := multiplyMatrixVector(M, ecefVec)
-	return eciVec[0], eciVec[1], eciVec[2]
+	return DefaultTransformer().ECEF2ECI(x, y, z, t)
 }
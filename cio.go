@@ -0,0 +1,290 @@
+package gomap3d
+
+import (
+	"math"
+)
+
+// ============================================================
+// 基于 CIO 的 GCRS → CIRS → TIRS → ITRS 变换链
+//
+// 变换链: r_ITRS = W · Rz(ERA) · C(X, Y, s) · r_GCRS
+//   C   GCRS → CIRS，由 CIP 坐标 X/Y 与 CIO 定位角 s 构成
+//   ERA 地球自转角 (UT1)
+//   W   极移矩阵 (xp, yp, s')
+// 参考: IERS Conventions 2010 Chapter 5, SOFA iauC2t06a
+// ============================================================
+
+// EarthRotationAngle 地球自转角 ERA (rad)，jdUT1 为 UT1 儒略日
+// ERA = 2π (0.7790572732640 + 1.00273781191135448 · (jdUT1 - 2451545.0))
+func EarthRotationAngle(jdUT1 float64) float64 {
+	d := jdUT1 - 2451545.0
+	f := math.Mod(jdUT1, 1.0)
+	era := tau * (f + 0.7790572732640 + 0.00273781191135448*d)
+	era = math.Mod(era, tau)
+	if era < 0 {
+		era += tau
+	}
+	return era
+}
+
+// npbMatrix 岁差-章动-偏差矩阵 NPB = N · P · B，章动 (Δψ, Δε) 单位 rad
+func npbMatrix(T, dpsi, deps float64) [3][3]float64 {
+	N := nutationMatrixFrom(dpsi, deps, meanObliquity(T))
+	return mul33(N, mul33(precessionMatrix(T), frameBiasMatrix()))
+}
+
+// CIPXY 天球中间极 (CIP) 在 GCRS 中的坐标 X、Y (rad)，jdTT 为 TT 儒略日
+// 使用默认转换器，见 Transformer.CIPXY。
+func CIPXY(jdTT float64) (x, y float64) {
+	return DefaultTransformer().CIPXY(jdTT)
+}
+
+// CIPXY 天球中间极 (CIP) 在 GCRS 中的坐标 X、Y (rad)，jdTT 为 TT 儒略日
+// 给出 CIP 级数时由级数求和 (SOFA iauXy06)，否则取自 NPB 矩阵第三行 (SOFA iauBpn2xy)，
+// 章动按转换器的 Model/Nutation 选取；有 EOP 时叠加天极偏差 dX、dY。
+func (tr *Transformer) CIPXY(jdTT float64) (x, y float64) {
+	x, y, dx, dy := tr.cip(jdTT)
+	return x + dx, y + dy
+}
+
+// cip 模型 CIP 坐标 X、Y 与 EOP 天极偏差 dX、dY (rad)
+// EOP 按 TT 日期查表，与 UTC 相差约 1 min，对天极偏差可忽略。
+func (tr *Transformer) cip(jdTT float64) (x, y, dx, dy float64) {
+	x, y = tr.cipModel((jdTT - 2451545.0) / 36525.0)
+	if tr.opts.EOP != nil {
+		if eop, err := tr.opts.EOP.At(jdTT - mjdOffset); err == nil {
+			dx, dy = eop.DX*as2r, eop.DY*as2r
+		}
+	}
+	return
+}
+
+// ---- CIO 定位角 s (IAU 2006, SOFA iauS06) ----
+
+// s06Term s+XY/2 级数中的一项
+type s06Term struct {
+	mult [8]int  // l, l', F, D, Ω, L_Ve, L_E, p_A
+	s, c float64 // sin、cos 系数 (µas)
+}
+
+// s06Poly s+XY/2 多项式部分 (µas)
+var s06Poly = [6]float64{94.00, 3808.65, -122.68, -72574.11, 27.98, 15.62}
+
+// s06Terms s+XY/2 周期项，下标为 t 的幂次
+var s06Terms = [5][]s06Term{
+	// t^0
+	{
+		{[8]int{0, 0, 0, 0, 1, 0, 0, 0}, -2640.73, 0.39},
+		{[8]int{0, 0, 0, 0, 2, 0, 0, 0}, -63.53, 0.02},
+		{[8]int{0, 0, 2, -2, 3, 0, 0, 0}, -11.75, -0.01},
+		{[8]int{0, 0, 2, -2, 1, 0, 0, 0}, -11.21, -0.01},
+		{[8]int{0, 0, 2, -2, 2, 0, 0, 0}, 4.57, 0.00},
+		{[8]int{0, 0, 2, 0, 3, 0, 0, 0}, -2.02, 0.00},
+		{[8]int{0, 0, 2, 0, 1, 0, 0, 0}, -1.98, 0.00},
+		{[8]int{0, 0, 0, 0, 3, 0, 0, 0}, 1.72, 0.00},
+		{[8]int{0, 1, 0, 0, 1, 0, 0, 0}, 1.41, 0.01},
+		{[8]int{0, 1, 0, 0, -1, 0, 0, 0}, 1.26, 0.01},
+		{[8]int{1, 0, 0, 0, -1, 0, 0, 0}, 0.63, 0.00},
+		{[8]int{1, 0, 0, 0, 1, 0, 0, 0}, 0.63, 0.00},
+		{[8]int{0, 1, 2, -2, 3, 0, 0, 0}, -0.46, 0.00},
+		{[8]int{0, 1, 2, -2, 1, 0, 0, 0}, -0.45, 0.00},
+		{[8]int{0, 0, 4, -4, 4, 0, 0, 0}, -0.36, 0.00},
+		{[8]int{0, 0, 1, -1, 1, -8, 12, 0}, 0.24, 0.12},
+		{[8]int{0, 0, 2, 0, 0, 0, 0, 0}, -0.32, 0.00},
+		{[8]int{0, 0, 2, 0, 2, 0, 0, 0}, -0.28, 0.00},
+		{[8]int{1, 0, 2, 0, 3, 0, 0, 0}, -0.27, 0.00},
+		{[8]int{1, 0, 2, 0, 1, 0, 0, 0}, -0.26, 0.00},
+		{[8]int{0, 0, 2, -2, 0, 0, 0, 0}, 0.21, 0.00},
+		{[8]int{0, 1, -2, 2, -3, 0, 0, 0}, -0.19, 0.00},
+		{[8]int{0, 1, -2, 2, -1, 0, 0, 0}, -0.18, 0.00},
+		{[8]int{0, 0, 0, 0, 0, 8, -13, -1}, 0.10, -0.05},
+		{[8]int{0, 0, 0, 2, 0, 0, 0, 0}, -0.15, 0.00},
+		{[8]int{2, 0, -2, 0, -1, 0, 0, 0}, 0.14, 0.00},
+		{[8]int{0, 1, 2, -2, 2, 0, 0, 0}, 0.14, 0.00},
+		{[8]int{1, 0, 0, -2, 1, 0, 0, 0}, -0.14, 0.00},
+		{[8]int{1, 0, 0, -2, -1, 0, 0, 0}, -0.14, 0.00},
+		{[8]int{0, 0, 4, -2, 4, 0, 0, 0}, -0.13, 0.00},
+		{[8]int{0, 0, 2, -2, 4, 0, 0, 0}, 0.11, 0.00},
+		{[8]int{1, 0, -2, 0, -3, 0, 0, 0}, 0.11, 0.00},
+		{[8]int{1, 0, -2, 0, -1, 0, 0, 0}, 0.11, 0.00},
+	},
+	// t^1
+	{
+		{[8]int{0, 0, 0, 0, 2, 0, 0, 0}, -0.07, 3.57},
+		{[8]int{0, 0, 0, 0, 1, 0, 0, 0}, 1.73, -0.03},
+		{[8]int{0, 0, 2, -2, 3, 0, 0, 0}, 0.00, 0.48},
+	},
+	// t^2
+	{
+		{[8]int{0, 0, 0, 0, 1, 0, 0, 0}, 743.52, -0.17},
+		{[8]int{0, 0, 2, -2, 2, 0, 0, 0}, 56.91, 0.06},
+		{[8]int{0, 0, 2, 0, 2, 0, 0, 0}, 9.84, -0.01},
+		{[8]int{0, 0, 0, 0, 2, 0, 0, 0}, -8.85, 0.01},
+		{[8]int{0, 1, 0, 0, 0, 0, 0, 0}, -6.38, -0.05},
+		{[8]int{1, 0, 0, 0, 0, 0, 0, 0}, -3.07, 0.00},
+		{[8]int{0, 1, 2, -2, 2, 0, 0, 0}, 2.23, 0.00},
+		{[8]int{0, 0, 2, 0, 1, 0, 0, 0}, 1.67, 0.00},
+		{[8]int{1, 0, 2, 0, 2, 0, 0, 0}, 1.30, 0.00},
+		{[8]int{0, 1, -2, 2, -2, 0, 0, 0}, 0.93, 0.00},
+		{[8]int{1, 0, 0, -2, 0, 0, 0, 0}, 0.68, 0.00},
+		{[8]int{0, 0, 2, -2, 1, 0, 0, 0}, -0.55, 0.00},
+		{[8]int{1, 0, -2, 0, -2, 0, 0, 0}, 0.53, 0.00},
+		{[8]int{0, 0, 0, 2, 0, 0, 0, 0}, -0.27, 0.00},
+		{[8]int{1, 0, 0, 0, 1, 0, 0, 0}, -0.27, 0.00},
+		{[8]int{1, 0, -2, -2, -2, 0, 0, 0}, -0.26, 0.00},
+		{[8]int{1, 0, 0, 0, -1, 0, 0, 0}, -0.25, 0.00},
+		{[8]int{1, 0, 2, 0, 1, 0, 0, 0}, 0.22, 0.00},
+		{[8]int{2, 0, 0, -2, 0, 0, 0, 0}, -0.21, 0.00},
+		{[8]int{2, 0, -2, 0, -1, 0, 0, 0}, 0.20, 0.00},
+		{[8]int{0, 0, 2, 2, 2, 0, 0, 0}, 0.17, 0.00},
+		{[8]int{2, 0, 2, 0, 2, 0, 0, 0}, 0.13, 0.00},
+		{[8]int{2, 0, 0, 0, 0, 0, 0, 0}, -0.13, 0.00},
+		{[8]int{1, 0, 2, -2, 2, 0, 0, 0}, -0.12, 0.00},
+		{[8]int{0, 0, 2, 0, 0, 0, 0, 0}, -0.11, 0.00},
+	},
+	// t^3
+	{
+		{[8]int{0, 0, 0, 0, 1, 0, 0, 0}, 0.30, -23.42},
+		{[8]int{0, 0, 2, -2, 2, 0, 0, 0}, -0.03, -1.46},
+		{[8]int{0, 0, 2, 0, 2, 0, 0, 0}, -0.01, -0.25},
+		{[8]int{0, 0, 0, 0, 2, 0, 0, 0}, 0.00, 0.23},
+	},
+	// t^4
+	{
+		{[8]int{0, 0, 0, 0, 1, 0, 0, 0}, -0.26, -0.01},
+	},
+}
+
+// CIOLocator CIO 定位角 s (rad)，IAU 2006 模型
+// jdTT 为 TT 儒略日，x、y 为 CIP 坐标 (rad)
+func CIOLocator(jdTT, x, y float64) float64 {
+	T := (jdTT - 2451545.0) / 36525.0
+	args := s06Args(T)
+
+	var w [6]float64
+	copy(w[:], s06Poly[:])
+	for k, terms := range s06Terms {
+		w[k] += sumS06Series(terms, args)
+	}
+	sxy2 := w[0] + (w[1]+(w[2]+(w[3]+(w[4]+w[5]*T)*T)*T)*T)*T
+	return sxy2*1e-6*as2r - x*y/2
+}
+
+// s06Args s06Term 使用的 8 个基本幅角: l, l', F, D, Ω, L_Ve, L_E, p_A
+func s06Args(T float64) [8]float64 {
+	fa := fundArgs2000A(T)
+	return [8]float64{fa[0], fa[1], fa[2], fa[3], fa[4], fa[6], fa[7], fa[13]}
+}
+
+// sumS06Series 级数求和 (µas)，从小项到大项累加
+func sumS06Series(terms []s06Term, args [8]float64) float64 {
+	sum := 0.0
+	for i := len(terms) - 1; i >= 0; i-- {
+		term := terms[i]
+		a := 0.0
+		for j, m := range term.mult {
+			a += float64(m) * args[j]
+		}
+		sa, ca := math.Sincos(a)
+		sum += term.s*sa + term.c*ca
+	}
+	return sum
+}
+
+// ---- 分点差补充项 (IERS 2003, SOFA iauEect00) ----
+
+// eect00Terms 补充项 t^0 部分，系数单位 µas
+var eect00Terms = []s06Term{
+	{[8]int{0, 0, 0, 0, 1, 0, 0, 0}, 2640.96, -0.39},
+	{[8]int{0, 0, 0, 0, 2, 0, 0, 0}, 63.52, -0.02},
+	{[8]int{0, 0, 2, -2, 3, 0, 0, 0}, 11.75, 0.01},
+	{[8]int{0, 0, 2, -2, 1, 0, 0, 0}, 11.21, 0.01},
+	{[8]int{0, 0, 2, -2, 2, 0, 0, 0}, -4.55, 0.00},
+	{[8]int{0, 0, 2, 0, 3, 0, 0, 0}, 2.02, 0.00},
+	{[8]int{0, 0, 2, 0, 1, 0, 0, 0}, 1.98, 0.00},
+	{[8]int{0, 0, 0, 0, 3, 0, 0, 0}, -1.72, 0.00},
+	{[8]int{0, 1, 0, 0, 1, 0, 0, 0}, -1.41, -0.01},
+	{[8]int{0, 1, 0, 0, -1, 0, 0, 0}, -1.26, -0.01},
+	{[8]int{1, 0, 0, 0, -1, 0, 0, 0}, -0.63, 0.00},
+	{[8]int{1, 0, 0, 0, 1, 0, 0, 0}, -0.63, 0.00},
+	{[8]int{0, 1, 2, -2, 3, 0, 0, 0}, 0.46, 0.00},
+	{[8]int{0, 1, 2, -2, 1, 0, 0, 0}, 0.45, 0.00},
+	{[8]int{0, 0, 4, -4, 4, 0, 0, 0}, 0.36, 0.00},
+	{[8]int{0, 0, 1, -1, 1, -8, 12, 0}, -0.24, -0.12},
+	{[8]int{0, 0, 2, 0, 0, 0, 0, 0}, 0.32, 0.00},
+	{[8]int{0, 0, 2, 0, 2, 0, 0, 0}, 0.28, 0.00},
+	{[8]int{1, 0, 2, 0, 3, 0, 0, 0}, 0.27, 0.00},
+	{[8]int{1, 0, 2, 0, 1, 0, 0, 0}, 0.26, 0.00},
+	{[8]int{0, 0, 2, -2, 0, 0, 0, 0}, -0.21, 0.00},
+	{[8]int{0, 1, -2, 2, -3, 0, 0, 0}, 0.19, 0.00},
+	{[8]int{0, 1, -2, 2, -1, 0, 0, 0}, 0.18, 0.00},
+	{[8]int{0, 0, 0, 0, 0, 8, -13, -1}, -0.10, 0.05},
+	{[8]int{0, 0, 0, 2, 0, 0, 0, 0}, 0.15, 0.00},
+	{[8]int{2, 0, -2, 0, -1, 0, 0, 0}, -0.14, 0.00},
+	{[8]int{1, 0, 0, -2, 1, 0, 0, 0}, 0.14, 0.00},
+	{[8]int{0, 1, 2, -2, 2, 0, 0, 0}, -0.14, 0.00},
+	{[8]int{1, 0, 0, -2, -1, 0, 0, 0}, 0.14, 0.00},
+	{[8]int{0, 0, 4, -2, 4, 0, 0, 0}, 0.13, 0.00},
+	{[8]int{0, 0, 2, -2, 4, 0, 0, 0}, -0.11, 0.00},
+	{[8]int{1, 0, -2, 0, -3, 0, 0, 0}, 0.11, 0.00},
+	{[8]int{1, 0, -2, 0, -1, 0, 0, 0}, 0.11, 0.00},
+}
+
+// eqeqComplementary 分点差补充项 (rad)，T 为 TT 儒略世纪数
+func eqeqComplementary(T float64) float64 {
+	args := s06Args(T)
+	return (sumS06Series(eect00Terms, args) - 0.87*T*math.Sin(args[4])) * 1e-6 * as2r
+}
+
+// cipToCIRSMatrix 由 X、Y、s 构造 GCRS → CIRS 矩阵
+// C = Rz(-(E+s)) · Ry(d) · Rz(E)，E = atan2(Y, X)，d = atan(√((X²+Y²)/(1-X²-Y²)))
+// 参考: SOFA iauC2ixys
+func cipToCIRSMatrix(x, y, s float64) [3][3]float64 {
+	r2 := x*x + y*y
+	e := 0.0
+	if r2 > 0 {
+		e = math.Atan2(y, x)
+	}
+	d := math.Atan(math.Sqrt(r2 / (1 - r2)))
+	return mul33(R3(-(e + s)), mul33(Ry(d), R3(e)))
+}
+
+// GCRS2CIRS GCRS → CIRS 旋转矩阵，jdTT 为 TT 儒略日，使用默认转换器
+func GCRS2CIRS(jdTT float64) [3][3]float64 {
+	return DefaultTransformer().GCRS2CIRS(jdTT)
+}
+
+// GCRS2CIRS GCRS → CIRS 旋转矩阵，jdTT 为 TT 儒略日
+// s 由模型 X、Y 计算，矩阵使用叠加天极偏差后的 X、Y (同 GCRF2ITRFCIO)。
+func (tr *Transformer) GCRS2CIRS(jdTT float64) [3][3]float64 {
+	x, y, dx, dy := tr.cip(jdTT)
+	return cipToCIRSMatrix(x+dx, y+dy, CIOLocator(jdTT, x, y))
+}
+
+// CIRS2TIRS CIRS → TIRS 旋转矩阵 Rz(ERA)，jdUT1 为 UT1 儒略日
+func CIRS2TIRS(jdUT1 float64) [3][3]float64 {
+	return R3(EarthRotationAngle(jdUT1))
+}
+
+// TIRS2ITRS TIRS → ITRS 极移矩阵 W，jdTT 为 TT 儒略日，xp、yp 单位角秒
+func TIRS2ITRS(jdTT, xp, yp float64) [3][3]float64 {
+	T := (jdTT - 2451545.0) / 36525.0
+	return polarMotionMatrix(xp*as2r, yp*as2r, T)
+}
+
+// GCRF2ITRFCIO 基于 CIO 的 GCRF → ITRF 旋转矩阵
+// M = W · Rz(ERA) · C(X+dX, Y+dY, s)
+// jdUT1 应已包含 eop.UT1UTC；天极偏差 dX/dY 直接叠加到 CIP 坐标上。
+func GCRF2ITRFCIO(jdUT1, jdTT float64, eop EOPRecord) [3][3]float64 {
+	x, y := DefaultTransformer().cipModel((jdTT - 2451545.0) / 36525.0)
+	return gcrf2itrfCIO(jdUT1, jdTT, eop, x, y)
+}
+
+// gcrf2itrfCIO 以给定 CIP 模型坐标 (X, Y) 计算 CIO 链 GCRF → ITRF 矩阵
+func gcrf2itrfCIO(jdUT1, jdTT float64, eop EOPRecord, x, y float64) [3][3]float64 {
+	s := CIOLocator(jdTT, x, y)
+	x += eop.DX * as2r
+	y += eop.DY * as2r
+	C := cipToCIRSMatrix(x, y, s)
+	return mul33(TIRS2ITRS(jdTT, eop.Xp, eop.Yp), mul33(CIRS2TIRS(jdUT1), C))
+}
@@ -0,0 +1,267 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// 参考值取自 SOFA t_sofa_c.c
+
+func TestEarthRotationAngleSOFA(t *testing.T) {
+	got := EarthRotationAngle(2400000.5 + 54388.0)
+	if math.Abs(got-0.4022837240028158102) > 1e-12 {
+		t.Errorf("ERA = %.16f, want 0.4022837240028158102", got)
+	}
+}
+
+func TestNutation00bSOFA(t *testing.T) {
+	T := (2400000.5 + 53736.0 - 2451545.0) / 36525.0
+	dpsi, deps := nutation00b(T)
+	if math.Abs(dpsi-(-0.9632552291148362783e-5)) > 1e-13 {
+		t.Errorf("dpsi = %.16e", dpsi)
+	}
+	if math.Abs(deps-0.4063197106621159367e-4) > 1e-13 {
+		t.Errorf("deps = %.16e", deps)
+	}
+}
+
+func TestMeanObliquitySOFA(t *testing.T) {
+	T := (2400000.5 + 54388.0 - 2451545.0) / 36525.0
+	if got := meanObliquity(T); math.Abs(got-0.4090749229387258204) > 1e-14 {
+		t.Errorf("obl06 = %.16f", got)
+	}
+}
+
+func TestFrameBiasSOFA(t *testing.T) {
+	want := [3][3]float64{
+		{0.9999999999999942498, -0.7078279744199196626e-7, 0.8056217146976134152e-7},
+		{0.7078279477857337206e-7, 0.9999999999999969484, 0.3306041454222136517e-7},
+		{-0.8056217380986972157e-7, -0.3306040883980552500e-7, 0.9999999999999962084},
+	}
+	checkMatrix(t, "B", frameBiasMatrix(), want, 1e-12)
+}
+
+func TestCIOLocatorSOFA(t *testing.T) {
+	s := CIOLocator(2400000.5+53736.0, 0.5791308486706011000e-3, 0.4020579816732961219e-4)
+	// 与 SOFA 相差不足 1 µas
+	if math.Abs(s-(-0.1220032213076463117e-7)) > 5e-12 {
+		t.Errorf("s = %.16e", s)
+	}
+}
+
+func TestCIPToCIRSMatrixSOFA(t *testing.T) {
+	got := cipToCIRSMatrix(0.5791308486706011000e-3, 0.4020579816732961219e-4, -0.1220040848472271978e-7)
+	want := [3][3]float64{
+		{0.9999998323037157138, 0.5581526349032241205e-9, -0.5791308491611263745e-3},
+		{-0.2384257057469842953e-7, 0.9999999991917468964, -0.4020579110172324363e-4},
+		{0.5791308486706011000e-3, 0.4020579816732961219e-4, 0.9999998314954627590},
+	}
+	checkMatrix(t, "C", got, want, 1e-12)
+}
+
+func TestPolarMotionMatrixSOFA(t *testing.T) {
+	// 选取 T 使 s' = -47 µas · T 等于 SOFA 算例中的值
+	sp := -0.1367174580728891460e-10
+	T := sp / (-47e-6 * as2r)
+	got := polarMotionMatrix(2.55060238e-7, 1.860359247e-6, T)
+	want := [3][3]float64{
+		{0.9999999999999674721, -0.1367174580728846989e-10, 0.2550602379999972345e-6},
+		{0.1414624947957029801e-10, 0.9999999999982695317, -0.1860359246998866389e-5},
+		{-0.2550602379741215021e-6, 0.1860359247002414021e-5, 0.9999999999982370039},
+	}
+	checkMatrix(t, "W", got, want, 1e-12)
+}
+
+func TestCIOvsEquinoxChain(t *testing.T) {
+	// 两条归算链使用同一章动模型与 IAU 2006 GMST，应在 0.05 mas 以内
+	tUTC := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	jd := juliandate(tUTC)
+	jdTT := NewEpoch(tUTC, UTC).To(TT).JD()
+	a := GCRF2ITRFCIO(jd, jdTT, EOPRecord{})
+	b := GCRF2ITRFEOP(jd, jdTT, EOPRecord{})
+	maxDiff := 0.0
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			maxDiff = math.Max(maxDiff, math.Abs(a[i][j]-b[i][j]))
+		}
+	}
+	t.Logf("CIO vs equinox: %.3f mas", maxDiff/as2r*1e3)
+	if maxDiff > 5e-5*as2r {
+		t.Errorf("CIO vs equinox chain differ by %.3f mas", maxDiff/as2r*1e3)
+	}
+}
+
+func TestParseNutation2000A(t *testing.T) {
+	n, err := LoadNutation2000A("test_data/tab5.3a_sample.txt", "test_data/tab5.3b_sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.psi) != 77+36 || len(n.eps) != 77+19 {
+		t.Fatalf("terms = %d/%d", len(n.psi), len(n.eps))
+	}
+	if n.psi[0].sinC != -17206416.1 || n.psi[0].power != 0 || n.psi[77].power != 1 {
+		t.Errorf("unexpected first term: %+v", n.psi[0])
+	}
+	if n.eps[0].cosC != 9205233.1 {
+		t.Errorf("unexpected obliquity term: %+v", n.eps[0])
+	}
+
+	// 样例表只含日月项，结果应与 IAU 2000B 相差不足 1 mas（行星项常值偏移与 J2 修正）
+	for _, T := range []float64{-0.5, 0, 0.245, 1} {
+		dpsiA, depsA := n.nutation06(T)
+		dpsiB, depsB := nutation00b(T)
+		if math.Abs(dpsiA-dpsiB) > 1e-3*as2r || math.Abs(depsA-depsB) > 1e-3*as2r {
+			t.Errorf("T=%v: 2000A-2000B = %.3f/%.3f mas", T,
+				(dpsiA-dpsiB)/as2r*1e3, (depsA-depsB)/as2r*1e3)
+		}
+	}
+
+	// 载入后完整模式改用 CIO 归算链
+	SetGMSTMode(false)
+	defer SetGMSTMode(true)
+	p := [3]float64{-2.174e6, 4.389e6, 4.077e6}
+	tUTC := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	xb, yb, zb := ECI2ECEF(p[0], p[1], p[2], tUTC)
+	SetNutation2000A(n)
+	defer SetNutation2000A(nil)
+	xa, ya, za := ECI2ECEF(p[0], p[1], p[2], tUTC)
+	if d := vecDist([3]float64{xa, ya, za}, [3]float64{xb, yb, zb}); d > 0.5 {
+		t.Errorf("2000A/CIO vs 2000B/equinox = %.3f m", d)
+	}
+	xi, yi, zi := ECEF2ECI(xa, ya, za, tUTC)
+	if r := vecDist(p, [3]float64{xi, yi, zi}); r > epsFloat {
+		t.Errorf("roundtrip error = %.3e m", r)
+	}
+}
+
+func TestTransformerCIPXY(t *testing.T) {
+	n, err := LoadNutation2000A("test_data/tab5.3a_sample.txt", "test_data/tab5.3b_sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eop, err := LoadFinals2000A("test_data/finals2000A_sample.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jdTT := 2400000.5 + 57753.0
+
+	// 包级函数使用默认转换器 (IAU 2000B)
+	b, _ := NewTransformer(Options{Model: ModelIAU2006B})
+	x0, y0 := CIPXY(jdTT)
+	if x, y := b.CIPXY(jdTT); x != x0 || y != y0 {
+		t.Errorf("default CIPXY = %v, %v, want %v, %v", x0, y0, x, y)
+	}
+	if b.GCRS2CIRS(jdTT) != GCRS2CIRS(jdTT) {
+		t.Error("GCRS2CIRS differs from the default transformer")
+	}
+
+	// 转换器的章动序列
+	a, _ := NewTransformer(Options{Model: ModelIAU2006A, Nutation: n})
+	xa, ya := a.CIPXY(jdTT)
+	T := (jdTT - 2451545.0) / 36525.0
+	dpsi, deps := n.nutation06(T)
+	m := npbMatrix(T, dpsi, deps)
+	if xa != m[2][0] || ya != m[2][1] || xa == x0 {
+		t.Errorf("2000A CIPXY = %v, %v", xa, ya)
+	}
+	SetNutation2000A(n)
+	if x, y := CIPXY(jdTT); x != xa || y != ya {
+		t.Errorf("CIPXY after SetNutation2000A = %v, %v", x, y)
+	}
+	SetNutation2000A(nil)
+
+	// EOP 天极偏差 (2016-12-31: dX = 0.125 mas, dY = -0.024 mas)
+	e, _ := NewTransformer(Options{Model: ModelIAU2006A, Nutation: n, EOP: eop})
+	xe, ye := e.CIPXY(jdTT)
+	if math.Abs(xe-xa-0.125e-3*as2r) > 1e-15 || math.Abs(ye-ya+0.024e-3*as2r) > 1e-15 {
+		t.Errorf("dX/dY = %.4f/%.4f mas", (xe-xa)/as2r*1e3, (ye-ya)/as2r*1e3)
+	}
+	want := cipToCIRSMatrix(xe, ye, CIOLocator(jdTT, xa, ya))
+	checkMatrix(t, "C", e.GCRS2CIRS(jdTT), want, 0)
+}
+
+func TestParseCIPSeries(t *testing.T) {
+	c, err := LoadCIPSeries("test_data/tab5.2a_sample.txt", "test_data/tab5.2b_sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.x) != 3 || len(c.y) != 3 || c.x[2].power != 1 || c.y[0].cosC != -1000 {
+		t.Fatalf("terms: %+v %+v", c.x, c.y)
+	}
+	// 样例系数为人工构造，只检验多项式与 t 幂次的组合
+	T := 0.24
+	fa := fundArgs2000A(T)
+	om, f2d2, m3 := fa[4], 2*fa[2]-2*fa[3]+2*fa[4], 2*fa[4]+fa[7]
+	wantX := -0.016617 + 2004.191898*T - 0.4297829*T*T - 0.19861834*T*T*T + 0.000007578*T*T*T*T + 0.0000059285*T*T*T*T*T +
+		(-1000*math.Sin(om)+20*math.Cos(om)+300*math.Sin(f2d2)-4*math.Cos(f2d2)+(50*math.Sin(m3)+6*math.Cos(m3))*T)*1e-6
+	wantY := -0.006951 - 0.025896*T - 22.4072747*T*T + 0.00190059*T*T*T + 0.001112526*T*T*T*T + 0.0000001358*T*T*T*T*T +
+		(20*math.Sin(om)-1000*math.Cos(om)-4*math.Sin(f2d2)+300*math.Cos(f2d2)+(6*math.Sin(m3)+50*math.Cos(m3))*T)*1e-6
+	x, y := c.XY(T)
+	if math.Abs(x/as2r-wantX) > 1e-9 || math.Abs(y/as2r-wantY) > 1e-9 {
+		t.Errorf("XY = %.9f/%.9f, want %.9f/%.9f", x/as2r, y/as2r, wantX, wantY)
+	}
+
+	// 转换器给出级数时 CIPXY 与 CIO 链直接使用级数
+	n, _ := LoadNutation2000A("test_data/tab5.3a_sample.txt", "test_data/tab5.3b_sample.txt")
+	tr, _ := NewTransformer(Options{Model: ModelIAU2006A, Nutation: n, CIP: c})
+	jdTT := 2451545.0 + T*36525
+	if xs, ys := tr.CIPXY(jdTT); xs != x || ys != y {
+		t.Errorf("Transformer.CIPXY = %v, %v", xs, ys)
+	}
+	SetCIPSeries(c)
+	if xs, ys := CIPXY(jdTT); xs != x || ys != y {
+		t.Errorf("CIPXY after SetCIPSeries = %v, %v", xs, ys)
+	}
+	SetCIPSeries(nil)
+	eq, _ := NewTransformer(Options{Model: ModelIAU2006B, CIP: c})
+	if xs, _ := eq.CIPXY(jdTT); xs == x {
+		t.Error("ModelIAU2006B should keep IAU 2000B")
+	}
+}
+
+// iersTables 读取 test_data/iers 下完整的 IERS Conventions 2010 表格，缺失时跳过测试
+func iersTables(t *testing.T) (*Nutation2000A, *CIPSeries) {
+	t.Helper()
+	n, err := LoadNutation2000A("test_data/iers/tab5.3a.txt", "test_data/iers/tab5.3b.txt")
+	if err != nil {
+		t.Skipf("full IAU 2000A tables not available: %v", err)
+	}
+	c, err := LoadCIPSeries("test_data/iers/tab5.2a.txt", "test_data/iers/tab5.2b.txt")
+	if err != nil {
+		t.Skipf("full CIP X/Y tables not available: %v", err)
+	}
+	if len(n.psi) != 1358 || len(n.eps) != 1056 || len(c.x) != 1600 || len(c.y) != 1275 {
+		t.Fatalf("incomplete tables: %d/%d/%d/%d terms", len(n.psi), len(n.eps), len(c.x), len(c.y))
+	}
+	return n, c
+}
+
+func TestNutation2000ASOFA(t *testing.T) {
+	n, c := iersTables(t)
+	T := (2400000.5 + 53736.0 - 2451545.0) / 36525.0
+	const uas = 1e-6 * as2r
+	// iauNut00a
+	if dpsi, deps := n.Nutation(T); math.Abs(dpsi-(-0.9630909107115518431e-5)) > uas || math.Abs(deps-0.4063239174001678710e-4) > uas {
+		t.Errorf("Nut00a = %.16e, %.16e", dpsi, deps)
+	}
+	// iauNut06a
+	if dpsi, deps := n.nutation06(T); math.Abs(dpsi-(-0.9630912025820308797e-5)) > uas || math.Abs(deps-0.4063238496887249798e-4) > uas {
+		t.Errorf("Nut06a = %.16e, %.16e", dpsi, deps)
+	}
+	// iauXy06
+	if x, y := c.XY(T); math.Abs(x-0.5791308486706010975e-3) > uas || math.Abs(y-0.4020579816732958141e-4) > uas {
+		t.Errorf("Xy06 = %.16e, %.16e", x, y)
+	}
+}
+
+func checkMatrix(t *testing.T, name string, got, want [3][3]float64, tol float64) {
+	t.Helper()
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(got[i][j]-want[i][j]) > tol {
+				t.Errorf("%s[%d][%d] = %.19f, want %.19f", name, i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"math"
 	"os"
@@ -65,51 +66,107 @@ func datenumToTime(dn float64) time.Time {
 }
 
 func main() {
+	inPath := flag.String("in", "check_input_maxcheck/test_mc_04_shortrange.json", "maxcheck 输入 JSON")
+	refPath := flag.String("ref", "check_output_maxcheck/test_mc_04_shortrange_out.json", "MATLAB maxcheck 输出 JSON")
+	tolTime := flag.Float64("tol-time", 1.0, "时间容差 (s)")
+	tolDeg := flag.Float64("tol-deg", 0.01, "经纬度容差 (°)")
+	j2 := flag.Bool("j2", false, "以 J2 数值积分修正发射点/落点")
+	flag.Parse()
+
 	// 读取测试输入
-	data, _ := os.ReadFile("check_input_maxcheck/test_mc_04_shortrange.json")
+	data, err := os.ReadFile(*inPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 	var in MaxCheckInput
-	json.Unmarshal(data, &in)
+	if err := json.Unmarshal(data, &in); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
 	tUTC := datenumToTime(in.TDatenum)
 	ell, _ := gomap3d.NewEllipsoid("wgs84")
+	station := gomap3d.Geodetic{Latitude: in.RadarLatDeg, Longitude: in.RadarLonDeg, Altitude: in.RadarHM, Ell: ell}
+	aer := gomap3d.AER{Azimuth: in.AzDeg, Elevation: in.ElDeg, SRange: in.RM, Ell: ell}
 
-	// 测站 ECEF
-	sx, sy, sz := gomap3d.Geodetic2ECEF(in.RadarLatDeg, in.RadarLonDeg, in.RadarHM, ell)
-
-	// ENU → ECEF
-	azR, elR := in.AzDeg*math.Pi/180, in.ElDeg*math.Pi/180
-	e := in.RM * math.Cos(elR) * math.Sin(azR)
-	n := in.RM * math.Cos(elR) * math.Cos(azR)
-	u := in.RM * math.Sin(elR)
-	tx, ty, tz := gomap3d.ENU2ECEF(e, n, u, in.RadarLatDeg, in.RadarLonDeg, in.RadarHM, ell)
-
-	tgx, tgy, tgz := sx+tx, sy+ty, sz+tz
+	// 目标 ECEF
+	tgt := aer.ToECEF(station)
 
 	// ECEF → ECI (新完整链)
-	xi, yi, zi := gomap3d.ECEF2ECI(tgx, tgy, tgz, tUTC)
+	eci := tgt.ToECI(tUTC)
 	// ECEF velocity → ECI velocity
-	vxi, vyi, vzi := gomap3d.ECEFVel2ECIVel(in.VxEci, in.VyEci, in.VzEci, tgx, tgy, tgz, tUTC)
+	vxi, vyi, vzi := gomap3d.ECEFVel2ECIVel(in.VxEci, in.VyEci, in.VzEci, tgt.X, tgt.Y, tgt.Z, tUTC)
 
-	fmt.Printf("Go Aero ECI position: (%.1f, %.1f, %.1f) km\n", xi/1000, yi/1000, zi/1000)
+	fmt.Printf("Go Aero ECI position: (%.1f, %.1f, %.1f) km\n", eci.X/1000, eci.Y/1000, eci.Z/1000)
 	fmt.Printf("Go Aero ECI velocity: (%.1f, %.1f, %.1f) m/s\n", vxi, vyi, vzi)
 
+	opts := &gomap3d.BallisticOptions{}
+	if *j2 {
+		opts.J2 = gomap3d.EarthJ2
+	}
+	res, err := gomap3d.EstimateBallisticAER(station, aer, vxi, vyi, vzi, tUTC, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
 	// 读取 MATLAB 参考
-	refData, _ := os.ReadFile("check_output_maxcheck/test_mc_04_shortrange_out.json")
+	refData, err := os.ReadFile(*refPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 	var ref MaxCheckRef
-	json.Unmarshal(refData, &ref)
+	if err := json.Unmarshal(refData, &ref); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	fmt.Printf("\nMATLAB maxcheck:\n")
-	fmt.Printf("  isSpaceTarget: %v\n", ref.IsSpaceTarget)
-	if ref.DtLaunchS != nil {
-		fmt.Printf("  dt_launch: %.1f s\n", *ref.DtLaunchS)
+	ok := true
+	check := func(name string, got float64, want *float64, tol float64) {
+		if want == nil {
+			if !math.IsNaN(got) {
+				fmt.Printf("  %-14s go %12.4f  matlab        null  FAIL\n", name, got)
+				ok = false
+			}
+			return
+		}
+		d := got - *want
+		if name == "launch_lon" || name == "impact_lon" {
+			d = math.Remainder(d, 360)
+		}
+		status := "ok"
+		if !(math.Abs(d) <= tol) {
+			status = "FAIL"
+			ok = false
+		}
+		fmt.Printf("  %-14s go %12.4f  matlab %12.4f  diff %10.4f  %s\n", name, got, *want, d, status)
 	}
-	if ref.DtImpactS != nil {
-		fmt.Printf("  dt_impact: %.1f s\n", *ref.DtImpactS)
+	latLon := func(g *gomap3d.Geodetic) (lat, lon float64) {
+		if g == nil {
+			return math.NaN(), math.NaN()
+		}
+		return g.Latitude, g.Longitude
 	}
-	if ref.LaunchLat != nil {
-		fmt.Printf("  launch: (%.4f, %.4f)\n", *ref.LaunchLat, *ref.LaunchLon)
+
+	fmt.Printf("\nGo vs MATLAB maxcheck (%s):\n", ref.ID)
+	status := "ok"
+	if res.Orbital != ref.IsSpaceTarget {
+		status = "FAIL"
+		ok = false
 	}
-	if ref.ImpactLat != nil {
-		fmt.Printf("  impact: (%.4f, %.4f)\n", *ref.ImpactLat, *ref.ImpactLon)
+	fmt.Printf("  %-14s go %12v  matlab %12v  %s\n", "isSpaceTarget", res.Orbital, ref.IsSpaceTarget, status)
+	check("dt_launch", res.TimeSinceLaunch, ref.DtLaunchS, *tolTime)
+	check("dt_impact", res.TimeToImpact, ref.DtImpactS, *tolTime)
+	lat, lon := latLon(res.Launch)
+	check("launch_lat", lat, ref.LaunchLat, *tolDeg)
+	check("launch_lon", lon, ref.LaunchLon, *tolDeg)
+	lat, lon = latLon(res.Impact)
+	check("impact_lat", lat, ref.ImpactLat, *tolDeg)
+	check("impact_lon", lon, ref.ImpactLon, *tolDeg)
+
+	if !ok {
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,363 @@
+package gomap3d
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// ============================================================
+// 雷达覆盖图与地形遮蔽角
+//
+// 由测站沿各方位的大地线以 TerrainStep 采样地形，地面点 (加余隙) 经 ToAERRefracted
+// 得视在仰角，沿程最大值即该距离之前的地形遮蔽角。某距离处可见目标的最低高度为:
+// 视在仰角不低于此前遮蔽角 (及 Mask) 且不低于地面加余隙的最小大地高，二分求解。
+// 覆盖多边形为给定目标高度下各方位最远可见距离的连线，内部可能含地形阴影区。
+// ============================================================
+
+// CoverageOptions 覆盖计算选项，nil 等价于零值
+type CoverageOptions struct {
+	DEM          *DEM            // 地形，nil 为椭球面 (地形高 0)
+	Refraction   RefractionModel // 折射模型，nil 为 EffectiveEarthRefraction{} (k = 4/3)
+	Mask         ElevationMask   // 最低视在仰角 (°)，nil 为不限
+	MinClearance float64         // 视线需高出地形的余隙 (m)
+	AzimuthStep  float64         // 方位步长 (°)，默认 1
+	RangeStep    float64         // 覆盖格网的距离步长 (m)，默认 1000
+	TerrainStep  float64         // 地形采样步长 (m)，默认 DEM 格距，无 DEM 时同 RangeStep
+}
+
+func (o *CoverageOptions) refraction() RefractionModel {
+	if o == nil || o.Refraction == nil {
+		return EffectiveEarthRefraction{}
+	}
+	return o.Refraction
+}
+
+func (o *CoverageOptions) azimuths() []float64 {
+	step := 1.0
+	if o != nil && o.AzimuthStep > 0 {
+		step = o.AzimuthStep
+	}
+	n := int(math.Max(1, math.Round(360/step)))
+	az := make([]float64, n)
+	for i := range az {
+		az[i] = 360 * float64(i) / float64(n)
+	}
+	return az
+}
+
+func (o *CoverageOptions) rangeStep() float64 {
+	if o == nil || o.RangeStep <= 0 {
+		return 1000
+	}
+	return o.RangeStep
+}
+
+func (o *CoverageOptions) terrainStep() float64 {
+	switch {
+	case o != nil && o.TerrainStep > 0:
+		return o.TerrainStep
+	case o != nil && o.DEM != nil:
+		return o.DEM.resolution()
+	}
+	return o.rangeStep()
+}
+
+// CoverageGrid 测站周围方位 × 距离格网上的覆盖
+type CoverageGrid struct {
+	Station     Geodetic
+	Azimuth     []float64    // 方位角 (°)
+	Range       []float64    // 沿大地线的地面距离 (m)
+	Terrain     [][]float64  // [方位][距离] 地形大地高 (m)
+	MinAltitude [][]float64  // [方位][距离] 可见目标的最低大地高 (m)，无法可见时为 +Inf
+	Horizon     *AzimuthMask // 最大距离内的地形遮蔽角 (视在仰角，°)，已计入 Mask
+	line        []*GeodesicLine
+}
+
+// coverageScan 沿单个方位的地形扫描
+type coverageScan struct {
+	station Geodetic
+	line    *GeodesicLine
+	m       RefractionModel
+	dem     *DEM
+	clr     float64
+}
+
+// ground 距测站 s (m) 处的地面点
+func (c *coverageScan) ground(s float64) (Geodetic, error) {
+	lat, lon, _ := c.line.Position(s)
+	p := Geodetic{Latitude: lat, Longitude: lon, Ell: c.station.Ell}
+	if c.dem != nil {
+		h, err := c.dem.TerrainHeight(lat, lon)
+		if err != nil {
+			return Geodetic{}, err
+		}
+		p.Altitude = h
+	}
+	return p, nil
+}
+
+// elevation p 的视在仰角 (°)，没有射线能到达时为 -90
+func (c *coverageScan) elevation(p Geodetic) float64 {
+	aer, err := p.ToAERRefracted(c.station, c.m)
+	if err != nil {
+		return -90
+	}
+	return aer.Elevation
+}
+
+// minAltitude 地面点 p 上方视在仰角不低于 el 的最低大地高
+func (c *coverageScan) minAltitude(p Geodetic, el float64) float64 {
+	visible := func(h float64) bool {
+		q := p
+		q.Altitude = h
+		return c.elevation(q) >= el
+	}
+	lo := p.Altitude + c.clr
+	if visible(lo) {
+		return lo
+	}
+	hi := lo + 1000
+	for !visible(hi) {
+		if hi-lo > 1e7 {
+			return math.Inf(1)
+		}
+		lo, hi = hi, hi+2*(hi-lo)
+	}
+	for hi-lo > 0.01 {
+		mid := (lo + hi) / 2
+		if visible(mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi
+}
+
+// sweep 扫描地形至 maxRange，返回地形遮蔽角；ranges 非空时填写各距离处的地形高与最低可见高度
+func (c *coverageScan) sweep(maxRange, step, floor float64, ranges, terrain, minAlt []float64) (float64, error) {
+	horizon := -90.0
+	k := 1
+	sample := func(s float64) error {
+		p, err := c.ground(s)
+		if err != nil {
+			return err
+		}
+		p.Altitude += c.clr
+		horizon = math.Max(horizon, c.elevation(p))
+		return nil
+	}
+	for j, r := range ranges {
+		for ; float64(k)*step < r; k++ {
+			if err := sample(float64(k) * step); err != nil {
+				return 0, err
+			}
+		}
+		p, err := c.ground(r)
+		if err != nil {
+			return 0, err
+		}
+		terrain[j] = p.Altitude
+		minAlt[j] = c.minAltitude(p, math.Max(horizon, floor))
+	}
+	for ; float64(k)*step <= maxRange; k++ {
+		if err := sample(float64(k) * step); err != nil {
+			return 0, err
+		}
+	}
+	return math.Max(horizon, floor), nil
+}
+
+// coverage 按方位扫描，ranges 为 nil 时只求遮蔽角
+func (geo *Geodetic) coverage(maxRange float64, ranges []float64, opts *CoverageOptions) (*CoverageGrid, error) {
+	if !(maxRange > 0) || math.IsInf(maxRange, 1) {
+		return nil, fmt.Errorf("coverage: invalid max range %g", maxRange)
+	}
+	if geo.Ell == nil {
+		return nil, fmt.Errorf("coverage: ellipsoid not specified")
+	}
+	c := &coverageScan{station: *geo, m: opts.refraction()}
+	var mask ElevationMask = ConstantMask(-90)
+	if opts != nil {
+		c.dem, c.clr = opts.DEM, opts.MinClearance
+		if opts.Mask != nil {
+			mask = opts.Mask
+		}
+	}
+	g := NewGeodesic(geo.Ell)
+	cg := &CoverageGrid{Station: *geo, Azimuth: opts.azimuths(), Range: ranges}
+	horizon := make([]float64, len(cg.Azimuth))
+	step := opts.terrainStep()
+	for i, az := range cg.Azimuth {
+		c.line = g.Line(geo.Latitude, geo.Longitude, az)
+		cg.line = append(cg.line, c.line)
+		terrain, minAlt := make([]float64, len(ranges)), make([]float64, len(ranges))
+		h, err := c.sweep(maxRange, step, mask.MinElevation(az), ranges, terrain, minAlt)
+		if err != nil {
+			return nil, err
+		}
+		horizon[i] = h
+		cg.Terrain, cg.MinAltitude = append(cg.Terrain, terrain), append(cg.MinAltitude, minAlt)
+	}
+	var err error
+	cg.Horizon, err = NewAzimuthMask(cg.Azimuth, horizon)
+	return cg, err
+}
+
+// HorizonMask 计算测站 maxRange (m) 内地形与地球曲率形成的遮蔽角，可直接用作过境预报的 Mask
+func (geo *Geodetic) HorizonMask(maxRange float64, opts *CoverageOptions) (*AzimuthMask, error) {
+	cg, err := geo.coverage(maxRange, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return cg.Horizon, nil
+}
+
+// Coverage 计算测站 maxRange (m) 内方位 × 距离格网上可见目标的最低高度
+func (geo *Geodetic) Coverage(maxRange float64, opts *CoverageOptions) (*CoverageGrid, error) {
+	if !(maxRange > 0) || math.IsInf(maxRange, 1) {
+		return nil, fmt.Errorf("coverage: invalid max range %g", maxRange)
+	}
+	step := opts.rangeStep()
+	ranges := make([]float64, int(math.Ceil(maxRange/step)))
+	for j := range ranges {
+		ranges[j] = math.Min(float64(j+1)*step, maxRange)
+	}
+	return geo.coverage(maxRange, ranges, opts)
+}
+
+// Ground 第 i 个方位、第 j 个距离处的地面点
+func (cg *CoverageGrid) Ground(i, j int) Geodetic {
+	lat, lon, _ := cg.line[i].Position(cg.Range[j])
+	return Geodetic{Latitude: lat, Longitude: lon, Altitude: cg.Terrain[i][j], Ell: cg.Station.Ell}
+}
+
+// Polygon 目标大地高 alt (m) 的覆盖边界，每个方位一个顶点，取最远可见距离 (格点间线性插值)，
+// 该方位无可见格点时顶点位于测站；顶点按方位顺时针排列，高度为 alt。
+func (cg *CoverageGrid) Polygon(alt float64) []Geodetic {
+	out := make([]Geodetic, len(cg.Azimuth))
+	for i, row := range cg.MinAltitude {
+		r := 0.0
+		for j := len(row) - 1; j >= 0; j-- {
+			if row[j] > alt {
+				continue
+			}
+			r = cg.Range[j]
+			if j+1 < len(row) && !math.IsInf(row[j+1], 1) {
+				r += (cg.Range[j+1] - r) * (alt - row[j]) / (row[j+1] - row[j])
+			}
+			break
+		}
+		lat, lon, _ := cg.line[i].Position(r)
+		out[i] = Geodetic{Latitude: lat, Longitude: lon, Altitude: alt, Ell: cg.Station.Ell}
+	}
+	return out
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type       string             `json:"type"`
+	Properties map[string]float64 `json:"properties"`
+	Geometry   geoJSONGeometry    `json:"geometry"`
+}
+
+type geoJSONCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// unwrapRing 将逆时针外环的经度展开为连续值 (相邻点经差取最短方向)
+// 环绕极点的外环经度累计变化 ±360°，以经线与极点纬线补成平面上的闭合环。
+func unwrapRing(poly []Geodetic) [][2]float64 {
+	ring := make([][2]float64, 0, len(poly)+2)
+	lon := angNormalize(poly[len(poly)-1].Longitude)
+	for i := len(poly) - 1; i >= 0; i-- {
+		if len(ring) > 0 {
+			d, _ := angDiff(ring[len(ring)-1][0], poly[i].Longitude)
+			lon = ring[len(ring)-1][0] + d
+		}
+		ring = append(ring, [2]float64{lon, poly[i].Latitude})
+	}
+	d, _ := angDiff(lon, ring[0][0])
+	if wind := lon + d - ring[0][0]; math.Abs(wind) > 180 {
+		// 向东环绕 (逆时针) 时内部在北，向西时在南
+		pole := math.Copysign(90, wind)
+		ring = append(ring, [2]float64{lon + d, ring[0][1]}, [2]float64{lon + d, pole}, [2]float64{ring[0][0], pole})
+	}
+	return ring
+}
+
+// clipRing Sutherland-Hodgman 算法裁剪 side·(经度 - edge) ≤ 0 的部分
+func clipRing(ring [][2]float64, edge, side float64) [][2]float64 {
+	var out [][2]float64
+	inside := func(p [2]float64) bool { return side*(p[0]-edge) <= 0 }
+	for i, cur := range ring {
+		prev := ring[(i+len(ring)-1)%len(ring)]
+		if inside(cur) != inside(prev) {
+			f := (edge - prev[0]) / (cur[0] - prev[0])
+			out = append(out, [2]float64{edge, prev[1] + f*(cur[1]-prev[1])})
+		}
+		if inside(cur) {
+			out = append(out, cur)
+		}
+	}
+	return out
+}
+
+// splitRing 按 ±180° 经线切分展开后的外环，各部分经度平移回 [-180°, 180°] 并首尾闭合
+func splitRing(ring [][2]float64) [][][][2]float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range ring {
+		lo, hi = math.Min(lo, p[0]), math.Max(hi, p[0])
+	}
+	parts := [][][][2]float64{}
+	for k := math.Ceil((lo - 180) / 360); 360*k-180 < hi; k++ {
+		part := clipRing(clipRing(ring, 360*k+180, 1), 360*k-180, -1)
+		out := make([][2]float64, 0, len(part)+1)
+		for _, p := range part {
+			p[0] -= 360 * k
+			if n := len(out); n == 0 || out[n-1] != p {
+				out = append(out, p)
+			}
+		}
+		if len(out) > 1 && out[0] == out[len(out)-1] {
+			out = out[:len(out)-1]
+		}
+		// 只在切分线上接触的退化部分面积为零
+		area := 0.0
+		for i, p := range out {
+			q := out[(i+1)%len(out)]
+			area += p[0]*q[1] - q[0]*p[1]
+		}
+		if len(out) < 3 || math.Abs(area) < 1e-12 {
+			continue
+		}
+		parts = append(parts, [][][2]float64{append(out, out[0])})
+	}
+	return parts
+}
+
+// GeoJSON 各目标高度 (m) 的覆盖多边形，输出 GeoJSON FeatureCollection (RFC 7946)
+// 每个 Feature 的 properties.altitude 为目标高度，外环逆时针并首尾闭合，坐标为 [经度, 纬度]。
+// 跨越 ±180° 经线的多边形切分为 MultiPolygon；环绕极点的多边形沿经线与极点纬线闭合。
+func (cg *CoverageGrid) GeoJSON(altitudes ...float64) ([]byte, error) {
+	fc := geoJSONCollection{Type: "FeatureCollection", Features: []geoJSONFeature{}}
+	for _, alt := range altitudes {
+		parts := splitRing(unwrapRing(cg.Polygon(alt)))
+		geom := geoJSONGeometry{Type: "MultiPolygon", Coordinates: parts}
+		if len(parts) == 1 {
+			geom = geoJSONGeometry{Type: "Polygon", Coordinates: parts[0]}
+		}
+		fc.Features = append(fc.Features, geoJSONFeature{
+			Type:       "Feature",
+			Properties: map[string]float64{"altitude": alt},
+			Geometry:   geom,
+		})
+	}
+	return json.Marshal(fc)
+}
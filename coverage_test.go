@@ -0,0 +1,313 @@
+package gomap3d
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestCoverageSmoothEarth(t *testing.T) {
+	venus, _ := NewEllipsoid("venus")
+	R := venus.SemimajorAxis
+	k := DefaultKFactor
+	radar := Geodetic{Latitude: 10, Longitude: 20, Altitude: 100, Ell: venus}
+	opts := &CoverageOptions{AzimuthStep: 30, TerrainStep: 200}
+
+	// 球面上的雷达地平: 遮蔽角为等效地球上的俯角
+	mask, err := radar.HorizonMask(100e3, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dip := -math.Acos(k*R/(k*R+radar.Altitude)) * 180 / math.Pi
+	for _, az := range []float64{0, 45, 200} {
+		if el := mask.MinElevation(az); math.Abs(el-dip) > 1e-3 {
+			t.Errorf("az %g: horizon %.5f°, want %.5f°", az, el, dip)
+		}
+	}
+
+	cg, err := radar.Coverage(250e3, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cg.Azimuth) != 12 || len(cg.Range) != 250 || cg.Range[249] != 250e3 {
+		t.Fatalf("grid %d×%d", len(cg.Azimuth), len(cg.Range))
+	}
+	// 地平内地面可见，地平外最低高度 ≈ kR 球面上切线的高度
+	horizon := k * R * math.Acos(k*R/(k*R+radar.Altitude))
+	for j, r := range cg.Range {
+		var want float64
+		if r > horizon {
+			want = k*R/math.Cos((r-horizon)/(k*R)) - k*R
+		}
+		if h := cg.MinAltitude[3][j]; math.Abs(h-want) > 0.01*want+0.5 {
+			t.Errorf("range %g: min altitude %.2f, want %.2f", r, h, want)
+		}
+	}
+
+	// 覆盖多边形半径 = 两者的等效地球地平距离之和
+	alt := 1000.0
+	want := horizon + k*R*math.Acos(k*R/(k*R+alt))
+	poly := cg.Polygon(alt)
+	for i, p := range poly {
+		s, azi, _ := radar.GeodesicInverse(p)
+		if math.Abs(s-want) > 200 || math.Abs(angNormalize(azi-cg.Azimuth[i])) > 1e-6 || p.Altitude != alt {
+			t.Errorf("vertex %d: %.1f m at %g°, want %.1f m", i, s, azi, want)
+		}
+	}
+	if gp, err := NewGeodesicPolygon(poly); err != nil || math.Abs(gp.Area()) > math.Pi*want*want {
+		t.Errorf("polygon area %v %v", gp, err)
+	}
+	// 高于最大距离处的最低高度时覆盖到格网边缘
+	for _, p := range cg.Polygon(1e5) {
+		if s, _, _ := radar.GeodesicInverse(p); math.Abs(s-250e3) > 1e-6 {
+			t.Errorf("full coverage vertex at %.3f m", s)
+		}
+	}
+
+	// 最低仰角遮蔽
+	masked, err := radar.HorizonMask(100e3, &CoverageOptions{AzimuthStep: 90, Mask: ConstantMask(2)})
+	if err != nil || masked.MinElevation(10) != 2 {
+		t.Errorf("mask: %+v %v", masked, err)
+	}
+	for _, r := range []float64{0, -1e3, math.NaN(), math.Inf(1)} {
+		if _, err := radar.Coverage(r, nil); err == nil {
+			t.Errorf("expected error for range %g", r)
+		}
+		if _, err := radar.HorizonMask(r, nil); err == nil {
+			t.Errorf("expected horizon mask error for range %g", r)
+		}
+	}
+}
+
+func TestCoverageTerrain(t *testing.T) {
+	d, err := OpenDEM("test_data/dem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SeaLevel = true
+	wgs, _ := NewEllipsoid("wgs84")
+	// 测站位于山脊 (111.5°E) 以西约 14 km 的平地上
+	radar := Geodetic{Latitude: 30.25, Longitude: 111.35, Altitude: 10, Ell: wgs}
+	opts := &CoverageOptions{DEM: d, AzimuthStep: 90, RangeStep: 500, TerrainStep: 50}
+	cg, err := radar.Coverage(25e3, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 东向遮蔽角为山脊顶的视在仰角
+	top := Geodetic{Latitude: 30.25, Longitude: 111.5, Altitude: 2000, Ell: wgs}
+	aer, _ := top.ToAERRefracted(radar, EffectiveEarthRefraction{})
+	if el := cg.Horizon.MinElevation(90); math.Abs(el-aer.Elevation) > 0.02 {
+		t.Errorf("ridge horizon %.4f°, want %.4f°", el, aer.Elevation)
+	}
+	east := 1
+	for j, r := range cg.Range {
+		g := cg.Ground(east, j)
+		if math.Abs(cg.Terrain[east][j]-math.Max(0, 2000-math.Abs(g.Longitude-111.5)*20000)) > 1e-3 {
+			t.Errorf("terrain at %g m: %g", r, cg.Terrain[east][j])
+		}
+		switch {
+		case r <= 12e3:
+			// 山脊前坡可见至地面
+			if cg.MinAltitude[east][j] != cg.Terrain[east][j] {
+				t.Errorf("front slope %g m: %g vs terrain %g", r, cg.MinAltitude[east][j], cg.Terrain[east][j])
+			}
+		case r >= 16e3:
+			// 山脊后为阴影区，最低可见目标恰在遮蔽角上
+			g.Altitude = cg.MinAltitude[east][j]
+			if e, _ := g.ToAERRefracted(radar, EffectiveEarthRefraction{}); g.Altitude < 2000 || math.Abs(e.Elevation-aer.Elevation) > 0.02 {
+				t.Errorf("shadow %g m: %.1f m at %.4f°", r, g.Altitude, e.Elevation)
+			}
+		}
+	}
+	// 西向平地
+	for j, r := range cg.Range {
+		if r < 10e3 && cg.MinAltitude[3][j] != 0 {
+			t.Errorf("west %g m: %g", r, cg.MinAltitude[3][j])
+		}
+	}
+
+	// 余隙要求抬高最低高度
+	clr, err := radar.Coverage(5e3, &CoverageOptions{DEM: d, AzimuthStep: 90, MinClearance: 30})
+	if err != nil || clr.MinAltitude[3][0] != 30 {
+		t.Errorf("clearance: %v %v", clr.MinAltitude, err)
+	}
+
+	// 低空多边形被山脊截断，高空越过山脊
+	low, high := cg.Polygon(500), cg.Polygon(1e4)
+	sLow, _, _ := radar.GeodesicInverse(low[east])
+	sHigh, _, _ := radar.GeodesicInverse(high[east])
+	if sLow > 14.5e3 || sHigh < 25e3-1e-6 {
+		t.Errorf("east boundary: 500 m at %.0f m, 10 km at %.0f m", sLow, sHigh)
+	}
+
+	// GeoJSON
+	b, err := cg.GeoJSON(500, 1e4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc struct {
+		Type     string
+		Features []struct {
+			Properties map[string]float64
+			Geometry   struct {
+				Type        string
+				Coordinates [][][]float64
+			}
+		}
+	}
+	if err := json.Unmarshal(b, &fc); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 2 || fc.Features[1].Properties["altitude"] != 1e4 {
+		t.Fatalf("geojson: %s", b)
+	}
+	ring := fc.Features[0].Geometry.Coordinates[0]
+	if fc.Features[0].Geometry.Type != "Polygon" || len(ring) != 5 || ring[0][0] != ring[4][0] || ring[0][1] != ring[4][1] {
+		t.Fatalf("ring: %v", ring)
+	}
+	// 逆时针: 平面鞋带公式面积为正
+	var area float64
+	for i := 0; i+1 < len(ring); i++ {
+		area += ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+	}
+	if area <= 0 || math.Abs(ring[0][0]-low[3].Longitude) > 1e-12 {
+		t.Errorf("ring orientation: %v", ring)
+	}
+
+	// 覆盖区离开 DEM 且未设 SeaLevel
+	d.SeaLevel = false
+	if _, err := radar.HorizonMask(50e3, opts); err == nil {
+		t.Error("expected error outside the DEM")
+	}
+}
+
+// ringArea 平面鞋带公式面积 (度²)，逆时针为正
+func ringArea(ring [][]float64) float64 {
+	var area float64
+	for i := 0; i+1 < len(ring); i++ {
+		area += ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+	}
+	return area / 2
+}
+
+// coverageParts 解析 GeoJSON 中第一个 Feature 的各部分外环
+func coverageParts(t *testing.T, b []byte) (string, [][][]float64) {
+	t.Helper()
+	var fc struct {
+		Features []struct {
+			Geometry struct {
+				Type        string
+				Coordinates json.RawMessage
+			}
+		}
+	}
+	if err := json.Unmarshal(b, &fc); err != nil || len(fc.Features) != 1 {
+		t.Fatalf("geojson %s: %v", b, err)
+	}
+	g := fc.Features[0].Geometry
+	var polys [][][][]float64
+	if g.Type == "Polygon" {
+		var poly [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &poly); err != nil {
+			t.Fatal(err)
+		}
+		polys = append(polys, poly)
+	} else if err := json.Unmarshal(g.Coordinates, &polys); err != nil {
+		t.Fatal(err)
+	}
+	var rings [][][]float64
+	for _, poly := range polys {
+		ring := poly[0]
+		first, last := ring[0], ring[len(ring)-1]
+		if len(poly) != 1 || first[0] != last[0] || first[1] != last[1] || ringArea(ring) <= 0 {
+			t.Errorf("ring not closed or not counter-clockwise: %v", ring)
+		}
+		for _, c := range ring {
+			if math.Abs(c[0]) > 180 || math.Abs(c[1]) > 90 {
+				t.Errorf("coordinate out of range: %v", c)
+			}
+		}
+		rings = append(rings, ring)
+	}
+	return g.Type, rings
+}
+
+func TestCoverageGeoJSONAntimeridian(t *testing.T) {
+	venus, _ := NewEllipsoid("venus")
+	opts := &CoverageOptions{AzimuthStep: 10, RangeStep: 10e3, TerrainStep: 10e3}
+	lonSpan := func(ring [][]float64) (lo, hi float64) {
+		lo, hi = 180, -180
+		for _, c := range ring {
+			lo, hi = math.Min(lo, c[0]), math.Max(hi, c[0])
+		}
+		return
+	}
+
+	// 测站位于 179.5°E，覆盖区跨越 180° 经线
+	radar := Geodetic{Latitude: 10, Longitude: 179.5, Altitude: 100, Ell: venus}
+	cg, err := radar.Coverage(200e3, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := cg.GeoJSON(5000)
+	typ, rings := coverageParts(t, b)
+	if typ != "MultiPolygon" || len(rings) != 2 {
+		t.Fatalf("geometry %s with %d parts", typ, len(rings))
+	}
+	east, west := rings[0], rings[1]
+	if _, hi := lonSpan(east); hi != 180 {
+		east, west = west, east
+	}
+	if lo, hi := lonSpan(east); hi != 180 || lo > 179.5 {
+		t.Errorf("east part spans %g..%g", lo, hi)
+	}
+	if lo, hi := lonSpan(west); lo != -180 || hi < -179.5 || hi > -170 {
+		t.Errorf("west part spans %g..%g", lo, hi)
+	}
+	// 两部分面积之和等于未切分的展开多边形
+	var whole [][]float64
+	for _, p := range unwrapRing(cg.Polygon(5000)) {
+		whole = append(whole, []float64{p[0], p[1]})
+	}
+	whole = append(whole, whole[0])
+	if a := ringArea(east) + ringArea(west); math.Abs(a-ringArea(whole)) > 1e-9*a {
+		t.Errorf("area %g, want %g", a, ringArea(whole))
+	}
+
+	// 不跨越时仍为单个 Polygon
+	near := Geodetic{Latitude: 10, Longitude: 170, Altitude: 100, Ell: venus}
+	cg, _ = near.Coverage(200e3, opts)
+	b, _ = cg.GeoJSON(5000)
+	if typ, rings := coverageParts(t, b); typ != "Polygon" || len(rings) != 1 {
+		t.Errorf("geometry %s with %d parts", typ, len(rings))
+	}
+
+	// 覆盖区包含极点: 沿极点纬线闭合，覆盖全部经度
+	for _, lat := range []float64{89.5, -89.5} {
+		polar := Geodetic{Latitude: lat, Longitude: 179.5, Altitude: 100, Ell: venus}
+		cg, err := polar.Coverage(200e3, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := cg.GeoJSON(5000)
+		_, rings := coverageParts(t, b)
+		lo, hi, area := 180.0, -180.0, 0.0
+		for _, ring := range rings {
+			l, h := lonSpan(ring)
+			lo, hi = math.Min(lo, l), math.Max(hi, h)
+			area += ringArea(ring)
+			pole := false
+			for _, c := range ring {
+				pole = pole || c[1] == math.Copysign(90, lat)
+			}
+			if !pole {
+				t.Errorf("lat %g: part does not reach the pole: %v", lat, ring)
+			}
+		}
+		// 覆盖半径 200 km 约 1.9°，平面面积约 360° × 1.9°
+		if lo != -180 || hi != 180 || area < 360*1.5 || area > 360*2.5 {
+			t.Errorf("lat %g: parts span %g..%g, area %g", lat, lo, hi, area)
+		}
+	}
+}
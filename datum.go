@@ -0,0 +1,219 @@
+package gomap3d
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================================
+// 大地基准与基准转换
+//
+// 各基准给出转换到 WGS84 的参数，基准间转换经 WGS84 中转。
+// CGCS2000 与 WGS84 (G1762) 差异在厘米级，视为恒等；
+// 厘米级以下的历元相关转换见 ITRF 十四参数转换。
+// ============================================================
+
+// HelmertConvention 七参数旋转角的符号约定
+type HelmertConvention int
+
+const (
+	// PositionVector 位置矢量约定 (EPSG 9606，IERS/ITRF 惯例)
+	PositionVector HelmertConvention = iota
+	// CoordinateFrame 坐标框架约定 (EPSG 9607，布尔莎模型)，旋转角与位置矢量约定符号相反
+	CoordinateFrame
+)
+
+// Helmert 七参数相似变换 (小角度线性化)
+// X' = T + (1 + S·10⁻⁶)·R·X，位置矢量约定下 R = [[1, -RZ, RY], [RZ, 1, -RX], [-RY, RX, 1]]
+type Helmert struct {
+	TX, TY, TZ float64 // 平移 (m)
+	RX, RY, RZ float64 // 旋转 (角秒)
+	S          float64 // 尺度 (ppm)
+	Convention HelmertConvention
+}
+
+// matrix 位置矢量约定下的旋转尺度矩阵 (1 + s)·R
+func (h Helmert) matrix() [3][3]float64 {
+	rx, ry, rz := h.RX*as2r, h.RY*as2r, h.RZ*as2r
+	if h.Convention == CoordinateFrame {
+		rx, ry, rz = -rx, -ry, -rz
+	}
+	k := 1 + h.S*1e-6
+	return [3][3]float64{
+		{k, -k * rz, k * ry},
+		{k * rz, k, -k * rx},
+		{-k * ry, k * rx, k},
+	}
+}
+
+// Transform 正向变换地心直角坐标 (m)
+func (h Helmert) Transform(x, y, z float64) (float64, float64, float64) {
+	v := multiplyMatrixVector(h.matrix(), [3]float64{x, y, z})
+	return v[0] + h.TX, v[1] + h.TY, v[2] + h.TZ
+}
+
+// InverseTransform 反向变换，对线性化矩阵严格求逆 (而非参数取反的近似)
+func (h Helmert) InverseTransform(x, y, z float64) (float64, float64, float64) {
+	v := multiplyMatrixVector(inv33(h.matrix()), [3]float64{x - h.TX, y - h.TY, z - h.TZ})
+	return v[0], v[1], v[2]
+}
+
+// inv33 3×3 矩阵求逆
+func inv33(m [3][3]float64) [3][3]float64 {
+	var c [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			i1, i2 := (i+1)%3, (i+2)%3
+			j1, j2 := (j+1)%3, (j+2)%3
+			// 伴随矩阵 (转置的余子式)
+			c[j][i] = m[i1][j1]*m[i2][j2] - m[i1][j2]*m[i2][j1]
+		}
+	}
+	det := m[0][0]*c[0][0] + m[0][1]*c[1][0] + m[0][2]*c[2][0]
+	for i := range c {
+		for j := range c[i] {
+			c[i][j] /= det
+		}
+	}
+	return c
+}
+
+// Molodensky 由平移参数 (dx, dy, dz) (m) 将椭球 from 上的大地坐标直接转换到椭球 to
+// abridged 为真时使用简化公式 (忽略高程项与二阶项)，否则使用标准公式；经纬度单位为 °。
+func Molodensky(lat, lon, alt float64, from, to *Ellipsoid, dx, dy, dz float64, abridged bool) (lat2, lon2, alt2 float64) {
+	phi := lat * math.Pi / 180
+	lam := lon * math.Pi / 180
+	sp, cp := math.Sincos(phi)
+	sl, cl := math.Sincos(lam)
+
+	a, f := from.SemimajorAxis, from.Flattening
+	e2 := f * (2 - f)
+	da := to.SemimajorAxis - a
+	df := to.Flattening - f
+
+	w := math.Sqrt(1 - e2*sp*sp)
+	n := a / w                      // 卯酉圈曲率半径
+	m := a * (1 - e2) / (w * w * w) // 子午圈曲率半径
+
+	var dphi, dlam, dh float64
+	if abridged {
+		k := a*df + f*da
+		dphi = (-dx*sp*cl - dy*sp*sl + dz*cp + k*2*sp*cp) / m
+		dlam = (-dx*sl + dy*cl) / (n * cp)
+		dh = dx*cp*cl + dy*cp*sl + dz*sp + k*sp*sp - da
+	} else {
+		b := a * (1 - f)
+		dphi = (-dx*sp*cl - dy*sp*sl + dz*cp +
+			da*n*e2*sp*cp/a + df*(m*a/b+n*b/a)*sp*cp) / (m + alt)
+		dlam = (-dx*sl + dy*cl) / ((n + alt) * cp)
+		dh = dx*cp*cl + dy*cp*sl + dz*sp - da*a/n + df*b/a*n*sp*sp
+	}
+	return lat + dphi*180/math.Pi, lon + dlam*180/math.Pi, alt + dh
+}
+
+// DatumMethod 基准到 WGS84 的转换方法
+type DatumMethod int
+
+const (
+	// DatumHelmert 七参数 Helmert 变换 (仅平移时即三参数地心平移)
+	DatumHelmert DatumMethod = iota
+	// DatumMolodensky 标准 Molodensky 公式，仅使用 TX/TY/TZ
+	DatumMolodensky
+	// DatumMolodenskyAbridged 简化 Molodensky 公式，仅使用 TX/TY/TZ
+	DatumMolodenskyAbridged
+)
+
+// Datum 大地基准：参考椭球与转换到 WGS84 的参数
+type Datum struct {
+	Name    string
+	Ell     *Ellipsoid
+	ToWGS84 Helmert
+	Method  DatumMethod
+}
+
+// datumDef 内置基准定义
+type datumDef struct {
+	Name      string
+	Ellipsoid string
+	ToWGS84   Helmert
+	Method    DatumMethod
+}
+
+var datums = map[string]datumDef{
+	"wgs84":    {"WGS 84", "wgs84", Helmert{}, DatumHelmert},
+	"cgcs2000": {"CGCS2000", "cgcs2000", Helmert{}, DatumHelmert},
+	// NIMA TR8350.2
+	"wgs72": {"WGS 72", "wgs72", Helmert{TZ: 4.5, RZ: 0.554, S: 0.2263}, DatumHelmert},
+	// EPSG:1918 Beijing 1954 to WGS 84 (1)，精度约数米，宜以本地参数替换
+	"beijing54": {"Beijing 1954", "krass", Helmert{TX: 15.8, TY: -154.4, TZ: -82.3}, DatumHelmert},
+	// EPSG:1314 OSGB 1936 to WGS 84 (6)
+	"osgb36": {"OSGB 1936", "airy", Helmert{TX: 446.448, TY: -125.157, TZ: 542.06,
+		RX: 0.15, RY: 0.247, RZ: 0.842, S: -20.489}, DatumHelmert},
+	// EPSG:1777 DHDN to WGS 84 (2)
+	"dhdn": {"DHDN", "bessel", Helmert{TX: 598.1, TY: 73.7, TZ: 418.2,
+		RX: 0.202, RY: 0.045, RZ: -2.455, S: 6.7, Convention: CoordinateFrame}, DatumHelmert},
+	// 以下为 NIMA TR8350.2 区域平均三参数，按惯例以 Molodensky 公式施加
+	"ed50": {"ED50", "intl", Helmert{TX: -87, TY: -98, TZ: -121}, DatumMolodensky},
+	// 美国本土
+	"nad27": {"NAD27", "clrk66", Helmert{TX: -8, TY: 160, TZ: 176}, DatumMolodensky},
+	"tokyo": {"Tokyo", "bessel", Helmert{TX: -148, TY: 507, TZ: 685}, DatumMolodensky},
+}
+
+// NewDatum 通过名称创建内置基准
+// 西安 80 等无公开全国参数的基准，请以 Datum{Ell: xian80 椭球, ToWGS84: 本地七参数} 自行构造。
+func NewDatum(name string) (*Datum, error) {
+	d, ok := datums[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown datum: %s", name)
+	}
+	ell, err := NewEllipsoid(d.Ellipsoid)
+	if err != nil {
+		return nil, err
+	}
+	return &Datum{Name: d.Name, Ell: ell, ToWGS84: d.ToWGS84, Method: d.Method}, nil
+}
+
+// wgs84Ellipsoid 中转基准椭球
+var wgs84Ellipsoid, _ = NewEllipsoid("wgs84")
+
+// toWGS84 本基准地心坐标 → WGS84 地心坐标
+func (d *Datum) toWGS84(x, y, z float64) (float64, float64, float64) {
+	if d.Method == DatumHelmert {
+		return d.ToWGS84.Transform(x, y, z)
+	}
+	lat, lon, alt := ECEF2Geodetic(x, y, z, d.Ell)
+	lat, lon, alt = Molodensky(lat, lon, alt, d.Ell, wgs84Ellipsoid,
+		d.ToWGS84.TX, d.ToWGS84.TY, d.ToWGS84.TZ, d.Method == DatumMolodenskyAbridged)
+	return Geodetic2ECEF(lat, lon, alt, wgs84Ellipsoid)
+}
+
+// fromWGS84 WGS84 地心坐标 → 本基准地心坐标
+func (d *Datum) fromWGS84(x, y, z float64) (float64, float64, float64) {
+	if d.Method == DatumHelmert {
+		return d.ToWGS84.InverseTransform(x, y, z)
+	}
+	lat, lon, alt := ECEF2Geodetic(x, y, z, wgs84Ellipsoid)
+	lat, lon, alt = Molodensky(lat, lon, alt, wgs84Ellipsoid, d.Ell,
+		-d.ToWGS84.TX, -d.ToWGS84.TY, -d.ToWGS84.TZ, d.Method == DatumMolodenskyAbridged)
+	return Geodetic2ECEF(lat, lon, alt, d.Ell)
+}
+
+// DatumTransform 将基准 from 下的地心直角坐标转换到基准 to (经 WGS84 中转)
+func DatumTransform(x, y, z float64, from, to *Datum) (float64, float64, float64) {
+	x, y, z = from.toWGS84(x, y, z)
+	return to.fromWGS84(x, y, z)
+}
+
+// TransformDatum 将基准 from 下的地心直角坐标转换到基准 to，结果椭球为 to.Ell
+func (ecef ECEF) TransformDatum(from, to *Datum) ECEF {
+	x, y, z := DatumTransform(ecef.X, ecef.Y, ecef.Z, from, to)
+	return ECEF{X: x, Y: y, Z: z, Ell: to.Ell}
+}
+
+// TransformDatum 将基准 from 下的大地坐标转换到基准 to，输入按 from.Ell 解释，结果椭球为 to.Ell
+func (geo *Geodetic) TransformDatum(from, to *Datum) Geodetic {
+	x, y, z := Geodetic2ECEF(geo.Latitude, geo.Longitude, geo.Altitude, from.Ell)
+	x, y, z = DatumTransform(x, y, z, from, to)
+	lat, lon, alt := ECEF2Geodetic(x, y, z, to.Ell)
+	return Geodetic{Latitude: lat, Longitude: lon, Altitude: alt, Ell: to.Ell}
+}
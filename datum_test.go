@@ -0,0 +1,105 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHelmert(t *testing.T) {
+	// EPSG Guidance Note 7-2 算例: WGS 72 → WGS 84
+	src := [3]float64{3657660.66, 255768.55, 5201382.11}
+	want := [3]float64{3657660.78, 255778.43, 5201387.75}
+	pv := Helmert{TZ: 4.5, RZ: 0.554, S: 0.219}
+	cf := Helmert{TZ: 4.5, RZ: -0.554, S: 0.219, Convention: CoordinateFrame}
+	for _, h := range []Helmert{pv, cf} {
+		x, y, z := h.Transform(src[0], src[1], src[2])
+		if d := vecDist([3]float64{x, y, z}, want); d > 0.01 {
+			t.Errorf("convention %d: %.3f %.3f %.3f, error %.3f m", h.Convention, x, y, z, d)
+		}
+		// 严格逆变换
+		xi, yi, zi := h.InverseTransform(x, y, z)
+		if d := vecDist([3]float64{xi, yi, zi}, src); d > 1e-8 {
+			t.Errorf("convention %d: inverse error %.3e m", h.Convention, d)
+		}
+	}
+
+	// 大旋转与尺度下逆变换仍为严格逆
+	h := Helmert{TX: 446.448, TY: -125.157, TZ: 542.06, RX: 0.15, RY: 0.247, RZ: 0.842, S: -20.489}
+	x, y, z := h.InverseTransform(h.Transform(src[0], src[1], src[2]))
+	if d := vecDist([3]float64{x, y, z}, src); d > 1e-8 {
+		t.Errorf("OSGB36 round trip error %.3e m", d)
+	}
+}
+
+func TestMolodensky(t *testing.T) {
+	// EPSG Guidance Note 7-2 算例: WGS 84 → ED50
+	wgs, _ := NewEllipsoid("wgs84")
+	intl, _ := NewEllipsoid("intl")
+	dms := func(d, m, s float64) float64 { return d + m/60 + s/3600 }
+	lat, lon := dms(53, 48, 33.82), dms(2, 7, 46.38)
+	cases := []struct {
+		abridged       bool
+		lat, lon, h    float64
+		tolAng, tolAlt float64 // 角秒, m
+	}{
+		{false, dms(53, 48, 36.565), dms(2, 7, 51.477), 28.02, 1e-3, 5e-3},
+		{true, dms(53, 48, 36.563), dms(2, 7, 51.477), 28.091, 1e-3, 5e-3},
+	}
+	for _, c := range cases {
+		la, lo, h := Molodensky(lat, lon, 73, wgs, intl, 84.87, 96.49, 116.95, c.abridged)
+		if math.Abs(la-c.lat)*3600 > c.tolAng || math.Abs(lo-c.lon)*3600 > c.tolAng || math.Abs(h-c.h) > c.tolAlt {
+			t.Errorf("abridged=%v: %.9f %.9f %.4f", c.abridged, la, lo, h)
+		}
+	}
+}
+
+func TestDatumTransform(t *testing.T) {
+	wgs, _ := NewDatum("wgs84")
+	cgcs, _ := NewDatum("cgcs2000")
+	bj54, _ := NewDatum("beijing54")
+	osgb, _ := NewDatum("osgb36")
+	ed50, _ := NewDatum("ed50")
+	if _, err := NewDatum("unknown"); err == nil {
+		t.Error("expected error for unknown datum")
+	}
+
+	p := Geodetic{Latitude: 39.9, Longitude: 116.4, Altitude: 50}
+	for _, d := range []*Datum{cgcs, bj54, osgb, ed50} {
+		// 往返
+		q := p.TransformDatum(wgs, d)
+		if q.Ell != d.Ell {
+			t.Errorf("%s: result ellipsoid not set", d.Name)
+		}
+		r := q.TransformDatum(d, wgs)
+		tol := 1e-9
+		if d.Method != DatumHelmert {
+			tol = 1e-6 // Molodensky 正反公式非严格互逆
+		}
+		if math.Abs(r.Latitude-p.Latitude) > tol || math.Abs(r.Longitude-p.Longitude) > tol ||
+			math.Abs(r.Altitude-p.Altitude) > tol*1e5 {
+			t.Errorf("%s: round trip %+v", d.Name, r)
+		}
+	}
+
+	// 三参数 Helmert 与直接地心平移一致
+	w := (&Geodetic{Latitude: p.Latitude, Longitude: p.Longitude, Altitude: p.Altitude, Ell: wgs.Ell}).ToECEF()
+	b := w.TransformDatum(wgs, bj54)
+	want := [3]float64{w.X - 15.8, w.Y + 154.4, w.Z + 82.3}
+	if d := vecDist([3]float64{b.X, b.Y, b.Z}, want); d > 1e-6 || b.Ell != bj54.Ell {
+		t.Errorf("Beijing 54 translation error %.3e m", d)
+	}
+
+	// Molodensky 基准与严格地心平移相差厘米级
+	e := p.TransformDatum(wgs, ed50)
+	x, y, z := Geodetic2ECEF(p.Latitude, p.Longitude, p.Altitude, wgs.Ell)
+	la, lo, h := ECEF2Geodetic(x+87, y+98, z+121, ed50.Ell)
+	if dl := math.Hypot(e.Latitude-la, e.Longitude-lo) * 111e3; dl > 0.05 || math.Abs(e.Altitude-h) > 0.05 {
+		t.Errorf("ED50 Molodensky vs geocentric: %.3f m horizontal, %.3f m vertical", dl, e.Altitude-h)
+	}
+
+	// CGCS2000 与 WGS84 基准恒等，坐标差仅来自椭球短半轴 0.1 mm 差异
+	c := p.TransformDatum(wgs, cgcs)
+	if math.Abs(c.Latitude-p.Latitude) > 1e-8 || math.Abs(c.Altitude-p.Altitude) > 1e-3 {
+		t.Errorf("CGCS2000: %+v", c)
+	}
+}
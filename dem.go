@@ -0,0 +1,525 @@
+package gomap3d
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ============================================================
+// 数字高程模型 (DEM)
+//
+// 从本地目录读取地形瓦片，按经纬度查找所在瓦片并插值，瓦片在首次查询时读入内存。支持:
+//   - SRTM HGT (NddEddd.hgt)，16 位大端有符号整数，自北向南逐行，-32768 为空洞；
+//     瓦片覆盖 1°×1°，边界行列与相邻瓦片重合 (SRTM1 3601², SRTM3 1201²，亦接受其他方形尺寸)
+//   - 未压缩的条带式 GeoTIFF，地理坐标 (经纬度)，单波段 int16/uint16/int32/uint32/float32/float64，
+//     ModelPixelScale + ModelTiepoint 定位，支持 PixelIsArea / PixelIsPoint 与 GDAL NoData
+// DEM 高程通常为正高 (SRTM 为 EGM96)，指定 Geoid 时加大地水准面差距得椭球高。
+// ============================================================
+
+// demTile DEM 瓦片，格网在首次查询时读取
+type demTile struct {
+	path                     string
+	south, north, west, east float64
+	spacing                  float64 // 纬向格距 (°)
+	load                     func() (*grid, error)
+
+	once sync.Once
+	g    *grid
+	err  error
+}
+
+// DEM 由本地 SRTM HGT 与 GeoTIFF 瓦片组成的数字高程模型，查询可并发
+type DEM struct {
+	Method   Interpolation // 插值方法，默认双线性
+	Geoid    *Geoid        // 非 nil 时 DEM 高程视为正高，加大地水准面差距得椭球高
+	SeaLevel bool          // 无瓦片覆盖处按高程 0 (海面) 处理，否则返回错误
+	tiles    []*demTile
+}
+
+// OpenDEM 递归扫描目录 dir 中的 .hgt、.tif、.tiff 瓦片
+func OpenDEM(dir string) (*DEM, error) {
+	d := &DEM{}
+	err := filepath.WalkDir(dir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil || e.IsDir() {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".hgt", ".tif", ".tiff":
+			return d.AddFile(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(d.tiles) == 0 {
+		return nil, fmt.Errorf("dem: no .hgt or .tif tiles in %s", dir)
+	}
+	return d, nil
+}
+
+// AddFile 加入单个瓦片文件，仅读取文件头；不可与查询并发调用
+func (d *DEM) AddFile(path string) error {
+	var t *demTile
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".hgt":
+		t, err = hgtTile(path)
+	case ".tif", ".tiff":
+		t, err = geoTIFFTile(path)
+	default:
+		err = fmt.Errorf("dem: unsupported file %s", path)
+	}
+	if err != nil {
+		return err
+	}
+	d.tiles = append(d.tiles, t)
+	return nil
+}
+
+// find 查找覆盖 (lat, lon) 的瓦片，返回瓦片与归化到瓦片经度范围内的经度
+func (d *DEM) find(lat, lon float64) (*demTile, float64) {
+	for _, t := range d.tiles {
+		if lat < t.south || lat > t.north {
+			continue
+		}
+		dl := math.Mod(lon-t.west, 360)
+		if dl < 0 {
+			dl += 360
+		}
+		if dl <= t.east-t.west {
+			return t, t.west + dl
+		}
+	}
+	return nil, lon
+}
+
+// resolution 瓦片中最细的纬向格距 (m)
+func (d *DEM) resolution() float64 {
+	r := math.Inf(1)
+	for _, t := range d.tiles {
+		r = math.Min(r, t.spacing*math.Pi/180*6371e3)
+	}
+	return r
+}
+
+// Elevation DEM 高程 (m)，无瓦片覆盖 (且未设 SeaLevel) 或位于空洞时返回错误
+func (d *DEM) Elevation(lat, lon float64) (float64, error) {
+	t, tlon := d.find(lat, lon)
+	if t == nil {
+		if d.SeaLevel {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("dem: no tile covers (%g, %g)", lat, lon)
+	}
+	t.once.Do(func() { t.g, t.err = t.load() })
+	if t.err != nil {
+		return 0, fmt.Errorf("dem: %s: %w", t.path, t.err)
+	}
+	h, err := t.g.interpolate(lat, tlon, d.Method)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(h) {
+		return 0, fmt.Errorf("dem: void at (%g, %g) in %s", lat, lon, t.path)
+	}
+	return h, nil
+}
+
+// TerrainHeight 地形椭球高 (m)：指定 Geoid 时为 DEM 高程加大地水准面差距，否则即 DEM 高程
+func (d *DEM) TerrainHeight(lat, lon float64) (float64, error) {
+	h, err := d.Elevation(lat, lon)
+	if err != nil || d.Geoid == nil {
+		return h, err
+	}
+	n, err := d.Geoid.Undulation(lat, lon)
+	if err != nil {
+		return 0, err
+	}
+	return h + n, nil
+}
+
+// OnTerrain 返回高度为地形椭球高加离地高 agl (m) 的副本，如架设于地面的天线
+func (geo *Geodetic) OnTerrain(d *DEM, agl float64) (Geodetic, error) {
+	h, err := d.TerrainHeight(geo.Latitude, geo.Longitude)
+	if err != nil {
+		return Geodetic{}, err
+	}
+	out := *geo
+	out.Altitude = h + agl
+	return out, nil
+}
+
+// ProfilePoint 地形剖面点
+type ProfilePoint struct {
+	Distance float64  // 沿大地线距起点的距离 (m)
+	Ground   Geodetic // 地面点，高度为地形椭球高
+}
+
+// Profile 沿 from → to 的大地线以间距 step (m) 提取地形剖面，含两端点；step ≤ 0 时取 DEM 格距
+func (d *DEM) Profile(from, to Geodetic, step float64) ([]ProfilePoint, error) {
+	if step <= 0 {
+		step = d.resolution()
+	}
+	s12, _, _ := from.GeodesicInverse(to)
+	n := int(math.Ceil(s12 / step))
+	if n < 1 {
+		n = 1
+	}
+	pts := from.GeodesicWaypoints(to, n)
+	out := make([]ProfilePoint, len(pts))
+	for i, p := range pts {
+		h, err := d.TerrainHeight(p.Latitude, p.Longitude)
+		if err != nil {
+			return nil, err
+		}
+		p.Altitude = h
+		out[i] = ProfilePoint{Distance: s12 * float64(i) / float64(n), Ground: p}
+	}
+	return out, nil
+}
+
+// LineOfSight 计入地形遮挡的 station → target 视线可视性
+// 射线模型同 VisibilityTo；沿视线以 DEM 格距采样 (不含两端点)，Clearance 为射线高出地形的最小余隙，
+// Grazing 为该处射线上的点。
+func (d *DEM) LineOfSight(station, target Geodetic, opts *VisibilityOptions) (Visibility, error) {
+	l := newSightLine(station, target, opts.kFactor())
+	v := Visibility{Clearance: math.Inf(1), SRange: l.d}
+	n := int(math.Ceil(l.d / d.resolution()))
+	for i := 1; i < n; i++ {
+		lat, lon, h := l.at(l.d * float64(i) / float64(n))
+		ground, err := d.TerrainHeight(lat, lon)
+		if err != nil {
+			return Visibility{}, err
+		}
+		if c := h - ground; c < v.Clearance {
+			v.Clearance = c
+			v.Grazing = Geodetic{Latitude: lat, Longitude: lon, Altitude: h, Ell: station.Ell}
+		}
+	}
+	if n < 2 {
+		// 两点相距不足一个格距: 视为无遮挡
+		v.Clearance, v.Grazing = math.Inf(1), station
+	}
+	v.Visible = v.Clearance >= opts.minClearance()
+	return v, nil
+}
+
+// ============================================================
+// SRTM HGT
+// ============================================================
+
+// hgtTile 由文件名 (如 N39E116.hgt) 确定瓦片西南角，由文件大小确定格网尺寸
+func hgtTile(path string) (*demTile, error) {
+	name := strings.ToUpper(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	bad := fmt.Errorf("dem: HGT file name %s is not like N39E116.hgt", filepath.Base(path))
+	if len(name) != 7 || (name[0] != 'N' && name[0] != 'S') || (name[3] != 'E' && name[3] != 'W') {
+		return nil, bad
+	}
+	lat, err1 := strconv.Atoi(name[1:3])
+	lon, err2 := strconv.Atoi(name[4:7])
+	if err1 != nil || err2 != nil {
+		return nil, bad
+	}
+	if name[0] == 'S' {
+		lat = -lat
+	}
+	if name[3] == 'W' {
+		lon = -lon
+	}
+	st, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	n := int(math.Round(math.Sqrt(float64(st.Size() / 2))))
+	if n < 2 || int64(n)*int64(n)*2 != st.Size() {
+		return nil, fmt.Errorf("dem: %s: size %d is not a square 16-bit grid", path, st.Size())
+	}
+	step := 1 / float64(n-1)
+	return &demTile{
+		path: path, south: float64(lat), north: float64(lat + 1), west: float64(lon), east: float64(lon + 1),
+		spacing: step,
+		load: func() (*grid, error) {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			g, err := newGrid(float64(lat+1), float64(lon), step, step, n, n)
+			if err != nil {
+				return nil, err
+			}
+			for k := range g.v {
+				v := int16(binary.BigEndian.Uint16(raw[2*k:]))
+				if v == -32768 {
+					g.v[k] = float32(math.NaN())
+				} else {
+					g.v[k] = float32(v)
+				}
+			}
+			return g, nil
+		},
+	}, nil
+}
+
+// ============================================================
+// GeoTIFF
+// ============================================================
+
+// TIFF / GeoTIFF 标签
+const (
+	tiffImageWidth      = 256
+	tiffImageLength     = 257
+	tiffBitsPerSample   = 258
+	tiffCompression     = 259
+	tiffStripOffsets    = 273
+	tiffSamplesPerPixel = 277
+	tiffRowsPerStrip    = 278
+	tiffStripByteCounts = 279
+	tiffTileWidth       = 322
+	tiffSampleFormat    = 339
+	tiffModelPixelScale = 33550
+	tiffModelTiepoint   = 33922
+	tiffGeoKeyDirectory = 34735
+	tiffGDALNoData      = 42113
+
+	geoKeyModelType  = 1024
+	geoKeyRasterType = 1025
+)
+
+// geoTIFF 单波段条带式 GeoTIFF 的布局与地理定位
+type geoTIFF struct {
+	order           binary.ByteOrder
+	width, height   int
+	bits, format    int
+	offsets, counts []float64
+	rowsPerStrip    int
+	north, west     float64 // 第 0 行第 0 列像元中心
+	dlat, dlon      float64
+	nodata          float64 // 无 NoData 时为 NaN
+}
+
+// geoTIFFTile 读取 GeoTIFF 文件头建立瓦片
+func geoTIFFTile(path string) (*demTile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	ti, err := parseGeoTIFF(f)
+	if err != nil {
+		return nil, fmt.Errorf("dem: %s: %w", path, err)
+	}
+	return &demTile{
+		path: path, north: ti.north, west: ti.west,
+		south:   ti.north - float64(ti.height-1)*ti.dlat,
+		east:    ti.west + float64(ti.width-1)*ti.dlon,
+		spacing: ti.dlat,
+		load: func() (*grid, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			return ti.read(f)
+		},
+	}, nil
+}
+
+// parseGeoTIFF 解析首个 IFD
+func parseGeoTIFF(r io.ReaderAt) (*geoTIFF, error) {
+	hdr := make([]byte, 8)
+	if _, err := r.ReadAt(hdr, 0); err != nil {
+		return nil, fmt.Errorf("tiff header: %w", err)
+	}
+	ti := &geoTIFF{nodata: math.NaN()}
+	switch string(hdr[:2]) {
+	case "II":
+		ti.order = binary.LittleEndian
+	case "MM":
+		ti.order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("not a TIFF file")
+	}
+	if v := ti.order.Uint16(hdr[2:]); v != 42 {
+		return nil, fmt.Errorf("unsupported TIFF version %d (BigTIFF?)", v)
+	}
+	off := int64(ti.order.Uint32(hdr[4:]))
+	cnt := make([]byte, 2)
+	if _, err := r.ReadAt(cnt, off); err != nil {
+		return nil, fmt.Errorf("tiff IFD: %w", err)
+	}
+	n := int(ti.order.Uint16(cnt))
+	ifd := make([]byte, 12*n)
+	if _, err := r.ReadAt(ifd, off+2); err != nil {
+		return nil, fmt.Errorf("tiff IFD: %w", err)
+	}
+
+	tags := map[uint16][]float64{}
+	var nodata string
+	for k := 0; k < n; k++ {
+		e := ifd[12*k : 12*k+12]
+		tag, typ, count := ti.order.Uint16(e), ti.order.Uint16(e[2:]), int(ti.order.Uint32(e[4:]))
+		size := map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 6: 1, 8: 2, 9: 4, 11: 4, 12: 8}[typ]
+		if size == 0 {
+			continue
+		}
+		data := e[8:12]
+		if size*count > 4 {
+			data = make([]byte, size*count)
+			if _, err := r.ReadAt(data, int64(ti.order.Uint32(e[8:]))); err != nil {
+				return nil, fmt.Errorf("tiff tag %d: %w", tag, err)
+			}
+		}
+		if typ == 2 {
+			if tag == tiffGDALNoData {
+				nodata = strings.TrimRight(string(data[:count]), "\x00 ")
+			}
+			continue
+		}
+		vals := make([]float64, count)
+		for i := range vals {
+			b := data[i*size:]
+			switch typ {
+			case 1:
+				vals[i] = float64(b[0])
+			case 6:
+				vals[i] = float64(int8(b[0]))
+			case 3:
+				vals[i] = float64(ti.order.Uint16(b))
+			case 8:
+				vals[i] = float64(int16(ti.order.Uint16(b)))
+			case 4:
+				vals[i] = float64(ti.order.Uint32(b))
+			case 9:
+				vals[i] = float64(int32(ti.order.Uint32(b)))
+			case 11:
+				vals[i] = float64(math.Float32frombits(ti.order.Uint32(b)))
+			case 12:
+				vals[i] = math.Float64frombits(ti.order.Uint64(b))
+			}
+		}
+		tags[tag] = vals
+	}
+
+	get := func(tag uint16, def float64) float64 {
+		if v, ok := tags[tag]; ok && len(v) > 0 {
+			return v[0]
+		}
+		return def
+	}
+	ti.width, ti.height = int(get(tiffImageWidth, 0)), int(get(tiffImageLength, 0))
+	ti.bits, ti.format = int(get(tiffBitsPerSample, 1)), int(get(tiffSampleFormat, 1))
+	ti.rowsPerStrip = int(get(tiffRowsPerStrip, float64(ti.height)))
+	ti.offsets, ti.counts = tags[tiffStripOffsets], tags[tiffStripByteCounts]
+	switch {
+	case ti.width < 2 || ti.height < 2:
+		return nil, fmt.Errorf("invalid image size %d×%d", ti.width, ti.height)
+	case get(tiffCompression, 1) != 1:
+		return nil, fmt.Errorf("compressed TIFF not supported")
+	case get(tiffSamplesPerPixel, 1) != 1:
+		return nil, fmt.Errorf("multi-band TIFF not supported")
+	case tags[tiffTileWidth] != nil:
+		return nil, fmt.Errorf("tiled TIFF not supported")
+	case ti.rowsPerStrip < 1 || len(ti.offsets) == 0 || len(ti.offsets) != len(ti.counts):
+		return nil, fmt.Errorf("invalid strip layout")
+	}
+
+	scale, tie := tags[tiffModelPixelScale], tags[tiffModelTiepoint]
+	if len(scale) < 2 || len(tie) < 6 || !(scale[0] > 0) || !(scale[1] > 0) {
+		return nil, fmt.Errorf("missing ModelPixelScale or ModelTiepoint")
+	}
+	modelType, rasterType := 2.0, 1.0
+	if keys := tags[tiffGeoKeyDirectory]; len(keys) >= 4 {
+		for k := 0; k < int(keys[3]) && 4+4*k+3 < len(keys); k++ {
+			id, loc, v := keys[4+4*k], keys[4+4*k+1], keys[4+4*k+3]
+			if loc != 0 {
+				continue
+			}
+			switch id {
+			case geoKeyModelType:
+				modelType = v
+			case geoKeyRasterType:
+				rasterType = v
+			}
+		}
+	}
+	if modelType != 2 {
+		return nil, fmt.Errorf("only geographic (lat/lon) GeoTIFF supported, model type %g", modelType)
+	}
+	ti.dlon, ti.dlat = scale[0], scale[1]
+	// PixelIsArea: 定位点为像元角点，格网节点取像元中心
+	half := 0.5
+	if rasterType == 2 {
+		half = 0
+	}
+	ti.west = tie[3] + (half-tie[0])*ti.dlon
+	ti.north = tie[4] - (half-tie[1])*ti.dlat
+	if nodata != "" {
+		v, err := strconv.ParseFloat(nodata, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GDAL NoData %q", nodata)
+		}
+		ti.nodata = v
+	}
+	return ti, nil
+}
+
+// read 读取像元值
+func (ti *geoTIFF) read(r io.ReaderAt) (*grid, error) {
+	bps := ti.bits / 8
+	var decode func(b []byte) float64
+	switch {
+	case ti.bits == 16 && ti.format == 1:
+		decode = func(b []byte) float64 { return float64(ti.order.Uint16(b)) }
+	case ti.bits == 16 && ti.format == 2:
+		decode = func(b []byte) float64 { return float64(int16(ti.order.Uint16(b))) }
+	case ti.bits == 32 && ti.format == 1:
+		decode = func(b []byte) float64 { return float64(ti.order.Uint32(b)) }
+	case ti.bits == 32 && ti.format == 2:
+		decode = func(b []byte) float64 { return float64(int32(ti.order.Uint32(b))) }
+	case ti.bits == 32 && ti.format == 3:
+		decode = func(b []byte) float64 { return float64(math.Float32frombits(ti.order.Uint32(b))) }
+	case ti.bits == 64 && ti.format == 3:
+		decode = func(b []byte) float64 { return math.Float64frombits(ti.order.Uint64(b)) }
+	default:
+		return nil, fmt.Errorf("unsupported sample type: %d bits, format %d", ti.bits, ti.format)
+	}
+	g, err := newGrid(ti.north, ti.west, ti.dlat, ti.dlon, ti.height, ti.width)
+	if err != nil {
+		return nil, err
+	}
+	row := 0
+	for s, off := range ti.offsets {
+		rows := imin(ti.rowsPerStrip, ti.height-row)
+		if rows <= 0 {
+			break
+		}
+		buf := make([]byte, rows*ti.width*bps)
+		if int(ti.counts[s]) < len(buf) {
+			return nil, fmt.Errorf("strip %d: %d bytes, need %d", s, int(ti.counts[s]), len(buf))
+		}
+		if _, err := r.ReadAt(buf, int64(off)); err != nil {
+			return nil, fmt.Errorf("strip %d: %w", s, err)
+		}
+		for k := 0; k < rows*ti.width; k++ {
+			v := decode(buf[k*bps:])
+			if v == ti.nodata {
+				v = math.NaN()
+			}
+			g.v[row*ti.width+k] = float32(v)
+		}
+		row += rows
+	}
+	if row < ti.height {
+		return nil, fmt.Errorf("strips cover %d of %d rows", row, ti.height)
+	}
+	return g, nil
+}
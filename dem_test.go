@@ -0,0 +1,129 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDEMElevation(t *testing.T) {
+	d, err := OpenDEM("test_data/dem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// N31E111.hgt: 11×11，第 i 行 (自北) 第 j 列高程 100 + 10i + 3j，双线性插值精确
+	hgt := func(lat, lon float64) float64 { return 100 + 10*(32-lat)*10 + 3*(lon-111)*10 }
+	for _, c := range [][2]float64{{31.75, 111.25}, {32, 111}, {31, 112}, {31.03, 111.97}} {
+		if h, err := d.Elevation(c[0], c[1]); err != nil || math.Abs(h-hgt(c[0], c[1])) > 1e-9 {
+			t.Errorf("hgt %v: %g %v", c, h, err)
+		}
+	}
+	// 经度按 360° 归化
+	if h, err := d.Elevation(31.75, 111.25-360); err != nil || math.Abs(h-hgt(31.75, 111.25)) > 1e-9 {
+		t.Errorf("wrapped longitude: %g %v", h, err)
+	}
+	if _, err := d.Elevation(31.5, 111.5); err == nil {
+		t.Error("expected error at void")
+	}
+
+	// ridge.tif: float32，0.01° 格距，沿 111.5°E 高 2000 m、半宽 0.1° 的山脊
+	ridge := func(lon float64) float64 { return math.Max(0, 2000-math.Abs(lon-111.5)*20000) }
+	for _, c := range [][2]float64{{30.25, 111.5}, {30.1, 111.55}, {30.333, 111.437}, {30, 112}} {
+		if h, err := d.Elevation(c[0], c[1]); err != nil || math.Abs(h-ridge(c[1])) > 1e-3 {
+			t.Errorf("tif %v: %g %v", c, h, err)
+		}
+	}
+	if _, err := d.Elevation(30.5, 111); err == nil {
+		t.Error("expected error at NoData")
+	}
+
+	// 无瓦片覆盖
+	if _, err := d.Elevation(0, 0); err == nil {
+		t.Error("expected error outside tiles")
+	}
+	d.SeaLevel = true
+	if h, err := d.Elevation(0, 0); err != nil || h != 0 {
+		t.Errorf("sea level: %g %v", h, err)
+	}
+
+	// 正高加大地水准面差距
+	d.Geoid, err = LoadGeoid("test_data/geoid_region.grd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := -20 + 1.5*1.75 - 0.8*1.25 + 0.3*1.75*1.25
+	if h, err := d.TerrainHeight(31.75, 111.25); err != nil || math.Abs(h-hgt(31.75, 111.25)-n) > 1e-4 {
+		t.Errorf("terrain height: %g, want %g", h, hgt(31.75, 111.25)+n)
+	}
+	wgs, _ := NewEllipsoid("wgs84")
+	mast := Geodetic{Latitude: 31.75, Longitude: 111.25, Altitude: -1, Ell: wgs}
+	if g, err := mast.OnTerrain(d, 30); err != nil || math.Abs(g.Altitude-hgt(31.75, 111.25)-n-30) > 1e-4 {
+		t.Errorf("OnTerrain: %+v %v", g, err)
+	}
+
+	if _, err := OpenDEM("cpp"); err == nil {
+		t.Error("expected error for directory without tiles")
+	}
+	if err := d.AddFile("test_data/geoid_region.grd"); err == nil {
+		t.Error("expected error for unsupported file")
+	}
+}
+
+func TestDEMLineOfSight(t *testing.T) {
+	d, err := OpenDEM("test_data/dem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wgs, _ := NewEllipsoid("wgs84")
+	west := Geodetic{Latitude: 30.25, Longitude: 111.2, Altitude: 10, Ell: wgs}
+	east := Geodetic{Latitude: 30.25, Longitude: 111.8, Altitude: 10, Ell: wgs}
+
+	// 剖面穿过山脊
+	prof, err := d.Profile(west, east, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s12, _, _ := west.GeodesicInverse(east)
+	last := prof[len(prof)-1]
+	if prof[0].Distance != 0 || math.Abs(last.Distance-s12) > 1e-6 || math.Abs(last.Ground.Longitude-111.8) > 1e-9 {
+		t.Errorf("profile ends: %+v %+v", prof[0], last)
+	}
+	peak := ProfilePoint{}
+	for _, p := range prof {
+		if p.Ground.Altitude > peak.Ground.Altitude {
+			peak = p
+		}
+	}
+	if peak.Ground.Altitude < 1990 || math.Abs(peak.Ground.Longitude-111.5) > 1e-3 || math.Abs(peak.Distance-s12/2) > 200 {
+		t.Errorf("profile peak: %+v", peak)
+	}
+
+	// 低处两点被山脊遮挡，最低余隙位于山脊
+	v, err := d.LineOfSight(west, east, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Visible || v.Clearance > -1900 || math.Abs(v.Grazing.Longitude-111.5) > 0.01 || math.Abs(v.SRange-s12) > 10 {
+		t.Errorf("blocked: %+v", v)
+	}
+	// 高空目标越过山脊可见，余隙要求可使其不可见
+	high := east
+	high.Altitude = 5000
+	v, err = d.LineOfSight(west, high, nil)
+	if err != nil || !v.Visible || v.Clearance < 0 {
+		t.Errorf("over the ridge: %+v %v", v, err)
+	}
+	if v2, _ := d.LineOfSight(west, high, &VisibilityOptions{MinClearance: v.Clearance + 1}); v2.Visible {
+		t.Errorf("clearance requirement: %+v", v2)
+	}
+	// 山脊同侧无遮挡
+	near := Geodetic{Latitude: 30.3, Longitude: 111.3, Altitude: 10, Ell: wgs}
+	if v, err := d.LineOfSight(west, near, nil); err != nil || !v.Visible {
+		t.Errorf("same side: %+v %v", v, err)
+	}
+	// 视线经过无瓦片区域
+	far := Geodetic{Latitude: 29.5, Longitude: 111.5, Altitude: 10, Ell: wgs}
+	if _, err := d.LineOfSight(west, far, nil); err == nil {
+		t.Error("expected error when the path leaves the DEM")
+	}
+}
@@ -21,7 +21,12 @@ func (ecef ECEF) ToGeodetic() Geodetic {
 
 // 转地心惯性坐标系
 func (ecef ECEF) ToECI(t time.Time) ECI {
-	x, y, z := ECEF2ECI(ecef.X, ecef.Y, ecef.Z, t)
+	return ecef.ToECIWith(t, DefaultTransformer())
+}
+
+// ToECIWith 使用转换器 tr 转地心惯性坐标系
+func (ecef ECEF) ToECIWith(t time.Time, tr *Transformer) ECI {
+	x, y, z := tr.ECEF2ECI(ecef.X, ecef.Y, ecef.Z, t)
 	return ECI{
 		X:   x,
 		Y:   y,
@@ -13,7 +13,12 @@ type ECI struct {
 
 // 转地心地固坐标系
 func (eci *ECI) ToECEF() ECEF {
-	x, y, z := ECI2ECEF(eci.X, eci.Y, eci.Z, eci.T)
+	return eci.ToECEFWith(DefaultTransformer())
+}
+
+// ToECEFWith 使用转换器 tr 转地心地固坐标系
+func (eci *ECI) ToECEFWith(tr *Transformer) ECEF {
+	x, y, z := tr.ECI2ECEF(eci.X, eci.Y, eci.Z, eci.T)
 	return ECEF{
 		X:   x,
 		Y:   y,
@@ -24,7 +29,12 @@ func (eci *ECI) ToECEF() ECEF {
 
 // 转大地坐标系
 func (eci *ECI) ToGeodetic() Geodetic {
-	x, y, z := ECI2ECEF(eci.X, eci.Y, eci.Z, eci.T)
+	return eci.ToGeodeticWith(DefaultTransformer())
+}
+
+// ToGeodeticWith 使用转换器 tr 转大地坐标系
+func (eci *ECI) ToGeodeticWith(tr *Transformer) Geodetic {
+	x, y, z := tr.ECI2ECEF(eci.X, eci.Y, eci.Z, eci.T)
 	latitude, longitude, altitude := ECEF2Geodetic(x, y, z, eci.Ell)
 	return Geodetic{
 		Latitude:  latitude,
@@ -36,7 +46,12 @@ func (eci *ECI) ToGeodetic() Geodetic {
 
 // 转东北天坐标系
 func (eci *ECI) ToENU(ref Geodetic) ENU {
-	x, y, z := ECI2ECEF(eci.X, eci.Y, eci.Z, eci.T)
+	return eci.ToENUWith(ref, DefaultTransformer())
+}
+
+// ToENUWith 使用转换器 tr 转东北天坐标系
+func (eci *ECI) ToENUWith(ref Geodetic, tr *Transformer) ENU {
+	x, y, z := tr.ECI2ECEF(eci.X, eci.Y, eci.Z, eci.T)
 	e, n, u := ECEF2ENU(
 		x, y, z,
 		ref.Latitude, ref.Longitude, ref.Altitude,
@@ -51,7 +66,12 @@ func (eci *ECI) ToENU(ref Geodetic) ENU {
 
 // 转站心坐标系
 func (eci *ECI) ToAER(ref Geodetic) AER {
-	x, y, z := ECI2ECEF(eci.X, eci.Y, eci.Z, eci.T)
+	return eci.ToAERWith(ref, DefaultTransformer())
+}
+
+// ToAERWith 使用转换器 tr 转站心坐标系
+func (eci *ECI) ToAERWith(ref Geodetic, tr *Transformer) AER {
+	x, y, z := tr.ECI2ECEF(eci.X, eci.Y, eci.Z, eci.T)
 	east, north, up := ECEF2ENU(
 		x, y, z,
 		ref.Latitude, ref.Longitude, ref.Altitude,
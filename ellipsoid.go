@@ -3,6 +3,8 @@ package gomap3d
 import (
 	"fmt"
 	"math"
+	"sort"
+	"sync"
 )
 
 // Ellipsoid 表示地球椭球体参数
@@ -14,44 +16,181 @@ type Ellipsoid struct {
 	Flattening      float64
 	ThirdFlattening float64
 	Eccentricity    float64
+	GM              float64 // 中心天体引力常数 (m³/s²)，未定义为 0
 }
 
-var models = map[string]struct {
+// ellipsoidDef 内置椭球定义参数：短半轴 B 与扁率倒数 InvF 二选一，均为 0 时为球体
+type ellipsoidDef struct {
 	Name string
 	A    float64
 	B    float64
-}{
+	InvF float64
+	GM   float64
+}
+
+var builtinEllipsoids = map[string]ellipsoidDef{
 	// 地球椭球体
 	// CGCS2000 坐标系
-	"cgcs2000": {"CGCS-2000 (2008) ", 6378137.0, 6356752.31414},
+	"cgcs2000": {"CGCS-2000 (2008) ", 6378137.0, 6356752.31414, 0, 3.986004418e14},
 	// WGS84 坐标系
-	"wgs84": {"WGS-84 (1984)", 6378137.0, 6356752.31424518},
-	// 月球
-	"moon": {"Moon", 1738100, 1736000.0},
-	// 火星
-	"mars": {"Mars", 3396190, 3376097.80585952},
+	"wgs84":     {"WGS-84 (1984)", 6378137.0, 6356752.31424518, 0, 3.986004418e14},
+	"wgs72":     {"WGS-72 (1972)", 6378135.0, 0, 298.26, 3.986008e14},
+	"grs80":     {"GRS-80 (1980)", 6378137.0, 0, 298.257222101, 3.986005e14},
+	"grs67":     {"GRS-67 (1967)", 6378160.0, 0, 298.247167427, 3.98603e14},
+	"pz90":      {"PZ-90 (1990)", 6378136.0, 0, 298.25784, 3.9860044e14},
+	"gsk2011":   {"GSK-2011", 6378136.5, 0, 298.2564151, 3.986004415e14},
+	"iag75":     {"IAG-75 (1975)", 6378140.0, 0, 298.257, 3.986005e14},
+	"xian80":    {"Xi'an 80 (IAG-75)", 6378140.0, 0, 298.257, 3.986005e14},
+	"krass":     {"Krassovsky (1940)", 6378245.0, 0, 298.3, 0},
+	"beijing54": {"Beijing 54 (Krassovsky 1940)", 6378245.0, 0, 298.3, 0},
+	"clrk66":    {"Clarke (1866)", 6378206.4, 6356583.8, 0, 0},
+	"clrk80":    {"Clarke (1880 mod.)", 6378249.145, 0, 293.4663, 0},
+	"bessel":    {"Bessel (1841)", 6377397.155, 0, 299.1528128, 0},
+	"intl":      {"International (1924)", 6378388.0, 0, 297.0, 0},
+	"airy":      {"Airy (1830)", 6377563.396, 0, 299.3249646, 0},
+	"mod_airy":  {"Modified Airy", 6377340.189, 0, 299.3249646, 0},
+	"evrst30":   {"Everest (1830)", 6377276.345, 0, 300.8017, 0},
+	"helmert":   {"Helmert (1906)", 6378200.0, 0, 298.3, 0},
+	"aust_sa":   {"Australian National (1965)", 6378160.0, 0, 298.25, 0},
+
+	// 太阳系天体，半径取自 IAU WGCCRE 2015，GM 取自 DE430 (含卫星的系统值)
+	// 月球 (GM 取自 DE430)
+	"moon":     {"Moon", 1738100, 1736000.0, 0, 4.9028000661e12},
+	"moon_iau": {"Moon (IAU 2015 mean)", 1737400, 0, 0, 4.9028000661e12},
+	// 火星 (GM 取自 DE430)
+	"mars":    {"Mars", 3396190, 3376097.80585952, 0, 4.282837362e13},
+	"sun":     {"Sun (IAU 2015)", 695700e3, 0, 0, GMSun},
+	"mercury": {"Mercury (IAU 2015)", 2440530, 2438260, 0, 2.203178e13},
+	"venus":   {"Venus (IAU 2015)", 6051800, 0, 0, 3.24858592e14},
+	"jupiter": {"Jupiter (IAU 2015)", 71492e3, 66854e3, 0, 1.267127648e17},
+	"saturn":  {"Saturn (IAU 2015)", 60268e3, 54364e3, 0, 3.79405852e16},
+	"uranus":  {"Uranus (IAU 2015)", 25559e3, 24973e3, 0, 5.7945486e15},
+	"neptune": {"Neptune (IAU 2015)", 24764e3, 24341e3, 0, 6.8365271e15},
+	"pluto":   {"Pluto (IAU 2015)", 1188300, 0, 0, 9.77e11},
 }
 
-// NewEllipsoid 通过名称创建椭球体
+var (
+	modelsMu sync.RWMutex
+	models   = builtinModels()
+)
+
+// builtinModels 由内置定义计算全部派生参数
+func builtinModels() map[string]Ellipsoid {
+	m := make(map[string]Ellipsoid, len(builtinEllipsoids))
+	for model, d := range builtinEllipsoids {
+		var e *Ellipsoid
+		var err error
+		if d.B != 0 {
+			e, err = NewEllipsoidAB(d.Name, d.A, d.B)
+		} else {
+			e, err = NewEllipsoidInvF(d.Name, d.A, d.InvF)
+		}
+		if err != nil {
+			panic(fmt.Sprintf("ellipsoid %s: %v", model, err))
+		}
+		e.Model = model
+		e.GM = d.GM
+		m[model] = *e
+	}
+	return m
+}
+
+// NewEllipsoid 通过名称创建椭球体，名称为内置模型或 Register 注册的模型
 func NewEllipsoid(model string) (*Ellipsoid, error) {
+	modelsMu.RLock()
 	m, ok := models[model]
+	modelsMu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("unknown ellipsoid model: %s", model)
 	}
+	return &m, nil
+}
 
-	a := m.A
-	b := m.B
+// NewEllipsoidAB 由长半轴 a 与短半轴 b (m) 创建椭球体
+func NewEllipsoidAB(name string, a, b float64) (*Ellipsoid, error) {
+	if !(a > 0) || !(b > 0) || b > a {
+		return nil, fmt.Errorf("ellipsoid: invalid axes a=%g b=%g", a, b)
+	}
 	f := (a - b) / a
-	thirdF := (a - b) / (a + b)
-	e := math.Sqrt(2*f - f*f)
-
 	return &Ellipsoid{
-		Model:           model,
-		Name:            m.Name,
+		Name:            name,
 		SemimajorAxis:   a,
 		SemiminorAxis:   b,
 		Flattening:      f,
-		ThirdFlattening: thirdF,
-		Eccentricity:    e,
+		ThirdFlattening: (a - b) / (a + b),
+		Eccentricity:    math.Sqrt(2*f - f*f),
+	}, nil
+}
+
+// NewEllipsoidInvF 由长半轴 a (m) 与扁率倒数 1/f 创建椭球体，invf 为 0 表示球体
+func NewEllipsoidInvF(name string, a, invf float64) (*Ellipsoid, error) {
+	if !(a > 0) || (invf != 0 && !(invf > 1)) {
+		return nil, fmt.Errorf("ellipsoid: invalid a=%g 1/f=%g", a, invf)
+	}
+	var f float64
+	if invf != 0 {
+		f = 1 / invf
+	}
+	return &Ellipsoid{
+		Name:            name,
+		SemimajorAxis:   a,
+		SemiminorAxis:   a * (1 - f),
+		Flattening:      f,
+		ThirdFlattening: f / (2 - f),
+		Eccentricity:    math.Sqrt(2*f - f*f),
 	}, nil
 }
+
+// NewEllipsoidE2 由长半轴 a (m) 与第一偏心率平方 e² 创建椭球体
+func NewEllipsoidE2(name string, a, e2 float64) (*Ellipsoid, error) {
+	if !(a > 0) || !(e2 >= 0 && e2 < 1) {
+		return nil, fmt.Errorf("ellipsoid: invalid a=%g e²=%g", a, e2)
+	}
+	s := math.Sqrt(1 - e2)
+	f := 1 - s
+	return &Ellipsoid{
+		Name:            name,
+		SemimajorAxis:   a,
+		SemiminorAxis:   a * s,
+		Flattening:      f,
+		ThirdFlattening: f / (2 - f),
+		Eccentricity:    math.Sqrt(e2),
+	}, nil
+}
+
+// Register 以名称 model 注册椭球体，之后可通过 NewEllipsoid(model) 获取
+// 派生参数与长短半轴不一致 (如只填写了 a、b) 时按长短半轴重新计算；名称已存在时返回错误。
+func Register(model string, e *Ellipsoid) error {
+	if model == "" || e == nil {
+		return fmt.Errorf("ellipsoid: empty model name or nil ellipsoid")
+	}
+	r, err := NewEllipsoidAB(e.Name, e.SemimajorAxis, e.SemiminorAxis)
+	if err != nil {
+		return err
+	}
+	if math.Abs(e.Flattening-r.Flattening) < 1e-12 && math.Abs(e.Eccentricity-r.Eccentricity) < 1e-12 {
+		*r = *e
+	}
+	r.Model = model
+	r.GM = e.GM
+
+	modelsMu.Lock()
+	defer modelsMu.Unlock()
+	if _, ok := models[model]; ok {
+		return fmt.Errorf("ellipsoid model already registered: %s", model)
+	}
+	models[model] = *r
+	return nil
+}
+
+// Ellipsoids 返回全部已注册的椭球体名称 (升序)
+func Ellipsoids() []string {
+	modelsMu.RLock()
+	defer modelsMu.RUnlock()
+	names := make([]string, 0, len(models))
+	for k := range models {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,116 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEllipsoidCatalog(t *testing.T) {
+	// 参考值: 短半轴 b 与 e²
+	cases := []struct {
+		model string
+		b, e2 float64
+	}{
+		{"grs80", 6356752.314140347, 0.00669438002290},
+		{"krass", 6356863.018773, 0.006693421622966},
+		{"beijing54", 6356863.018773, 0.006693421622966},
+		{"iag75", 6356755.288158, 0.006694384999588},
+		{"bessel", 6356078.962818, 0.0066743722318},
+		{"intl", 6356911.946128, 0.006722670022333},
+		{"airy", 6356256.909237, 0.006670539999985},
+		{"clrk66", 6356583.8, 0.006768657997291},
+	}
+	for _, c := range cases {
+		e, err := NewEllipsoid(c.model)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Model != c.model || math.Abs(e.SemiminorAxis-c.b) > 1e-5 ||
+			math.Abs(e.Eccentricity*e.Eccentricity-c.e2) > 1e-12 {
+			t.Errorf("%s: b = %.6f, e² = %.15f", c.model, e.SemiminorAxis, e.Eccentricity*e.Eccentricity)
+		}
+	}
+
+	// 全部内置模型派生参数自洽
+	for _, name := range Ellipsoids() {
+		e, _ := NewEllipsoid(name)
+		a, b := e.SemimajorAxis, e.SemiminorAxis
+		if math.Abs(e.Flattening-(a-b)/a) > 1e-15 || math.Abs(e.ThirdFlattening-(a-b)/(a+b)) > 1e-15 ||
+			math.Abs(e.Eccentricity*e.Eccentricity-(a*a-b*b)/(a*a)) > 1e-15 {
+			t.Errorf("%s: inconsistent derived parameters %+v", name, e)
+		}
+	}
+	if s, _ := NewEllipsoid("venus"); s.Eccentricity != 0 || s.SemiminorAxis != s.SemimajorAxis {
+		t.Errorf("venus should be a sphere: %+v", s)
+	}
+}
+
+func TestEllipsoidConstructors(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	a := wgs.SemimajorAxis
+	fromB, err := NewEllipsoidAB("ab", a, wgs.SemiminorAxis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromF, _ := NewEllipsoidInvF("invf", a, 298.257223563)
+	fromE, _ := NewEllipsoidE2("e2", a, 6.69437999014e-3)
+	for _, e := range []*Ellipsoid{fromB, fromF, fromE} {
+		if math.Abs(e.SemiminorAxis-wgs.SemiminorAxis) > 1e-4 ||
+			math.Abs(e.Flattening-wgs.Flattening) > 1e-13 ||
+			math.Abs(e.ThirdFlattening-wgs.ThirdFlattening) > 1e-13 ||
+			math.Abs(e.Eccentricity-wgs.Eccentricity) > 1e-12 {
+			t.Errorf("%s: %+v differs from WGS84 %+v", e.Name, e, wgs)
+		}
+	}
+
+	bad := []func() (*Ellipsoid, error){
+		func() (*Ellipsoid, error) { return NewEllipsoidAB("", 1, 2) },
+		func() (*Ellipsoid, error) { return NewEllipsoidAB("", 0, 0) },
+		func() (*Ellipsoid, error) { return NewEllipsoidInvF("", 1, 0.5) },
+		func() (*Ellipsoid, error) { return NewEllipsoidE2("", 1, 1) },
+		func() (*Ellipsoid, error) { return NewEllipsoidE2("", 1, math.NaN()) },
+	}
+	for i, f := range bad {
+		if _, err := f(); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestRegisterEllipsoid(t *testing.T) {
+	e, _ := NewEllipsoidInvF("Test body", 1000e3, 100)
+	e.GM = 1e10
+	if err := Register("test_register", e); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register("test_register", e); err == nil {
+		t.Error("expected error for duplicate model")
+	}
+	if err := Register("wgs84", e); err == nil {
+		t.Error("expected error for overriding built-in model")
+	}
+	got, err := NewEllipsoid("test_register")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Model != "test_register" || got.Flattening != 0.01 || got.GM != 1e10 || got.Name != "Test body" {
+		t.Errorf("registered ellipsoid: %+v", got)
+	}
+	// 返回副本，修改不影响注册表
+	got.SemimajorAxis = 1
+	if again, _ := NewEllipsoid("test_register"); again.SemimajorAxis != 1000e3 {
+		t.Error("NewEllipsoid should return a copy")
+	}
+
+	// 仅给出长短半轴时补全派生参数
+	if err := Register("test_register_ab", &Ellipsoid{SemimajorAxis: 2000, SemiminorAxis: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	ab, _ := NewEllipsoid("test_register_ab")
+	if ab.Flattening != 0.5 || math.Abs(ab.Eccentricity-math.Sqrt(0.75)) > 1e-15 {
+		t.Errorf("derived parameters not computed: %+v", ab)
+	}
+	if err := Register("test_register_bad", &Ellipsoid{SemimajorAxis: 1}); err == nil {
+		t.Error("expected error for invalid axes")
+	}
+}
@@ -44,8 +44,13 @@ func (enu *ENU) ToECEF(ref Geodetic) ECEF {
 
 // 转地心惯性坐标系
 func (enu *ENU) ToECI(ref Geodetic, t time.Time) ECI {
+	return enu.ToECIWith(ref, t, DefaultTransformer())
+}
+
+// ToECIWith 使用转换器 tr 转地心惯性坐标系
+func (enu *ENU) ToECIWith(ref Geodetic, t time.Time, tr *Transformer) ECI {
 	x, y, z := ENU2ECEF(enu.East, enu.North, enu.Up, ref.Latitude, ref.Longitude, ref.Altitude, enu.Ell)
-	xECI, yECI, zECI := ECEF2ECI(x, y, z, t)
+	xECI, yECI, zECI := tr.ECEF2ECI(x, y, z, t)
 	return ECI{
 		X:   xECI,
 		Y:   yECI,
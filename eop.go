@@ -0,0 +1,250 @@
+package gomap3d
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================
+// 地球定向参数 (Earth Orientation Parameters, EOP)
+//
+// 支持从本地磁盘读取 IERS 发布的两种常用格式:
+//   - finals2000A (finals2000A.all / finals2000A.data / finals2000A.daily)
+//   - EOP 14 C04 (eopc04_14_IAU2000.62-now)
+//
+// 插值得到任意时刻的 UT1-UTC、极移 xp/yp、天极偏差 dX/dY，
+// 供完整 IAU 归算链使用（UT1 恒星时 + 极移矩阵 W）。
+// 参考: IERS Conventions 2010, Chapter 5
+// ============================================================
+
+// mjdOffset 儒略日与简化儒略日之差 (MJD = JD - 2400000.5)
+const mjdOffset = 2400000.5
+
+// EOPRecord 单个时刻的地球定向参数
+type EOPRecord struct {
+	MJD    float64 // 简化儒略日 (UTC)
+	Xp, Yp float64 // 极移 (角秒)
+	UT1UTC float64 // UT1-UTC (秒)
+	LOD    float64 // 日长超出 86400 s 的部分 (秒)
+	DX, DY float64 // 天极偏差 dX/dY，相对 IAU 2000A 模型 (角秒)
+}
+
+// EOPTable 按 MJD 升序排列的 EOP 序列
+type EOPTable struct {
+	Records []EOPRecord
+}
+
+// LoadFinals2000A 从本地文件读取 IERS finals2000A 格式的 EOP 数据
+func LoadFinals2000A(path string) (*EOPTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseFinals2000A(f)
+}
+
+// ParseFinals2000A 解析 finals2000A 定长格式（使用 Bulletin A 列）
+// 列定义见 IERS readme.finals2000A:
+//
+//	8-15 MJD, 19-27 PM-x ("), 38-46 PM-y ("), 59-68 UT1-UTC (s),
+//	80-86 LOD (ms), 98-106 dX (mas), 117-125 dY (mas)
+//
+// 没有 UT1-UTC 值的行（超出预报范围）被跳过。
+func ParseFinals2000A(r io.Reader) (*EOPTable, error) {
+	var recs []EOPRecord
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		s := sc.Text()
+		if len(strings.TrimSpace(s)) == 0 {
+			continue
+		}
+		mjd, ok, err := fixedFloat(s, 7, 15)
+		if err != nil || !ok {
+			return nil, fmt.Errorf("finals2000A line %d: invalid MJD", line)
+		}
+		ut1, ok, err := fixedFloat(s, 58, 68)
+		if err != nil {
+			return nil, fmt.Errorf("finals2000A line %d: invalid UT1-UTC: %v", line, err)
+		}
+		if !ok {
+			continue
+		}
+		rec := EOPRecord{MJD: mjd, UT1UTC: ut1}
+		if rec.Xp, _, err = fixedFloat(s, 18, 27); err != nil {
+			return nil, fmt.Errorf("finals2000A line %d: invalid PM-x: %v", line, err)
+		}
+		if rec.Yp, _, err = fixedFloat(s, 37, 46); err != nil {
+			return nil, fmt.Errorf("finals2000A line %d: invalid PM-y: %v", line, err)
+		}
+		lod, _, err := fixedFloat(s, 79, 86)
+		if err != nil {
+			return nil, fmt.Errorf("finals2000A line %d: invalid LOD: %v", line, err)
+		}
+		dx, _, err := fixedFloat(s, 97, 106)
+		if err != nil {
+			return nil, fmt.Errorf("finals2000A line %d: invalid dX: %v", line, err)
+		}
+		dy, _, err := fixedFloat(s, 116, 125)
+		if err != nil {
+			return nil, fmt.Errorf("finals2000A line %d: invalid dY: %v", line, err)
+		}
+		// ms → s, mas → "
+		rec.LOD = lod * 1e-3
+		rec.DX = dx * 1e-3
+		rec.DY = dy * 1e-3
+		recs = append(recs, rec)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return newEOPTable(recs)
+}
+
+// LoadEOPC04 从本地文件读取 IERS EOP 14 C04 格式的 EOP 数据
+func LoadEOPC04(path string) (*EOPTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseEOPC04(f)
+}
+
+// ParseEOPC04 解析 EOP 14 C04 空格分隔格式
+// 每行: 年 月 日 MJD x(") y(") UT1-UTC(s) LOD(s) dX(") dY(") [误差列...]
+// 表头与说明行（首字段不是整数年份）被跳过。
+func ParseEOPC04(r io.Reader) (*EOPTable, error) {
+	var recs []EOPRecord
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			continue
+		}
+		var v [7]float64
+		for i := range v {
+			x, err := strconv.ParseFloat(fields[i+3], 64)
+			if err != nil {
+				return nil, fmt.Errorf("EOP C04 line %d: %v", line, err)
+			}
+			v[i] = x
+		}
+		recs = append(recs, EOPRecord{
+			MJD:    v[0],
+			Xp:     v[1],
+			Yp:     v[2],
+			UT1UTC: v[3],
+			LOD:    v[4],
+			DX:     v[5],
+			DY:     v[6],
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return newEOPTable(recs)
+}
+
+// newEOPTable 排序并检查 EOP 序列
+func newEOPTable(recs []EOPRecord) (*EOPTable, error) {
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("no EOP records found")
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].MJD < recs[j].MJD })
+	return &EOPTable{Records: recs}, nil
+}
+
+// fixedFloat 读取定长列 [start, end)，列为空时 ok=false
+func fixedFloat(s string, start, end int) (v float64, ok bool, err error) {
+	if start >= len(s) {
+		return 0, false, nil
+	}
+	if end > len(s) {
+		end = len(s)
+	}
+	field := strings.TrimSpace(s[start:end])
+	if field == "" {
+		return 0, false, nil
+	}
+	v, err = strconv.ParseFloat(field, 64)
+	return v, err == nil, err
+}
+
+// At 线性插值得到给定 MJD (UTC) 处的 EOP
+// UT1-UTC 在闰秒处有 1 s 跳变，插值前先消除跳变。
+func (tab *EOPTable) At(mjd float64) (EOPRecord, error) {
+	recs := tab.Records
+	n := len(recs)
+	if n == 0 {
+		return EOPRecord{}, fmt.Errorf("empty EOP table")
+	}
+	if mjd < recs[0].MJD || mjd > recs[n-1].MJD {
+		return EOPRecord{}, fmt.Errorf("MJD %.5f outside EOP table range [%.2f, %.2f]",
+			mjd, recs[0].MJD, recs[n-1].MJD)
+	}
+	i := sort.Search(n, func(i int) bool { return recs[i].MJD >= mjd })
+	if recs[i].MJD == mjd {
+		return recs[i], nil
+	}
+	a, b := recs[i-1], recs[i]
+	f := (mjd - a.MJD) / (b.MJD - a.MJD)
+	lerp := func(x, y float64) float64 { return x + f*(y-x) }
+
+	ut1b := b.UT1UTC
+	if d := ut1b - a.UT1UTC; math.Abs(d) > 0.5 {
+		ut1b -= math.Copysign(1, d)
+	}
+	return EOPRecord{
+		MJD:    mjd,
+		Xp:     lerp(a.Xp, b.Xp),
+		Yp:     lerp(a.Yp, b.Yp),
+		UT1UTC: lerp(a.UT1UTC, ut1b),
+		LOD:    lerp(a.LOD, b.LOD),
+		DX:     lerp(a.DX, b.DX),
+		DY:     lerp(a.DY, b.DY),
+	}, nil
+}
+
+// AtTime 插值得到 UTC 时刻 t 的 EOP
+func (tab *EOPTable) AtTime(t time.Time) (EOPRecord, error) {
+	return tab.At(juliandate(t.UTC()) - mjdOffset)
+}
+
+// SetEOP 设置默认转换器使用的 EOP 数据
+// 传入 nil 则关闭 EOP 修正（UT1=UTC，无极移、无天极偏差）。
+// 仅在完整模式时生效；超出表格范围的时刻按无 EOP 处理。
+func SetEOP(tab *EOPTable) {
+	updateDefaultOptions(func(o *Options) { o.EOP = tab })
+}
+
+// eopAt 取默认转换器 EOP 数据源在 t 时刻的值，不可用时返回零值
+func eopAt(t time.Time) EOPRecord {
+	return eopFrom(DefaultTransformer().opts.EOP, t)
+}
+
+// eopFrom 取 tab 在 UTC 时刻 t 的值，tab 为 nil 或超出范围时返回零值
+func eopFrom(tab *EOPTable, t time.Time) EOPRecord {
+	if tab == nil {
+		return EOPRecord{}
+	}
+	rec, err := tab.AtTime(t)
+	if err != nil {
+		return EOPRecord{}
+	}
+	return rec
+}
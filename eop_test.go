@@ -0,0 +1,126 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseFinals2000A(t *testing.T) {
+	tab, err := LoadFinals2000A("test_data/finals2000A_sample.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 最后一行没有 UT1-UTC（超出预报），应被跳过
+	if len(tab.Records) != 8 {
+		t.Fatalf("records = %d, want 8", len(tab.Records))
+	}
+	rec := tab.Records[5]
+	if rec.MJD != 60471 || rec.Xp != 0.171853 || rec.Yp != 0.430502 || rec.UT1UTC != 0.0055024 {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+	// LOD ms → s, dX/dY mas → "
+	if math.Abs(rec.LOD-(-0.1612e-3)) > 1e-12 || math.Abs(rec.DX-0.266e-3) > 1e-12 || math.Abs(rec.DY-(-0.128e-3)) > 1e-12 {
+		t.Errorf("unit conversion failed: %+v", rec)
+	}
+}
+
+func TestParseEOPC04MatchesFinals(t *testing.T) {
+	finals, err := LoadFinals2000A("test_data/finals2000A_sample.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c04, err := LoadEOPC04("test_data/eopc04_sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c04.Records) != len(finals.Records) {
+		t.Fatalf("records = %d, want %d", len(c04.Records), len(finals.Records))
+	}
+	for i := range c04.Records {
+		a, b := c04.Records[i], finals.Records[i]
+		if a.MJD != b.MJD || math.Abs(a.Xp-b.Xp) > 1e-9 || math.Abs(a.UT1UTC-b.UT1UTC) > 1e-9 ||
+			math.Abs(a.LOD-b.LOD) > 1e-9 || math.Abs(a.DX-b.DX) > 1e-9 || math.Abs(a.DY-b.DY) > 1e-9 {
+			t.Errorf("record %d mismatch:\n  c04    %+v\n  finals %+v", i, a, b)
+		}
+	}
+}
+
+func TestEOPInterpolation(t *testing.T) {
+	tab, err := LoadFinals2000A("test_data/finals2000A_sample.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2024-06-10 12:00 UTC 位于两个节点正中
+	rec, err := tab.AtTime(time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(rec.UT1UTC-(0.0055024+0.0056554)/2) > 1e-12 {
+		t.Errorf("UT1-UTC = %.9f", rec.UT1UTC)
+	}
+	if math.Abs(rec.Xp-(0.171853+0.173376)/2) > 1e-12 {
+		t.Errorf("xp = %.9f", rec.Xp)
+	}
+
+	// 跨 2016-12-31 闰秒：插值结果应在两侧取值之间连续变化，而不是跨越 1 s
+	rec, err = tab.At(57753.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := -0.4092627 + 0.5*((0.5897488-1)-(-0.4092627))
+	if math.Abs(rec.UT1UTC-want) > 1e-12 {
+		t.Errorf("UT1-UTC across leap second = %.7f, want %.7f", rec.UT1UTC, want)
+	}
+
+	if _, err := tab.At(50000); err == nil {
+		t.Error("expected out-of-range error")
+	}
+}
+
+func TestEOPFullChain(t *testing.T) {
+	tab, err := LoadFinals2000A("test_data/finals2000A_sample.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	noEOP, _ := NewTransformer(Options{Model: ModelIAU2006B})
+	withEOP, _ := NewTransformer(Options{Model: ModelIAU2006B, EOP: tab})
+
+	tUTC := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	p := [3]float64{-2.174e6, 4.389e6, 4.077e6}
+
+	xe0, ye0, ze0 := noEOP.ECI2ECEF(p[0], p[1], p[2], tUTC)
+	xe1, ye1, ze1 := withEOP.ECI2ECEF(p[0], p[1], p[2], tUTC)
+
+	// UT1-UTC ≈ 5.6 ms → 赤道约 2.6 m；极移 ≈ 0.46" → 约 14 m
+	d := vecDist([3]float64{xe0, ye0, ze0}, [3]float64{xe1, ye1, ze1})
+	t.Logf("EOP 修正量: %.3f m", d)
+	if d < 5 || d > 30 {
+		t.Errorf("unexpected EOP correction = %.3f m", d)
+	}
+
+	// 含 EOP 的往返
+	xi, yi, zi := withEOP.ECEF2ECI(xe1, ye1, ze1, tUTC)
+	if r := vecDist(p, [3]float64{xi, yi, zi}); r > epsFloat {
+		t.Errorf("roundtrip error with EOP = %.3e m", r)
+	}
+
+	// 表格范围外退化为无 EOP
+	tOut := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	xa, ya, za := noEOP.ECI2ECEF(p[0], p[1], p[2], tOut)
+	xb, yb, zb := withEOP.ECI2ECEF(p[0], p[1], p[2], tOut)
+	if vecDist([3]float64{xa, ya, za}, [3]float64{xb, yb, zb}) != 0 {
+		t.Error("out-of-range epoch should ignore EOP")
+	}
+}
+
+func TestPolarMotionMatrix(t *testing.T) {
+	xp, yp := 0.2*as2r, 0.4*as2r
+	W := polarMotionMatrix(xp, yp, 0.25)
+	// 小角近似: W ≈ [[1, 0, xp], [0, 1, -yp], [-xp, yp, 1]]
+	if math.Abs(W[0][2]-xp) > 1e-15 || math.Abs(W[1][2]+yp) > 1e-15 ||
+		math.Abs(W[2][0]+xp) > 1e-15 || math.Abs(W[2][1]-yp) > 1e-15 {
+		t.Errorf("unexpected polar motion matrix: %v", W)
+	}
+}
@@ -0,0 +1,109 @@
+package gomap3d
+
+import (
+	"math"
+	"time"
+)
+
+// ============================================================
+// 日月低精度解析星历
+//
+// Montenbruck & Gill, Satellite Orbits, 3.3.2：
+// 太阳位置精度约 0.1%，月球位置精度约数百公里，足以计算第三体摄动。
+// 结果为 J2000 平赤道坐标 (EME2000 ≈ GCRF)，单位 m。
+// ============================================================
+
+const (
+	// GMSun 太阳引力常数 (m³/s²，DE430)
+	GMSun = 1.32712440041e20
+	// GMMoon 月球引力常数 (m³/s²，DE430)
+	GMMoon = 4.9028000661e12
+
+	// obliquityJ2000 J2000 黄赤交角 (rad)
+	obliquityJ2000 = 23.43929111 * math.Pi / 180
+)
+
+// SunECI 时刻 t (UTC) 太阳在 J2000 地心惯性系中的位置 (m)
+func SunECI(t time.Time) (x, y, z float64) {
+	r := sunPosition(julianCenturiesTT(t))
+	return r[0], r[1], r[2]
+}
+
+// MoonECI 时刻 t (UTC) 月球在 J2000 地心惯性系中的位置 (m)
+func MoonECI(t time.Time) (x, y, z float64) {
+	r := moonPosition(julianCenturiesTT(t))
+	return r[0], r[1], r[2]
+}
+
+// julianCenturiesTT UTC 时刻对应的 J2000 起算 TT 儒略世纪数
+func julianCenturiesTT(t time.Time) float64 {
+	return (NewEpoch(t, UTC).to(TT, nil).JD() - 2451545.0) / 36525.0
+}
+
+// eclipticToEquatorial 黄道球坐标 (λ, β, r) → J2000 赤道直角坐标
+func eclipticToEquatorial(lon, lat, r float64) [3]float64 {
+	xe := r * math.Cos(lat) * math.Cos(lon)
+	ye := r * math.Cos(lat) * math.Sin(lon)
+	ze := r * math.Sin(lat)
+	// 绕 x 轴旋转 -ε
+	return multiplyMatrixVector(transpose(Rx(obliquityJ2000)), [3]float64{xe, ye, ze})
+}
+
+// sunPosition 太阳地心位置 (m)，T 为 TT 儒略世纪数
+func sunPosition(T float64) [3]float64 {
+	const as = math.Pi / (180 * 3600)
+	m := (357.5256 + 35999.049*T) * math.Pi / 180
+	lon := (282.9400+357.5256+35999.049*T)*math.Pi/180 + (6892*math.Sin(m)+72*math.Sin(2*m))*as
+	r := (149.619 - 2.499*math.Cos(m) - 0.021*math.Cos(2*m)) * 1e9
+	return eclipticToEquatorial(lon, 0, r)
+}
+
+// moonPosition 月球地心位置 (m)，T 为 TT 儒略世纪数
+func moonPosition(T float64) [3]float64 {
+	const (
+		deg = math.Pi / 180
+		as  = deg / 3600
+	)
+	// 平黄经 (含岁差改正至 J2000 黄道)、平近点角、太阳平近点角、升交角距、平距角
+	L0 := (218.31617 + 481267.88088*T - 1.3972*T) * deg
+	l := (134.96292 + 477198.86753*T) * deg
+	lp := (357.52543 + 35999.04944*T) * deg
+	F := (93.27283 + 483202.01873*T) * deg
+	D := (297.85027 + 445267.11135*T) * deg
+
+	lon := L0 + (22640*math.Sin(l)+769*math.Sin(2*l)-
+		4586*math.Sin(l-2*D)+2370*math.Sin(2*D)-
+		668*math.Sin(lp)-412*math.Sin(2*F)-
+		212*math.Sin(2*l-2*D)-206*math.Sin(l+lp-2*D)+
+		192*math.Sin(l+2*D)-165*math.Sin(lp-2*D)+
+		148*math.Sin(l-lp)-125*math.Sin(D)-
+		110*math.Sin(l+lp)-55*math.Sin(2*F-2*D))*as
+
+	lat := (18520*math.Sin(F+lon-L0+(412*math.Sin(2*F)+541*math.Sin(lp))*as) -
+		526*math.Sin(F-2*D) + 44*math.Sin(l+F-2*D) -
+		31*math.Sin(-l+F-2*D) - 25*math.Sin(-2*l+F) -
+		23*math.Sin(lp+F-2*D) + 21*math.Sin(-l+F) +
+		11*math.Sin(-lp+F-2*D)) * as
+
+	r := (385000 - 20905*math.Cos(l) - 3699*math.Cos(2*D-l) -
+		2956*math.Cos(2*D) - 570*math.Cos(2*l) +
+		246*math.Cos(2*l-2*D) - 205*math.Cos(lp-2*D) -
+		171*math.Cos(l+2*D) - 152*math.Cos(l+lp-2*D)) * 1e3
+	return eclipticToEquatorial(lon, lat, r)
+}
+
+// thirdBodyAccel 第三体对地心参考系中位置 r 处目标的摄动加速度 (m/s²)
+func thirdBodyAccel(r, s [3]float64, gm float64) [3]float64 {
+	var d [3]float64
+	for i := range d {
+		d[i] = s[i] - r[i]
+	}
+	dn := norm3(d)
+	sn := norm3(s)
+	d3, s3 := dn*dn*dn, sn*sn*sn
+	var a [3]float64
+	for i := range a {
+		a[i] = gm * (d[i]/d3 - s[i]/s3)
+	}
+	return a
+}
@@ -0,0 +1,248 @@
+package gomap3d
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================================
+// 春分点根数 (Broucke & Cefola 1972) 与修正春分点根数 (Walker 1985)
+//
+// 两者在圆轨道与赤道轨道处均无奇异，适合近圆、近赤道轨道。
+// 采用逆行因子 I：Retrograde 为 true 时 I = -1，tan(i/2) 换为 cot(i/2)，
+// 近点经度 ϖ = ω + I·Ω，仅在 i = 0°（逆行）或 i = 180°（顺行）处奇异。
+// 由状态矢量计算时按 i > 90° 自动选择逆行形式。
+// ============================================================
+
+// Equinoctial 春分点根数（仅椭圆轨道）
+type Equinoctial struct {
+	A             float64 // 半长轴 a (m)
+	H             float64 // e·sin(ϖ)
+	K             float64 // e·cos(ϖ)
+	P             float64 // tan^I(i/2)·sin(Ω)
+	Q             float64 // tan^I(i/2)·cos(Ω)
+	MeanLongitude float64 // 平经度 λ = M + ϖ (°)
+	Retrograde    bool    // 逆行因子 I = -1
+	GM            float64 // 中心天体引力常数 μ (m³/s²)
+}
+
+// ModifiedEquinoctial 修正春分点根数（适用于任意圆锥曲线）
+type ModifiedEquinoctial struct {
+	P             float64 // 半通径 p (m)
+	F             float64 // e·cos(ϖ)
+	G             float64 // e·sin(ϖ)
+	H             float64 // tan^I(i/2)·cos(Ω)
+	K             float64 // tan^I(i/2)·sin(Ω)
+	TrueLongitude float64 // 真经度 L = ν + ϖ (°)
+	Retrograde    bool    // 逆行因子 I = -1
+	GM            float64 // 中心天体引力常数 μ (m³/s²)
+}
+
+// retroFactor 逆行因子 I
+func retroFactor(retrograde bool) float64 {
+	if retrograde {
+		return -1
+	}
+	return 1
+}
+
+// equinoctialFrame 春分点坐标系基矢量 f̂、ĝ
+// 参数 p = tan^I(i/2)·sinΩ，q = tan^I(i/2)·cosΩ
+func equinoctialFrame(p, q, fr float64) (f, g [3]float64) {
+	s := 1 + p*p + q*q
+	f = [3]float64{(1 - p*p + q*q) / s, 2 * p * q / s, -2 * p * fr / s}
+	g = [3]float64{2 * p * q * fr / s, (1 + p*p - q*q) * fr / s, 2 * q / s}
+	return f, g
+}
+
+// equinoctialState 由状态矢量求春分点坐标系参数
+// 返回 p、q、逆行标志、基矢量、偏心率矢量与角动量大小
+func equinoctialState(x, y, z, vx, vy, vz, gm float64) (p, q float64, retro bool, f, g, ev [3]float64, hn float64, err error) {
+	if gm <= 0 {
+		err = fmt.Errorf("orbit: GM must be positive")
+		return
+	}
+	r := [3]float64{x, y, z}
+	v := [3]float64{vx, vy, vz}
+	rn := norm3(r)
+	h := cross3(r, v)
+	hn = norm3(h)
+	if rn == 0 || hn < orbitTol*rn*norm3(v) {
+		err = fmt.Errorf("orbit: degenerate (rectilinear) state")
+		return
+	}
+	w := [3]float64{h[0] / hn, h[1] / hn, h[2] / hn}
+	retro = w[2] < 0
+	fr := retroFactor(retro)
+	p = w[0] / (1 + fr*w[2])
+	q = -w[1] / (1 + fr*w[2])
+	f, g = equinoctialFrame(p, q, fr)
+
+	rv := dot3(r, v)
+	c1 := dot3(v, v) - gm/rn
+	for i := range ev {
+		ev[i] = (c1*r[i] - rv*v[i]) / gm
+	}
+	return
+}
+
+// ModifiedEquinoctialFromState 由惯性系位置 (m)、速度 (m/s) 计算修正春分点根数
+func ModifiedEquinoctialFromState(x, y, z, vx, vy, vz, gm float64) (ModifiedEquinoctial, error) {
+	p, q, retro, f, g, ev, hn, err := equinoctialState(x, y, z, vx, vy, vz, gm)
+	if err != nil {
+		return ModifiedEquinoctial{}, err
+	}
+	r := [3]float64{x, y, z}
+	L := math.Atan2(dot3(r, g), dot3(r, f))
+	return ModifiedEquinoctial{
+		P:             hn * hn / gm,
+		F:             dot3(ev, f),
+		G:             dot3(ev, g),
+		H:             q,
+		K:             p,
+		TrueLongitude: wrapDeg(L * 180 / math.Pi),
+		Retrograde:    retro,
+		GM:            gm,
+	}, nil
+}
+
+// ToState 修正春分点根数 → 惯性系位置 (m) 与速度 (m/s)
+func (m ModifiedEquinoctial) ToState() (x, y, z, vx, vy, vz float64, err error) {
+	if m.GM <= 0 || !(m.P > 0) {
+		return 0, 0, 0, 0, 0, 0, fmt.Errorf("orbit: invalid modified equinoctial elements p=%g GM=%g", m.P, m.GM)
+	}
+	L := m.TrueLongitude * math.Pi / 180
+	sinL, cosL := math.Sin(L), math.Cos(L)
+	w := 1 + m.F*cosL + m.G*sinL
+	if w <= 0 {
+		return 0, 0, 0, 0, 0, 0, fmt.Errorf("orbit: true longitude %g° beyond hyperbolic asymptote", m.TrueLongitude)
+	}
+	rn := m.P / w
+	sq := math.Sqrt(m.GM / m.P)
+	f, g := equinoctialFrame(m.K, m.H, retroFactor(m.Retrograde))
+	var r, v [3]float64
+	for i := range r {
+		r[i] = rn * (cosL*f[i] + sinL*g[i])
+		v[i] = sq * (-(m.G+sinL)*f[i] + (m.F+cosL)*g[i])
+	}
+	return r[0], r[1], r[2], v[0], v[1], v[2], nil
+}
+
+// EquinoctialFromState 由惯性系位置 (m)、速度 (m/s) 计算春分点根数，仅限椭圆轨道
+func EquinoctialFromState(x, y, z, vx, vy, vz, gm float64) (Equinoctial, error) {
+	p, q, retro, f, g, ev, _, err := equinoctialState(x, y, z, vx, vy, vz, gm)
+	if err != nil {
+		return Equinoctial{}, err
+	}
+	r := [3]float64{x, y, z}
+	v := [3]float64{vx, vy, vz}
+	energy := dot3(v, v)/2 - gm/norm3(r)
+	if energy >= 0 {
+		return Equinoctial{}, fmt.Errorf("orbit: equinoctial elements require an elliptic orbit")
+	}
+	a := -gm / (2 * energy)
+	k := dot3(ev, f)
+	h := dot3(ev, g)
+
+	// 偏经度 F，由春分点坐标系内的坐标 X1、Y1 直接求出
+	x1 := dot3(r, f)
+	y1 := dot3(r, g)
+	beta := 1 / (1 + math.Sqrt(1-h*h-k*k))
+	den := a * math.Sqrt(1-h*h-k*k)
+	sinF := h + ((1-h*h*beta)*y1-h*k*beta*x1)/den
+	cosF := k + ((1-k*k*beta)*x1-h*k*beta*y1)/den
+	F := math.Atan2(sinF, cosF)
+	lambda := F + h*math.Cos(F) - k*math.Sin(F)
+
+	return Equinoctial{
+		A:             a,
+		H:             h,
+		K:             k,
+		P:             p,
+		Q:             q,
+		MeanLongitude: wrapDeg(lambda * 180 / math.Pi),
+		Retrograde:    retro,
+		GM:            gm,
+	}, nil
+}
+
+// EccentricLongitude 由平经度求偏经度 F = E + ϖ (rad)
+// 求解广义开普勒方程 λ = F + H·cosF - K·sinF
+func (eq Equinoctial) EccentricLongitude() float64 {
+	lambda := eq.MeanLongitude * math.Pi / 180
+	F := lambda
+	for i := 0; i < 50; i++ {
+		sinF, cosF := math.Sin(F), math.Cos(F)
+		fv := F + eq.H*cosF - eq.K*sinF - lambda
+		d := fv / (1 - eq.H*sinF - eq.K*cosF)
+		F -= d
+		if math.Abs(d) < 1e-15 {
+			break
+		}
+	}
+	return F
+}
+
+// ToState 春分点根数 → 惯性系位置 (m) 与速度 (m/s)
+func (eq Equinoctial) ToState() (x, y, z, vx, vy, vz float64, err error) {
+	e2 := eq.H*eq.H + eq.K*eq.K
+	if eq.GM <= 0 || !(eq.A > 0) || e2 >= 1 {
+		return 0, 0, 0, 0, 0, 0, fmt.Errorf("orbit: invalid equinoctial elements a=%g e=%g", eq.A, math.Sqrt(e2))
+	}
+	F := eq.EccentricLongitude()
+	sinF, cosF := math.Sin(F), math.Cos(F)
+	h, k, a := eq.H, eq.K, eq.A
+	beta := 1 / (1 + math.Sqrt(1-e2))
+	n := math.Sqrt(eq.GM / (a * a * a))
+	rn := a * (1 - k*cosF - h*sinF)
+
+	x1 := a * ((1-h*h*beta)*cosF + h*k*beta*sinF - k)
+	y1 := a * ((1-k*k*beta)*sinF + h*k*beta*cosF - h)
+	c := n * a * a / rn
+	vx1 := c * (h*k*beta*cosF - (1-h*h*beta)*sinF)
+	vy1 := c * ((1-k*k*beta)*cosF - h*k*beta*sinF)
+
+	f, g := equinoctialFrame(eq.P, eq.Q, retroFactor(eq.Retrograde))
+	var r, v [3]float64
+	for i := range r {
+		r[i] = x1*f[i] + y1*g[i]
+		v[i] = vx1*f[i] + vy1*g[i]
+	}
+	return r[0], r[1], r[2], v[0], v[1], v[2], nil
+}
+
+// ToEquinoctial 经典根数 → 春分点根数
+func (el OrbitalElements) ToEquinoctial() (Equinoctial, error) {
+	x, y, z, vx, vy, vz, err := el.ToState()
+	if err != nil {
+		return Equinoctial{}, err
+	}
+	return EquinoctialFromState(x, y, z, vx, vy, vz, el.GM)
+}
+
+// ToModifiedEquinoctial 经典根数 → 修正春分点根数
+func (el OrbitalElements) ToModifiedEquinoctial() (ModifiedEquinoctial, error) {
+	x, y, z, vx, vy, vz, err := el.ToState()
+	if err != nil {
+		return ModifiedEquinoctial{}, err
+	}
+	return ModifiedEquinoctialFromState(x, y, z, vx, vy, vz, el.GM)
+}
+
+// ToElements 春分点根数 → 经典根数
+func (eq Equinoctial) ToElements() (OrbitalElements, error) {
+	x, y, z, vx, vy, vz, err := eq.ToState()
+	if err != nil {
+		return OrbitalElements{}, err
+	}
+	return StateToElements(x, y, z, vx, vy, vz, eq.GM)
+}
+
+// ToElements 修正春分点根数 → 经典根数
+func (m ModifiedEquinoctial) ToElements() (OrbitalElements, error) {
+	x, y, z, vx, vy, vz, err := m.ToState()
+	if err != nil {
+		return OrbitalElements{}, err
+	}
+	return StateToElements(x, y, z, vx, vy, vz, m.GM)
+}
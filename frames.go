@@ -0,0 +1,202 @@
+package gomap3d
+
+import (
+	"fmt"
+	"time"
+)
+
+// ============================================================
+// 命名参考坐标系及其相互转换
+//
+//   GCRF     地心天球参考系
+//   EME2000  J2000 平赤道平春分点         = B · GCRF
+//   MOD      瞬时平赤道平春分点           = P · EME2000
+//   TOD      瞬时真赤道真春分点           = N · MOD
+//   TEME     真赤道平春分点 (SGP4 输出)   = Rz(GAST - GMST82) · TOD
+//   PEF      伪地固系 (不含极移)          = Rz(GAST) · TOD = Rz(GMST82) · TEME
+//   ITRF     国际地球参考框架             = W · PEF
+//
+// 岁差章动、EOP 与转换器完整模式相同（IAU 2006/2000B 或 2000A，
+// UT1-UTC、极移、天极偏差），不受 ModelGMST 影响。
+// TEME 按 Vallado (2006) 约定由 GMST1982 定义，可直接用于 SGP4 结果。
+// 参考: Vallado, Fundamentals of Astrodynamics and Applications, 4th ed., 3.7
+// ============================================================
+
+// Frame 参考坐标系
+type Frame int
+
+const (
+	GCRF    Frame = iota // 地心天球参考系
+	EME2000              // J2000 平赤道平春分点
+	MOD                  // 瞬时平赤道平春分点
+	TOD                  // 瞬时真赤道真春分点
+	TEME                 // 真赤道平春分点
+	PEF                  // 伪地固系
+	ITRF                 // 国际地球参考框架
+)
+
+// J2000 EME2000 的别名
+const J2000 = EME2000
+
+func (f Frame) String() string {
+	switch f {
+	case GCRF:
+		return "GCRF"
+	case EME2000:
+		return "EME2000"
+	case MOD:
+		return "MOD"
+	case TOD:
+		return "TOD"
+	case TEME:
+		return "TEME"
+	case PEF:
+		return "PEF"
+	case ITRF:
+		return "ITRF"
+	}
+	return fmt.Sprintf("Frame(%d)", int(f))
+}
+
+// rotating 是否为随地球旋转的坐标系
+func (f Frame) rotating() bool {
+	return f == PEF || f == ITRF
+}
+
+// frameSet UTC 时刻 t 各坐标系相对 GCRF 的旋转矩阵
+type frameSet struct {
+	toFrame [ITRF + 1][3][3]float64 // r_f = toFrame[f] · r_GCRF
+	w       [3][3]float64           // 极移矩阵 W (PEF → ITRF)
+	omega   float64                 // 地球自转角速度 (rad/s)
+}
+
+// frameSet 计算 UTC 时刻 utc 的各坐标系矩阵
+func (tr *Transformer) frameSet(utc time.Time) *frameSet {
+	eop := eopFrom(tr.opts.EOP, utc)
+	jdTT := NewEpoch(utc, UTC).to(TT, tr.opts.EOP).JD()
+	jdUT1 := juliandate(utc) + eop.UT1UTC/86400.0
+	T := (jdTT - 2451545.0) / 36525.0
+
+	dpsi, deps := tr.nutation(T)
+	ddpsi, ddeps := cipOffsetNutation(T, eop)
+	dpsi += ddpsi
+	deps += ddeps
+
+	fs := &frameSet{
+		w:     polarMotionMatrix(eop.Xp*as2r, eop.Yp*as2r, T),
+		omega: We * (1 - eop.LOD/86400.0),
+	}
+	gast := gastFrom(jdUT1, T, dpsi)
+	m := &fs.toFrame
+	m[GCRF] = R3(0)
+	m[EME2000] = frameBiasMatrix()
+	m[MOD] = mul33(precessionMatrix(T), m[EME2000])
+	m[TOD] = mul33(nutationMatrixFrom(dpsi, deps, meanObliquity(T)), m[MOD])
+	m[TEME] = mul33(R3(gast-greenwichsrt(jdUT1)), m[TOD])
+	m[PEF] = mul33(R3(gast), m[TOD])
+	m[ITRF] = mul33(fs.w, m[PEF])
+	return fs
+}
+
+// checkFrames 检查参考系是否有效
+func checkFrames(frames ...Frame) error {
+	for _, f := range frames {
+		if f < GCRF || f > ITRF {
+			return fmt.Errorf("unknown frame %v", f)
+		}
+	}
+	return nil
+}
+
+// toGCRF 将 f 系中的位置、速度转换到 GCRF
+// 旋转系速度: v_GCRF = M_PEF^T · (v_PEF + ω × r_PEF)，v_PEF = W^T · v_ITRF
+func (fs *frameSet) toGCRF(f Frame, r, v [3]float64) (rg, vg [3]float64) {
+	M := transpose(fs.toFrame[f])
+	rg = multiplyMatrixVector(M, r)
+	if !f.rotating() {
+		return rg, multiplyMatrixVector(M, v)
+	}
+	rPEF := multiplyMatrixVector(fs.toFrame[PEF], rg)
+	vPEF := v
+	if f == ITRF {
+		vPEF = multiplyMatrixVector(transpose(fs.w), v)
+	}
+	vPEF[0] -= fs.omega * rPEF[1]
+	vPEF[1] += fs.omega * rPEF[0]
+	vg = multiplyMatrixVector(transpose(fs.toFrame[PEF]), vPEF)
+	return
+}
+
+// fromGCRF 将 GCRF 中的位置、速度转换到 f 系
+// 旋转系速度: v_PEF = M_PEF · v_GCRF - ω × r_PEF，v_ITRF = W · v_PEF
+func (fs *frameSet) fromGCRF(f Frame, rg, vg [3]float64) (r, v [3]float64) {
+	r = multiplyMatrixVector(fs.toFrame[f], rg)
+	if !f.rotating() {
+		return r, multiplyMatrixVector(fs.toFrame[f], vg)
+	}
+	rPEF := multiplyMatrixVector(fs.toFrame[PEF], rg)
+	v = multiplyMatrixVector(fs.toFrame[PEF], vg)
+	v[0] += fs.omega * rPEF[1]
+	v[1] -= fs.omega * rPEF[0]
+	if f == ITRF {
+		v = multiplyMatrixVector(fs.w, v)
+	}
+	return
+}
+
+// FrameMatrix UTC 时刻 t 从 from 系到 to 系的旋转矩阵: r_to = M · r_from（默认转换器）
+func FrameMatrix(from, to Frame, t time.Time) ([3][3]float64, error) {
+	return DefaultTransformer().FrameMatrix(from, to, t)
+}
+
+// TransformFrame 将 UTC 时刻 t 的位置 (m) 从 from 系转换到 to 系（默认转换器）
+func TransformFrame(x, y, z float64, from, to Frame, t time.Time) (xo, yo, zo float64, err error) {
+	return DefaultTransformer().TransformFrame(x, y, z, from, to, t)
+}
+
+// TransformFrameVel 将 UTC 时刻 t 的位置 (m)、速度 (m/s) 从 from 系转换到 to 系（默认转换器）
+func TransformFrameVel(x, y, z, vx, vy, vz float64, from, to Frame, t time.Time) (xo, yo, zo, vxo, vyo, vzo float64, err error) {
+	return DefaultTransformer().TransformFrameVel(x, y, z, vx, vy, vz, from, to, t)
+}
+
+// FrameMatrix 时刻 t 从 from 系到 to 系的旋转矩阵: r_to = M · r_from
+func (tr *Transformer) FrameMatrix(from, to Frame, t time.Time) ([3][3]float64, error) {
+	if err := checkFrames(from, to); err != nil {
+		return [3][3]float64{}, err
+	}
+	fs := tr.frameSet(tr.utc(t))
+	return mul33(fs.toFrame[to], transpose(fs.toFrame[from])), nil
+}
+
+// TransformFrame 将时刻 t 的位置 (m) 从 from 系转换到 to 系
+func (tr *Transformer) TransformFrame(x, y, z float64, from, to Frame, t time.Time) (xo, yo, zo float64, err error) {
+	M, err := tr.FrameMatrix(from, to, t)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	r := multiplyMatrixVector(M, [3]float64{x, y, z})
+	return r[0], r[1], r[2], nil
+}
+
+// TransformFrameVel 将时刻 t 的位置 (m)、速度 (m/s) 从 from 系转换到 to 系
+// 旋转系 (PEF、ITRF) 与非旋转系之间计入地球自转 ω × r；
+// 岁差章动与极移的变化率引起的速度项 (< 1 mm/s) 忽略不计。
+func (tr *Transformer) TransformFrameVel(x, y, z, vx, vy, vz float64, from, to Frame, t time.Time) (xo, yo, zo, vxo, vyo, vzo float64, err error) {
+	if err = checkFrames(from, to); err != nil {
+		return
+	}
+	fs := tr.frameSet(tr.utc(t))
+	rg, vg := fs.toGCRF(from, [3]float64{x, y, z}, [3]float64{vx, vy, vz})
+	r, v := fs.fromGCRF(to, rg, vg)
+	return r[0], r[1], r[2], v[0], v[1], v[2], nil
+}
+
+// TEME2ECEF TEME → ITRF 位置转换，常用于 SGP4 输出
+func TEME2ECEF(x, y, z float64, t time.Time) (xEcef, yEcef, zEcef float64) {
+	return DefaultTransformer().TEME2ECEF(x, y, z, t)
+}
+
+// ECEF2TEME ITRF → TEME 位置转换
+func ECEF2TEME(x, y, z float64, t time.Time) (xTeme, yTeme, zTeme float64) {
+	return DefaultTransformer().ECEF2TEME(x, y, z, t)
+}
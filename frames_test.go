@@ -0,0 +1,117 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// valladoEOP Vallado (2006) 算例 2004-04-06 的 EOP
+func valladoEOP() *EOPTable {
+	rec := EOPRecord{Xp: -0.140682, Yp: 0.333309, UT1UTC: -0.4399619, LOD: 0.0015563, DX: -0.000205, DY: -0.000136}
+	a, b := rec, rec
+	a.MJD, b.MJD = 53101, 53102
+	return &EOPTable{Records: []EOPRecord{a, b}}
+}
+
+var valladoEpoch = time.Date(2004, 4, 6, 7, 51, 28, 386009000, time.UTC)
+
+func TestFrameValladoExample(t *testing.T) {
+	SetEOP(valladoEOP())
+	defer SetEOP(nil)
+
+	rITRF := [3]float64{-1033479.3830, 7901295.2754, 6380356.5958}
+	vITRF := [3]float64{-3225.636520, -2872.451450, 5531.924446}
+
+	tests := []struct {
+		frame Frame
+		r, v  [3]float64
+		tolR  float64 // m
+		tolV  float64 // m/s，Vallado 取 ω = 7.29211514670698e-5 rad/s，与 We 相差约 1e-5 m/s
+	}{
+		{PEF, [3]float64{-1033475.0313, 7901305.5856, 6380344.5328}, [3]float64{-3225.632747, -2872.442511, 5531.931288}, 0.01, 5e-5},
+		{TEME, [3]float64{5094180.1621, 6127644.6595, 6380344.5327}, [3]float64{-4746.131487, 785.818041, 5531.931288}, 0.01, 5e-5},
+		{GCRF, [3]float64{5102508.959, 6123011.403, 6378136.925}, [3]float64{-4743.22016, 790.53650, 5533.75528}, 0.1, 1e-3},
+	}
+	for _, tc := range tests {
+		x, y, z, vx, vy, vz, err := TransformFrameVel(rITRF[0], rITRF[1], rITRF[2], vITRF[0], vITRF[1], vITRF[2], ITRF, tc.frame, valladoEpoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := vecDist([3]float64{x, y, z}, tc.r); d > tc.tolR {
+			t.Errorf("ITRF → %v position error = %.4f m (%.4f, %.4f, %.4f)", tc.frame, d, x, y, z)
+		}
+		if d := vecDist([3]float64{vx, vy, vz}, tc.v); d > tc.tolV {
+			t.Errorf("ITRF → %v velocity error = %.2e m/s (%.6f, %.6f, %.6f)", tc.frame, d, vx, vy, vz)
+		}
+	}
+}
+
+func TestFrameRoundtrip(t *testing.T) {
+	SetEOP(valladoEOP())
+	defer SetEOP(nil)
+
+	frames := []Frame{GCRF, EME2000, MOD, TOD, TEME, PEF, ITRF}
+	r := [3]float64{5102508.959, 6123011.403, 6378136.925}
+	v := [3]float64{-4743.22016, 790.53650, 5533.75528}
+	for _, from := range frames {
+		for _, to := range frames {
+			x, y, z, vx, vy, vz, _ := TransformFrameVel(r[0], r[1], r[2], v[0], v[1], v[2], from, to, valladoEpoch)
+			x, y, z, vx, vy, vz, err := TransformFrameVel(x, y, z, vx, vy, vz, to, from, valladoEpoch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := vecDist(r, [3]float64{x, y, z}); d > 1e-6 {
+				t.Errorf("%v → %v → %v position error = %.3e m", from, to, from, d)
+			}
+			if d := vecDist(v, [3]float64{vx, vy, vz}); d > 1e-9 {
+				t.Errorf("%v → %v → %v velocity error = %.3e m/s", from, to, from, d)
+			}
+		}
+	}
+}
+
+func TestFrameConsistency(t *testing.T) {
+	SetGMSTMode(false)
+	defer SetGMSTMode(true)
+
+	tUTC := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	p := [3]float64{-2.174e6, 4.389e6, 4.077e6}
+
+	// GCRF → ITRF 与完整模式 ECI2ECEF 一致
+	x1, y1, z1, err := TransformFrame(p[0], p[1], p[2], GCRF, ITRF, tUTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x2, y2, z2 := ECI2ECEF(p[0], p[1], p[2], tUTC)
+	if d := vecDist([3]float64{x1, y1, z1}, [3]float64{x2, y2, z2}); d > 1e-6 {
+		t.Errorf("GCRF → ITRF differs from ECI2ECEF by %.3e m", d)
+	}
+
+	// 分步复合与直接转换一致
+	frameMatrix := func(from, to Frame) [3][3]float64 {
+		M, err := FrameMatrix(from, to, tUTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return M
+	}
+	M := mul33(frameMatrix(TOD, PEF), mul33(frameMatrix(MOD, TOD), frameMatrix(EME2000, MOD)))
+	checkMatrix(t, "EME2000→PEF", M, frameMatrix(EME2000, PEF), 1e-14)
+
+	// TEME 与 TOD 仅相差绕 Z 轴的分点差补偿，极轴一致
+	T := frameMatrix(TOD, TEME)
+	if math.Abs(T[2][2]-1) > 1e-15 || math.Abs(T[0][2]) > 1e-15 || math.Abs(T[1][2]) > 1e-15 {
+		t.Errorf("TOD → TEME is not a z rotation: %v", T)
+	}
+
+	if J2000.String() != "EME2000" || Frame(42).String() != "Frame(42)" {
+		t.Errorf("unexpected frame names")
+	}
+	if _, err := FrameMatrix(GCRF, Frame(42), tUTC); err == nil {
+		t.Error("expected error for unknown frame")
+	}
+	if _, _, _, _, _, _, err := TransformFrameVel(p[0], p[1], p[2], 0, 0, 0, Frame(-1), ITRF, tUTC); err == nil {
+		t.Error("expected error for unknown frame")
+	}
+}
@@ -0,0 +1,982 @@
+package gomap3d
+
+import "math"
+
+// ============================================================
+// 椭球大地线
+//
+// Karney 算法，级数展开至 6 阶，WGS84 上精度约 15 nm，对跖点附近同样收敛。
+// 参考: Karney, Algorithms for geodesics, J. Geodesy 87 (2013) 43–55；
+// 实现对照 GeographicLib geodesic.c。
+// 纬度、经度与方位以度为单位，方位自北顺时针；距离单位为米。
+// ============================================================
+
+const (
+	geodOrder = 6
+	nA1       = geodOrder
+	nC1       = geodOrder
+	nC1p      = geodOrder
+	nA2       = geodOrder
+	nC2       = geodOrder
+	nA3       = geodOrder
+	nA3x      = nA3
+	nC3       = geodOrder
+	nC3x      = (nC3 * (nC3 - 1)) / 2
+	nC4       = geodOrder
+	nC4x      = (nC4 * (nC4 + 1)) / 2
+	nC        = geodOrder + 1
+
+	geodMaxit1 = 20
+	geodMaxit2 = geodMaxit1 + 53 + 10
+)
+
+var (
+	geodTiny    = math.Sqrt(0x1p-1022) // √(最小正规数)
+	geodTol0    = 0x1p-52              // 机器精度
+	geodTol1    = 200 * geodTol0
+	geodTol2    = math.Sqrt(geodTol0)
+	geodTolb    = geodTol0 * geodTol2
+	geodXthresh = 1000 * geodTol2
+)
+
+// Geodesic 椭球大地线解算器
+type Geodesic struct {
+	Ell *Ellipsoid
+
+	a, f, f1, e2, ep2, n, b, c2, etol2 float64
+	a3x                                [nA3x]float64
+	c3x                                [nC3x]float64
+	c4x                                [nC4x]float64
+}
+
+// NewGeodesic 创建椭球 ell 上的大地线解算器
+func NewGeodesic(ell *Ellipsoid) *Geodesic {
+	g := &Geodesic{Ell: ell, a: ell.SemimajorAxis, f: ell.Flattening}
+	g.f1 = 1 - g.f
+	g.e2 = g.f * (2 - g.f)
+	g.ep2 = g.e2 / (g.f1 * g.f1)
+	g.n = g.f / (2 - g.f)
+	g.b = g.a * g.f1
+	var r float64
+	switch {
+	case g.e2 == 0:
+		r = 1
+	case g.e2 > 0:
+		r = math.Atanh(math.Sqrt(g.e2)) / math.Sqrt(g.e2)
+	default:
+		r = math.Atan(math.Sqrt(-g.e2)) / math.Sqrt(-g.e2)
+	}
+	g.c2 = (g.a*g.a + g.b*g.b*r) / 2
+	g.etol2 = 0.1 * geodTol2 / math.Sqrt(math.Max(0.001, math.Abs(g.f))*math.Min(1, 1-g.f/2)/2)
+	g.a3coeff()
+	g.c3coeff()
+	g.c4coeff()
+	return g
+}
+
+// ---------------- 角度与级数工具 ----------------
+
+// sumx 无误差求和，返回 s = u + v 与舍入误差 t
+func sumx(u, v float64) (s, t float64) {
+	s = u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	t = -(up + vpp)
+	return
+}
+
+// angNormalize 角度归化到 (-180°, 180°]
+func angNormalize(x float64) float64 {
+	x = math.Remainder(x, 360)
+	if x == -180 {
+		return 180
+	}
+	return x
+}
+
+// angDiff 精确计算 y - x 并归化，返回差值与舍入误差
+func angDiff(x, y float64) (d, e float64) {
+	d, t := sumx(angNormalize(-x), angNormalize(y))
+	d = angNormalize(d)
+	if d == 180 && t > 0 {
+		d = -180
+	}
+	return sumx(d, t)
+}
+
+// angRound 舍去极小角度的低位，使 0 附近的值具有对称的舍入
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	if x == 0 {
+		return 0
+	}
+	y := math.Abs(x)
+	if y < z {
+		y = z - (z - y)
+	}
+	return math.Copysign(y, x)
+}
+
+// latFix 纬度超出 [-90°, 90°] 时返回 NaN
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+	return x
+}
+
+// sincosd 角度 (°) 的正弦与余弦，对 90° 的整数倍精确
+func sincosd(x float64) (sinx, cosx float64) {
+	r := math.Mod(x, 360)
+	q := int(math.Round(r / 90))
+	r -= 90 * float64(q)
+	s, c := math.Sincos(r * math.Pi / 180)
+	switch q & 3 {
+	case 0:
+		sinx, cosx = s, c
+	case 1:
+		sinx, cosx = c, -s
+	case 2:
+		sinx, cosx = -s, -c
+	default:
+		sinx, cosx = -c, s
+	}
+	return sinx + 0, cosx + 0
+}
+
+// atan2d 返回 (-180°, 180°] 内的 atan2 (°)
+func atan2d(y, x float64) float64 {
+	q := 0
+	if math.Abs(y) > math.Abs(x) {
+		x, y = y, x
+		q = 2
+	}
+	if x < 0 {
+		x = -x
+		q++
+	}
+	ang := math.Atan2(y, x) * 180 / math.Pi
+	switch q {
+	case 1:
+		if y >= 0 {
+			ang = 180 - ang
+		} else {
+			ang = -180 - ang
+		}
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+	return ang
+}
+
+func norm2(s, c float64) (float64, float64) {
+	r := math.Hypot(s, c)
+	return s / r, c / r
+}
+
+// polyval 以 Horner 法计算 n 次多项式，p 按降幂排列
+func polyval(n int, p []float64, x float64) float64 {
+	if n < 0 {
+		return 0
+	}
+	y := p[0]
+	for i := 1; i <= n; i++ {
+		y = y*x + p[i]
+	}
+	return y
+}
+
+// sinCosSeries Clenshaw 求和: sinp 时为 Σ c[l] sin 2lx (l = 1..n)，否则为 Σ c[l] cos (2l+1)x (l = 0..n-1)
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64, n int) float64 {
+	k := n
+	if sinp {
+		k++
+	}
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	for n /= 2; n > 0; n-- {
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	if sinp {
+		return 2 * sinx * cosx * y0
+	}
+	return cosx * (y0 - y1)
+}
+
+// ---------------- 级数系数 ----------------
+
+// a1m1f (1 - ε)A₁ - 1
+func a1m1f(eps float64) float64 {
+	coeff := []float64{1, 4, 64, 0, 256}
+	m := nA1 / 2
+	t := polyval(m, coeff, eps*eps) / coeff[m+1]
+	return (t + eps) / (1 - eps)
+}
+
+// c1f 系数 C₁ₗ，l = 1..nC1
+func c1f(eps float64, c []float64) {
+	coeff := []float64{
+		-1, 6, -16, 32,
+		-9, 64, -128, 2048,
+		9, -16, 768,
+		3, -5, 512,
+		-7, 1280,
+		-7, 2048,
+	}
+	eps2, d := eps*eps, eps
+	o := 0
+	for l := 1; l <= nC1; l++ {
+		m := (nC1 - l) / 2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// c1pf 反演系数 C'₁ₗ
+func c1pf(eps float64, c []float64) {
+	coeff := []float64{
+		205, -432, 768, 1536,
+		4005, -4736, 3840, 12288,
+		-225, 116, 384,
+		-7173, 2695, 7680,
+		3467, 7680,
+		38081, 61440,
+	}
+	eps2, d := eps*eps, eps
+	o := 0
+	for l := 1; l <= nC1p; l++ {
+		m := (nC1p - l) / 2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// a2m1f (1 + ε)A₂ - 1
+func a2m1f(eps float64) float64 {
+	coeff := []float64{-11, -28, -192, 0, 256}
+	m := nA2 / 2
+	t := polyval(m, coeff, eps*eps) / coeff[m+1]
+	return (t - eps) / (1 + eps)
+}
+
+// c2f 系数 C₂ₗ
+func c2f(eps float64, c []float64) {
+	coeff := []float64{
+		1, 2, 16, 32,
+		35, 64, 384, 2048,
+		15, 80, 768,
+		7, 35, 512,
+		63, 1280,
+		77, 2048,
+	}
+	eps2, d := eps*eps, eps
+	o := 0
+	for l := 1; l <= nC2; l++ {
+		m := (nC2 - l) / 2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// a3coeff A₃ 关于 ε 的系数 (关于 n 的多项式)
+func (g *Geodesic) a3coeff() {
+	coeff := []float64{
+		-3, 128,
+		-2, -3, 64,
+		-1, -3, -1, 16,
+		3, -1, -2, 8,
+		1, -1, 2,
+		1, 1,
+	}
+	o, k := 0, 0
+	for j := nA3 - 1; j >= 0; j-- {
+		m := imin(nA3-j-1, j)
+		g.a3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+}
+
+// c3coeff C₃ₗ 关于 ε 的系数
+func (g *Geodesic) c3coeff() {
+	coeff := []float64{
+		3, 128,
+		2, 5, 128,
+		-1, 3, 3, 64,
+		-1, 0, 1, 8,
+		-1, 1, 4,
+		5, 256,
+		1, 3, 128,
+		-3, -2, 3, 64,
+		1, -3, 2, 32,
+		7, 512,
+		-10, 9, 384,
+		5, -9, 5, 192,
+		7, 512,
+		-14, 7, 512,
+		21, 2560,
+	}
+	o, k := 0, 0
+	for l := 1; l < nC3; l++ {
+		for j := nC3 - 1; j >= l; j-- {
+			m := imin(nC3-j-1, j)
+			g.c3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+// c4coeff 面积级数 C₄ₗ 关于 ε 的系数
+func (g *Geodesic) c4coeff() {
+	coeff := []float64{
+		97, 15015,
+		1088, 156, 45045,
+		-224, -4784, 1573, 45045,
+		-10656, 14144, -4576, -858, 45045,
+		64, 624, -4576, 6864, -3003, 15015,
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		1, 9009,
+		-2944, 468, 135135,
+		5792, 1040, -1287, 135135,
+		5952, -11648, 9152, -2574, 135135,
+		-64, -624, 4576, -6864, 3003, 135135,
+		8, 10725,
+		1856, -936, 225225,
+		-8448, 4992, -1144, 225225,
+		-1440, 4160, -4576, 1716, 225225,
+		-136, 63063,
+		1024, -208, 105105,
+		3584, -3328, 1144, 315315,
+		-128, 135135,
+		-2560, 832, 405405,
+		128, 99099,
+	}
+	o, k := 0, 0
+	for l := 0; l < nC4; l++ {
+		for j := nC4 - 1; j >= l; j-- {
+			m := nC4 - j - 1
+			g.c4x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+func (g *Geodesic) a3f(eps float64) float64 {
+	return polyval(nA3-1, g.a3x[:], eps)
+}
+
+func (g *Geodesic) c3f(eps float64, c []float64) {
+	mult := 1.0
+	o := 0
+	for l := 1; l < nC3; l++ {
+		m := nC3 - l - 1
+		mult *= eps
+		c[l] = mult * polyval(m, g.c3x[o:], eps)
+		o += m + 1
+	}
+}
+
+func (g *Geodesic) c4f(eps float64, c []float64) {
+	mult := 1.0
+	o := 0
+	for l := 0; l < nC4; l++ {
+		m := nC4 - l - 1
+		c[l] = mult * polyval(m, g.c4x[o:], eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+// ---------------- 反算 ----------------
+
+// lengths 由辅助球上的弧长计算 s12/b、m12/b、m0 与测地线比例 M12、M21
+func (g *Geodesic) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2 float64) (s12b, m12b, m0, M12, M21 float64) {
+	var ca, cb [nC]float64
+	A1 := a1m1f(eps)
+	c1f(eps, ca[:])
+	A2 := a2m1f(eps)
+	c2f(eps, cb[:])
+	m0 = A1 - A2
+	A1++
+	A2++
+	B1 := sinCosSeries(true, ssig2, csig2, ca[:], nC1) - sinCosSeries(true, ssig1, csig1, ca[:], nC1)
+	B2 := sinCosSeries(true, ssig2, csig2, cb[:], nC2) - sinCosSeries(true, ssig1, csig1, cb[:], nC2)
+	s12b = A1 * (sig12 + B1)
+	J12 := m0*sig12 + (A1*B1 - A2*B2)
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*J12
+	csig12 := csig1*csig2 + ssig1*ssig2
+	t := g.ep2 * (cbet1 - cbet2) * (cbet1 + cbet2) / (dn1 + dn2)
+	M12 = csig12 + (t*ssig2-csig2*J12)*ssig1/dn1
+	M21 = csig12 - (t*ssig1-csig1*J12)*ssig2/dn2
+	return
+}
+
+// astroid 求解 k⁴ + 2k³ - (x² + y² - 1)k² - 2y²k - y² = 0 的正根
+func astroid(x, y float64) float64 {
+	p, q := x*x, y*y
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		return 0
+	}
+	S := p * q / 4
+	r2 := r * r
+	r3 := r * r2
+	disc := S * (S + 2*r3)
+	u := r
+	if disc >= 0 {
+		T3 := S + r3
+		if T3 < 0 {
+			T3 -= math.Sqrt(disc)
+		} else {
+			T3 += math.Sqrt(disc)
+		}
+		T := math.Cbrt(T3)
+		u += T
+		if T != 0 {
+			u += r2 / T
+		}
+	} else {
+		ang := math.Atan2(math.Sqrt(-disc), -(S + r3))
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(u*u + q)
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v
+	}
+	w := (uv - q) / (2 * v)
+	return uv / (math.Sqrt(uv+w*w) + w)
+}
+
+// inverseStart 反算的初始方位估计；短线时直接返回 sig12 >= 0 及终点方位
+func (g *Geodesic) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12 float64) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+	sig12 = -1
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := (sbet1 + sbet2) * (sbet1 + sbet2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + g.ep2*sbetm2)
+		omg12 := lam12 / (g.f1 * dnm)
+		somg12, comg12 = math.Sincos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	switch {
+	case shortline && ssig12 < g.etol2:
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*(somg12*somg12/(1+comg12))
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	case math.Abs(g.n) > 0.1 || csig12 >= 0 || ssig12 >= 6*math.Abs(g.n)*math.Pi*cbet1*cbet1:
+		// 零阶球面近似已足够
+	default:
+		// 近对跖点: 以星形线方程给出初值
+		var x, y, lamscale, betscale float64
+		lam12x := math.Atan2(-slam12, -clam12) // lam12 - π
+		if g.f >= 0 {
+			k2 := sbet1 * sbet1 * g.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			lamscale = g.f * cbet1 * g.a3f(eps) * math.Pi
+			betscale = lamscale * cbet1
+			x = lam12x / lamscale
+			y = sbet12a / betscale
+		} else {
+			cbet12a := cbet2*cbet1 - sbet2*sbet1
+			bet12a := math.Atan2(sbet12a, cbet12a)
+			_, m12b, m0, _, _ := g.lengths(g.n, math.Pi+bet12a, sbet1, -cbet1, dn1, sbet2, cbet2, dn2, cbet1, cbet2)
+			x = -1 + m12b/(cbet1*cbet2*m0*math.Pi)
+			if x < -0.01 {
+				betscale = sbet12a / x
+			} else {
+				betscale = -g.f * cbet1 * cbet1 * math.Pi
+			}
+			lamscale = betscale / cbet1
+			y = lam12x / lamscale
+		}
+		if y > -geodTol1 && x > -1-geodXthresh {
+			if g.f >= 0 {
+				salp1 = math.Min(1, -x)
+				calp1 = -math.Sqrt(1 - salp1*salp1)
+			} else {
+				if x > -geodTol1 {
+					calp1 = math.Max(0, x)
+				} else {
+					calp1 = math.Max(-1, x)
+				}
+				salp1 = math.Sqrt(1 - calp1*calp1)
+			}
+		} else {
+			k := astroid(x, y)
+			var omg12a float64
+			if g.f >= 0 {
+				omg12a = lamscale * (-x * k / (1 + k))
+			} else {
+				omg12a = lamscale * (-y * (1 + k) / k)
+			}
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+	return
+}
+
+// lambda12State lambda12 的中间结果
+type lambda12State struct {
+	salp2, calp2, sig12             float64
+	ssig1, csig1, ssig2, csig2, eps float64
+	domg12, dlam12                  float64
+}
+
+// lambda12 给定起点方位时的经差 λ12 及其对 α1 的导数
+func (g *Geodesic) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64, diffp bool) (lam12 float64, st lambda12State) {
+	if sbet1 == 0 && calp1 == 0 {
+		calp1 = -geodTiny
+	}
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	ssig1, somg1 := sbet1, salp0*sbet1
+	csig1 := calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = norm2(ssig1, csig1)
+
+	salp2 := salp1
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	}
+	var calp2 float64
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var d float64
+		if cbet1 < -sbet1 {
+			d = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			d = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt((calp1*cbet1)*(calp1*cbet1)+d) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+	ssig2, somg2 := sbet2, salp0*sbet2
+	csig2 := calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = norm2(ssig2, csig2)
+
+	sig12 := math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2)
+	comg12 := comg1*comg2 + somg1*somg2
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+	k2 := calp0 * calp0 * g.ep2
+	eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	var ca [nC]float64
+	g.c3f(eps, ca[:])
+	B312 := sinCosSeries(true, ssig2, csig2, ca[:], nC3-1) - sinCosSeries(true, ssig1, csig1, ca[:], nC3-1)
+	domg12 := -g.f * g.a3f(eps) * salp0 * (sig12 + B312)
+	lam12 = eta + domg12
+
+	var dlam12 float64
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * g.f1 * dn1 / sbet1
+		} else {
+			_, dlam12, _, _, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2)
+			dlam12 *= g.f1 / (calp2 * cbet2)
+		}
+	}
+	return lam12, lambda12State{salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12}
+}
+
+// geodInverse 反算的完整结果
+type geodInverse struct {
+	a12, s12, m12, M12, M21    float64
+	salp1, calp1, salp2, calp2 float64
+	S12                        float64 // 大地线、赤道与两端子午线围成的面积 (m²)
+}
+
+// genInverse 反算核心，返回弧长、距离、约化长度及方位的正余弦；area 时另计算面积 S12
+func (g *Geodesic) genInverse(lat1, lon1, lat2, lon2 float64, area bool) (r geodInverse) {
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := 1.0
+	if math.Signbit(lon12) {
+		lonsign = -1
+	}
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := lon12 * math.Pi / 180
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+	// 交换使点 1 的纬度绝对值较大，再使 lat1 <= 0
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) || math.IsNaN(lat2) {
+		swapp = -1
+		lonsign = -lonsign
+		lat1, lat2 = lat2, lat1
+	}
+	latsign := -1.0
+	if math.Signbit(lat1) {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1, cbet1 = norm2(sbet1*g.f1, cbet1)
+	cbet1 = math.Max(geodTiny, cbet1)
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2, cbet2 = norm2(sbet2*g.f1, cbet2)
+	cbet2 = math.Max(geodTiny, cbet2)
+
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1 := math.Sqrt(1 + g.ep2*sbet1*sbet1)
+	dn2 := math.Sqrt(1 + g.ep2*sbet2*sbet2)
+
+	var a12, sig12, s12x, m12x, M12, M21 float64
+	var salp1, calp1, salp2, calp2 float64
+	omg12, somg12, comg12 := 0.0, 2.0, 0.0
+	meridian := lat1 == -90 || slam12 == 0
+
+	if meridian {
+		// 沿子午线
+		salp1, calp1 = slam12, clam12
+		salp2, calp2 = 0, 1
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		s12x, m12x, _, M12, M21 = g.lengths(g.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2)
+		if sig12 < 1 || m12x >= 0 {
+			if sig12 < 3*geodTiny || (sig12 < geodTol0 && (s12x < 0 || m12x < 0)) {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			m12x *= g.b
+			s12x *= g.b
+			a12 = sig12 * 180 / math.Pi
+		} else {
+			meridian = false
+		}
+	}
+
+	if !meridian && sbet1 == 0 && (g.f <= 0 || lon12s >= g.f*180) {
+		// 沿赤道
+		salp1, calp1, salp2, calp2 = 1, 0, 1, 0
+		s12x = g.a * lam12
+		sig12 = lam12 / g.f1
+		omg12 = sig12
+		m12x = g.b * math.Sin(sig12)
+		M12 = math.Cos(sig12)
+		M21 = M12
+		a12 = lon12 / g.f1
+	} else if !meridian {
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = g.inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12)
+		if sig12 >= 0 {
+			// 短线
+			s12x = sig12 * g.b * dnm
+			m12x = dnm * dnm * g.b * math.Sin(sig12/dnm)
+			M12 = math.Cos(sig12 / dnm)
+			M21 = M12
+			a12 = sig12 * 180 / math.Pi
+			omg12 = lam12 / (g.f1 * dnm)
+		} else {
+			// 牛顿迭代 α1，失败时退回二分
+			var st lambda12State
+			salp1a, calp1a := geodTiny, 1.0
+			salp1b, calp1b := geodTiny, -1.0
+			tripn, tripb := false, false
+			for numit := 0; ; numit++ {
+				var v float64
+				v, st = g.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam12, clam12, numit < geodMaxit1)
+				tol := geodTol0
+				if tripn {
+					tol *= 8
+				}
+				if tripb || !(math.Abs(v) >= tol) || numit == geodMaxit2 {
+					break
+				}
+				if v > 0 && (numit > geodMaxit1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > geodMaxit1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+				if numit < geodMaxit1 && st.dlam12 > 0 {
+					dalp1 := -v / st.dlam12
+					if math.Abs(dalp1) < math.Pi {
+						sdalp1, cdalp1 := math.Sincos(dalp1)
+						nsalp1 := salp1*cdalp1 + calp1*sdalp1
+						if nsalp1 > 0 {
+							calp1 = calp1*cdalp1 - salp1*sdalp1
+							salp1 = nsalp1
+							salp1, calp1 = norm2(salp1, calp1)
+							tripn = math.Abs(v) <= 16*geodTol0
+							continue
+						}
+					}
+				}
+				salp1 = (salp1a + salp1b) / 2
+				calp1 = (calp1a + calp1b) / 2
+				salp1, calp1 = norm2(salp1, calp1)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < geodTolb ||
+					math.Abs(salp1-salp1b)+(calp1-calp1b) < geodTolb
+			}
+			salp2, calp2, sig12 = st.salp2, st.calp2, st.sig12
+			s12x, m12x, _, M12, M21 = g.lengths(st.eps, sig12, st.ssig1, st.csig1, dn1, st.ssig2, st.csig2, dn2, cbet1, cbet2)
+			m12x *= g.b
+			s12x *= g.b
+			a12 = sig12 * 180 / math.Pi
+			// omg12 = lam12 - domg12
+			sdomg12, cdomg12 := math.Sincos(st.domg12)
+			somg12 = slam12*cdomg12 - clam12*sdomg12
+			comg12 = clam12*cdomg12 + slam12*sdomg12
+		}
+	}
+
+	var S12 float64
+	if area {
+		salp0 := salp1 * cbet1
+		calp0 := math.Hypot(calp1, salp1*sbet1)
+		if calp0 != 0 && salp0 != 0 {
+			ssig1, csig1 := norm2(sbet1, calp1*cbet1)
+			ssig2, csig2 := norm2(sbet2, calp2*cbet2)
+			k2 := calp0 * calp0 * g.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			A4 := g.a * g.a * calp0 * salp0 * g.e2
+			var ca [nC]float64
+			g.c4f(eps, ca[:])
+			S12 = A4 * (sinCosSeries(false, ssig2, csig2, ca[:], nC4) - sinCosSeries(false, ssig1, csig1, ca[:], nC4))
+		}
+		if !meridian && somg12 == 2 {
+			somg12, comg12 = math.Sincos(omg12)
+		}
+		var alp12 float64
+		if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+			// tan(Γ/2) = tan(ω12/2)·(tan(β1/2) + tan(β2/2))/(1 + tan(β1/2)·tan(β2/2))
+			domg12, dbet1, dbet2 := 1+comg12, 1+cbet1, 1+cbet2
+			alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1), domg12*(sbet1*sbet2+dbet1*dbet2))
+		} else {
+			salp12 := salp2*calp1 - calp2*salp1
+			calp12 := calp2*calp1 + salp2*salp1
+			if salp12 == 0 && calp12 < 0 {
+				salp12 = geodTiny * calp1
+				calp12 = -1
+			}
+			alp12 = math.Atan2(salp12, calp12)
+		}
+		S12 = (S12+g.c2*alp12)*swapp*lonsign*latsign + 0
+	}
+
+	// 还原交换与符号
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+		M12, M21 = M21, M12
+	}
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+	return geodInverse{a12: a12, s12: 0 + s12x, m12: 0 + m12x, M12: M12, M21: M21,
+		salp1: salp1, calp1: calp1, salp2: salp2, calp2: calp2, S12: S12}
+}
+
+// Inverse 大地线反算，返回距离 s12 (m)、起点方位 azi1 与终点方位 azi2 (°)
+func (g *Geodesic) Inverse(lat1, lon1, lat2, lon2 float64) (s12, azi1, azi2 float64) {
+	r := g.genInverse(lat1, lon1, lat2, lon2, false)
+	return r.s12, atan2d(r.salp1, r.calp1), atan2d(r.salp2, r.calp2)
+}
+
+// ---------------- 正算与大地线 ----------------
+
+// GeodesicLine 过定点、定方位的大地线，可快速计算线上任意距离处的点
+type GeodesicLine struct {
+	Lat1, Lon1, Azi1 float64 // 起点与起始方位 (°)
+	Distance         float64 // 由 InverseLine 创建时为到终点的距离 (m)，否则为 NaN
+
+	g                                        *Geodesic
+	salp0, calp0, k2                         float64
+	ssig1, csig1, somg1, comg1, stau1, ctau1 float64
+	a1m1, a3c, b11, b31                      float64
+	c1a, c1pa                                [nC]float64
+	c3a                                      [nC]float64
+}
+
+// Line 以起点与起始方位 (°) 创建大地线
+func (g *Geodesic) Line(lat1, lon1, azi1 float64) *GeodesicLine {
+	azi1 = angNormalize(azi1)
+	salp1, calp1 := sincosd(angRound(azi1))
+	return g.line(lat1, lon1, azi1, salp1, calp1)
+}
+
+// InverseLine 创建连接两点的大地线，Distance 为两点间距离
+func (g *Geodesic) InverseLine(lat1, lon1, lat2, lon2 float64) *GeodesicLine {
+	r := g.genInverse(lat1, lon1, lat2, lon2, false)
+	l := g.line(lat1, lon1, atan2d(r.salp1, r.calp1), r.salp1, r.calp1)
+	l.Distance = r.s12
+	return l
+}
+
+func (g *Geodesic) line(lat1, lon1, azi1, salp1, calp1 float64) *GeodesicLine {
+	l := &GeodesicLine{Lat1: latFix(lat1), Lon1: lon1, Azi1: azi1, Distance: math.NaN(), g: g}
+	sbet1, cbet1 := sincosd(angRound(l.Lat1))
+	sbet1, cbet1 = norm2(sbet1*g.f1, cbet1)
+	cbet1 = math.Max(geodTiny, cbet1)
+
+	l.salp0 = salp1 * cbet1
+	l.calp0 = math.Hypot(calp1, salp1*sbet1)
+	l.ssig1 = sbet1
+	l.somg1 = l.salp0 * sbet1
+	if sbet1 != 0 || calp1 != 0 {
+		l.csig1 = cbet1 * calp1
+	} else {
+		l.csig1 = 1
+	}
+	l.comg1 = l.csig1
+	l.ssig1, l.csig1 = norm2(l.ssig1, l.csig1)
+
+	l.k2 = l.calp0 * l.calp0 * g.ep2
+	eps := l.k2 / (2*(1+math.Sqrt(1+l.k2)) + l.k2)
+
+	l.a1m1 = a1m1f(eps)
+	c1f(eps, l.c1a[:])
+	l.b11 = sinCosSeries(true, l.ssig1, l.csig1, l.c1a[:], nC1)
+	s, c := math.Sincos(l.b11)
+	l.stau1 = l.ssig1*c + l.csig1*s
+	l.ctau1 = l.csig1*c - l.ssig1*s
+	c1pf(eps, l.c1pa[:])
+
+	g.c3f(eps, l.c3a[:])
+	l.a3c = -g.f * l.salp0 * g.a3f(eps)
+	l.b31 = sinCosSeries(true, l.ssig1, l.csig1, l.c3a[:], nC3-1)
+	return l
+}
+
+// Position 距起点 s12 (m，可为负) 处的纬度、经度与方位 (°)
+func (l *GeodesicLine) Position(s12 float64) (lat2, lon2, azi2 float64) {
+	g := l.g
+	tau12 := s12 / (g.b * (1 + l.a1m1))
+	s, c := math.Sincos(tau12)
+	B12 := -sinCosSeries(true, l.stau1*c+l.ctau1*s, l.ctau1*c-l.stau1*s, l.c1pa[:], nC1p)
+	sig12 := tau12 - (B12 - l.b11)
+	ssig12, csig12 := math.Sincos(sig12)
+	if math.Abs(g.f) > 0.01 {
+		// |f| > 1/100 时反演级数精度不足，以一次牛顿迭代修正
+		ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+		csig2 := l.csig1*csig12 - l.ssig1*ssig12
+		B12 = sinCosSeries(true, ssig2, csig2, l.c1a[:], nC1)
+		serr := (1+l.a1m1)*(sig12+(B12-l.b11)) - s12/g.b
+		sig12 -= serr / math.Sqrt(1+l.k2*ssig2*ssig2)
+		ssig12, csig12 = math.Sincos(sig12)
+	}
+	ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+	csig2 := l.csig1*csig12 - l.ssig1*ssig12
+	sbet2 := l.calp0 * ssig2
+	cbet2 := math.Hypot(l.salp0, l.calp0*csig2)
+	if cbet2 == 0 {
+		cbet2, csig2 = geodTiny, geodTiny
+	}
+	salp2, calp2 := l.salp0, l.calp0*csig2
+
+	E := math.Copysign(1, l.salp0)
+	somg2, comg2 := l.salp0*ssig2, csig2
+	omg12 := E * (sig12 -
+		(math.Atan2(ssig2, csig2) - math.Atan2(l.ssig1, l.csig1)) +
+		(math.Atan2(E*somg2, comg2) - math.Atan2(E*l.somg1, l.comg1)))
+	lam12 := omg12 + l.a3c*(sig12+(sinCosSeries(true, ssig2, csig2, l.c3a[:], nC3-1)-l.b31))
+	lon2 = angNormalize(angNormalize(l.Lon1) + angNormalize(lam12*180/math.Pi))
+	lat2 = atan2d(sbet2, g.f1*cbet2)
+	azi2 = atan2d(salp2, calp2)
+	return
+}
+
+// Direct 大地线正算，由起点、起始方位 (°) 与距离 (m) 求终点及终点方位 (°)
+func (g *Geodesic) Direct(lat1, lon1, azi1, s12 float64) (lat2, lon2, azi2 float64) {
+	return g.Line(lat1, lon1, azi1).Position(s12)
+}
+
+// ---------------- Geodetic 方法 ----------------
+
+// GeodesicInverse 沿椭球面到 other 的大地线长度 s12 (m)、起点方位 azi1 与终点方位 azi2 (°)
+// 两点均视为 geo.Ell 上的坐标，忽略高度。
+func (geo *Geodetic) GeodesicInverse(other Geodetic) (s12, azi1, azi2 float64) {
+	return NewGeodesic(geo.Ell).Inverse(geo.Latitude, geo.Longitude, other.Latitude, other.Longitude)
+}
+
+// GeodesicDirect 沿方位 azi1 (°) 行进 s12 (m) 后的点 (高度不变) 及该点处的方位 (°)
+func (geo *Geodetic) GeodesicDirect(azi1, s12 float64) (Geodetic, float64) {
+	lat, lon, azi2 := NewGeodesic(geo.Ell).Direct(geo.Latitude, geo.Longitude, azi1, s12)
+	return Geodetic{Latitude: lat, Longitude: lon, Altitude: geo.Altitude, Ell: geo.Ell}, azi2
+}
+
+// GeodesicWaypoints 将到 other 的大地线等分为 n 段，返回含两端点的 n+1 个点
+// 高度在两端点间按距离线性插值；n < 1 时返回 nil。
+func (geo *Geodetic) GeodesicWaypoints(other Geodetic, n int) []Geodetic {
+	if n < 1 {
+		return nil
+	}
+	l := NewGeodesic(geo.Ell).InverseLine(geo.Latitude, geo.Longitude, other.Latitude, other.Longitude)
+	pts := make([]Geodetic, n+1)
+	for i := range pts {
+		f := float64(i) / float64(n)
+		lat, lon, _ := l.Position(f * l.Distance)
+		pts[i] = Geodetic{Latitude: lat, Longitude: lon, Altitude: geo.Altitude + f*(other.Altitude-geo.Altitude), Ell: geo.Ell}
+	}
+	pts[0].Latitude, pts[0].Longitude = geo.Latitude, geo.Longitude
+	pts[n].Latitude, pts[n].Longitude = other.Latitude, other.Longitude
+	return pts
+}
@@ -0,0 +1,131 @@
+package gomap3d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// geodesicCases GeographicLib testgeodesic.c 算例 (WGS84)
+// lat1, lon1, azi1, lat2, lon2, azi2, s12
+var geodesicCases = [][7]float64{
+	{35.60777, -139.44815, 111.098748429560326, -11.17491, -69.95921, 129.289270889708762, 8935244.5604818305},
+	{55.52454, 106.05087, 22.020059880982801, 77.03196, 197.18234, 109.112041110671519, 4105086.1713924406},
+	{-21.97856, 142.59065, -32.44456876433189, 41.84138, 98.56635, -41.84359951440466, 8394328.894657671},
+	{-66.99028, 112.2363, 173.73491240878403, -12.70631, 285.90344, 2.512956620913668, 11150344.2312080241},
+	{-17.42761, 173.34268, -159.033557661192928, -15.84784, 5.93557, -20.787484651536988, 16076603.1631180673},
+	{32.84994, 48.28919, 150.492927788121982, -56.28556, 202.29132, 48.113449399816759, 16727068.9438164461},
+	{6.96833, 52.74123, 92.581585386317712, -7.39675, 206.17291, 90.721692165923907, 17102477.2496958388},
+	{-50.56724, -16.30485, -105.439679907590164, -33.56571, -94.97412, -47.348547835650331, 6455670.5118668696},
+	{-58.93002, -8.90775, 140.965397902500679, -8.91104, 133.13503, 19.255429433416599, 11756066.0219864627},
+	{-68.82867, -74.28391, 93.774347763114881, -50.63005, -8.36685, 34.65564085411343, 3956936.926063544},
+	{-10.62672, -32.0898, -86.426713286747751, 5.883, -134.31681, -80.473780971034875, 11470869.3864563009},
+	{-21.76221, 166.90563, 29.319421206936428, 48.72884, 213.97627, 43.508671946410168, 9098627.3986554915},
+}
+
+func TestGeodesicInverseDirect(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	g := NewGeodesic(wgs)
+	for _, c := range geodesicCases {
+		s12, azi1, azi2 := g.Inverse(c[0], c[1], c[3], c[4])
+		if math.Abs(s12-c[6]) > 1e-8 || math.Abs(azi1-c[2]) > 1e-12 || math.Abs(azi2-c[5]) > 1e-12 {
+			t.Errorf("inverse %v: s12 = %.10f, azi1 = %.15f, azi2 = %.15f", c, s12, azi1, azi2)
+		}
+		lat2, lon2, azi2 := g.Direct(c[0], c[1], c[2], c[6])
+		if math.Abs(lat2-c[3]) > 1e-13 || math.Abs(angNormalize(lon2-c[4])) > 1e-13 || math.Abs(azi2-c[5]) > 1e-12 {
+			t.Errorf("direct %v: lat2 = %.15f, lon2 = %.15f, azi2 = %.15f", c, lat2, lon2, azi2)
+		}
+	}
+}
+
+func TestGeodesicKarneyExamples(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	g := NewGeodesic(wgs)
+	// Karney (2013) 正算算例
+	lat2, lon2, azi2 := g.Direct(40, 0, 30, 10e6)
+	if math.Abs(lat2-41.79331020506) > 1e-11 || math.Abs(lon2-137.84490004377) > 1e-11 || math.Abs(azi2-149.09016931807) > 1e-11 {
+		t.Errorf("direct: %.11f %.11f %.11f", lat2, lon2, azi2)
+	}
+	// Karney (2013) 近对跖点反算算例
+	s12, azi1, azi2 := g.Inverse(-30, 0, 29.9, 179.8)
+	if math.Abs(s12-19989832.827610) > 1e-6 || math.Abs(azi1-161.890524736) > 1e-9 || math.Abs(azi2-18.090737246) > 1e-9 {
+		t.Errorf("antipodal inverse: %.6f %.9f %.9f", s12, azi1, azi2)
+	}
+	// 严格对跖点: 距离为子午线长度的 2 倍 (半周)
+	half := 2 * 10001965.729313
+	if s12, _, _ := g.Inverse(0, 0, 0, 180); math.Abs(s12-half) > 1e-5 {
+		t.Errorf("equatorial antipodes: %.6f", s12)
+	}
+	if s12, azi1, _ := g.Inverse(90, 0, -90, 0); math.Abs(s12-half) > 1e-5 || azi1 != 180 {
+		t.Errorf("pole to pole: %.6f %g", s12, azi1)
+	}
+	// 赤道: s = aλ
+	if s12, azi1, azi2 := g.Inverse(0, 10, 0, 40); math.Abs(s12-wgs.SemimajorAxis*math.Pi/6) > 1e-8 || azi1 != 90 || azi2 != 90 {
+		t.Errorf("equator: %.9f %g %g", s12, azi1, azi2)
+	}
+	if s12, _, _ := g.Inverse(12, 34, 12, 34); s12 != 0 {
+		t.Errorf("coincident: %g", s12)
+	}
+}
+
+func TestGeodesicRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	for _, model := range []string{"wgs84", "moon", "mars"} {
+		ell, _ := NewEllipsoid(model)
+		g := NewGeodesic(ell)
+		for i := 0; i < 2000; i++ {
+			lat1 := rnd.Float64()*180 - 90
+			lat2 := rnd.Float64()*180 - 90
+			lon1 := rnd.Float64()*360 - 180
+			lon2 := rnd.Float64()*360 - 180
+			if i%10 == 0 { // 近对跖点
+				lat2, lon2 = -lat1+rnd.Float64()*0.2-0.1, lon1+180+rnd.Float64()*0.4-0.2
+			}
+			s12, azi1, azi2 := g.Inverse(lat1, lon1, lat2, lon2)
+			l2, o2, a2 := g.Direct(lat1, lon1, azi1, s12)
+			if math.Abs(l2-lat2) > 1e-9 || math.Abs(angNormalize(o2-lon2))*math.Cos(lat2*math.Pi/180) > 1e-9 ||
+				math.Abs(angNormalize(a2-azi2)) > 1e-7 {
+				t.Fatalf("%s (%g, %g) → (%g, %g): s12 = %g azi1 = %g → (%g, %g) azi2 %g/%g",
+					model, lat1, lon1, lat2, lon2, s12, azi1, l2, o2, a2, azi2)
+			}
+			// 对称性
+			s21, _, _ := g.Inverse(lat2, lon2, lat1, lon1)
+			if math.Abs(s21-s12) > 1e-8 {
+				t.Fatalf("%s asymmetric: %.9f vs %.9f", model, s12, s21)
+			}
+		}
+	}
+}
+
+func TestGeodeticGeodesic(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	jfk := Geodetic{Latitude: 40.6, Longitude: -73.8, Altitude: 10, Ell: wgs}
+	lhr := Geodetic{Latitude: 51.6, Longitude: -0.5, Altitude: 30, Ell: wgs}
+	// GeographicLib 文档算例
+	s12, azi1, _ := jfk.GeodesicInverse(lhr)
+	if math.Abs(s12-5551759.400) > 1e-3 {
+		t.Errorf("JFK-LHR %.1f", s12)
+	}
+	end, _ := jfk.GeodesicDirect(azi1, s12)
+	if math.Abs(end.Latitude-lhr.Latitude) > 1e-12 || math.Abs(end.Longitude-lhr.Longitude) > 1e-12 ||
+		end.Altitude != jfk.Altitude || end.Ell != wgs {
+		t.Errorf("direct: %+v", end)
+	}
+
+	pts := jfk.GeodesicWaypoints(lhr, 4)
+	if len(pts) != 5 || pts[0].Latitude != jfk.Latitude || pts[4].Longitude != lhr.Longitude {
+		t.Fatalf("waypoints: %+v", pts)
+	}
+	for i := 1; i < len(pts); i++ {
+		d, _, _ := pts[i-1].GeodesicInverse(pts[i])
+		if math.Abs(d-s12/4) > 1e-6 {
+			t.Errorf("segment %d: %.6f, want %.6f", i, d, s12/4)
+		}
+	}
+	if math.Abs(pts[2].Altitude-20) > 1e-12 {
+		t.Errorf("altitude %g", pts[2].Altitude)
+	}
+	if jfk.GeodesicWaypoints(lhr, 0) != nil {
+		t.Error("expected nil for n = 0")
+	}
+}
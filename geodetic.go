@@ -48,8 +48,13 @@ func (geo *Geodetic) ToAER(ref Geodetic) AER {
 
 // 转地心惯性坐标系
 func (geo *Geodetic) ToECI(t time.Time) ECI {
+	return geo.ToECIWith(t, DefaultTransformer())
+}
+
+// ToECIWith 使用转换器 tr 转地心惯性坐标系
+func (geo *Geodetic) ToECIWith(t time.Time, tr *Transformer) ECI {
 	x, y, z := Geodetic2ECEF(geo.Latitude, geo.Longitude, geo.Altitude, geo.Ell)
-	xECI, yECI, zECI := ECEF2ECI(x, y, z, t)
+	xECI, yECI, zECI := tr.ECEF2ECI(x, y, z, t)
 	return ECI{
 		X:   xECI,
 		Y:   yECI,
@@ -0,0 +1,316 @@
+package gomap3d
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ============================================================
+// 大地水准面模型
+//
+// 从本地格网文件读取大地水准面差距 N，正高 H = h - N。支持:
+//   - NGA ASCII 格网 (.GRD，EGM96 WW15MGH.GRD 及同格式的区域格网，如 CQG2000)
+//     首行: 南 北 西 东 纬差 经差 (°)，其后自北向南逐行、自西向东列出格网值 (m)
+//   - GeographicLib PGM 格网 (egm96-5.pgm、egm2008-1.pgm 等)
+//     16 位大端灰度，N = Offset + Scale·值，原点 90N 0E
+// ============================================================
+
+// Interpolation 格网插值方法
+type Interpolation int
+
+const (
+	Bilinear Interpolation = iota // 双线性
+	Bicubic                       // 双三次卷积 (Keys, a = -0.5)
+)
+
+// grid 等间距经纬度格网，第 0 行为最北端，第 0 列为最西端
+type grid struct {
+	north, west float64
+	dlat, dlon  float64
+	rows, cols  int
+	period      int // 全球格网的经向周期列数，区域格网为 0
+	v           []float32
+
+	// 16 位量化格网 (PGM) 保留原始样本，节点值为 offset + scale·raw，v 为 nil
+	raw           []uint16
+	offset, scale float64
+}
+
+func newGridShape(north, west, dlat, dlon float64, rows, cols int) (*grid, error) {
+	if !(dlat > 0) || !(dlon > 0) || rows < 2 || cols < 2 {
+		return nil, fmt.Errorf("grid: invalid spacing %g×%g or size %d×%d", dlat, dlon, rows, cols)
+	}
+	g := &grid{north: north, west: west, dlat: dlat, dlon: dlon, rows: rows, cols: cols}
+	if p := math.Round(360 / dlon); math.Abs(p*dlon-360) < 1e-9*360 && int(p) <= cols {
+		g.period = int(p)
+	}
+	return g, nil
+}
+
+func newGrid(north, west, dlat, dlon float64, rows, cols int) (*grid, error) {
+	g, err := newGridShape(north, west, dlat, dlon, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	g.v = make([]float32, rows*cols)
+	return g, nil
+}
+
+// newScaledGrid 16 位量化格网，每节点 2 字节 (egm2008-1 约 470 MB)
+func newScaledGrid(north, west, dlat, dlon float64, rows, cols int, offset, scale float64) (*grid, error) {
+	g, err := newGridShape(north, west, dlat, dlon, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	g.raw, g.offset, g.scale = make([]uint16, rows*cols), offset, scale
+	return g, nil
+}
+
+func (g *grid) at(i, j int) float64 {
+	if i < 0 {
+		i = 0
+	} else if i >= g.rows {
+		i = g.rows - 1
+	}
+	if g.period > 0 {
+		j %= g.period
+		if j < 0 {
+			j += g.period
+		}
+	} else if j < 0 {
+		j = 0
+	} else if j >= g.cols {
+		j = g.cols - 1
+	}
+	if g.raw != nil {
+		return g.offset + g.scale*float64(g.raw[i*g.cols+j])
+	}
+	return float64(g.v[i*g.cols+j])
+}
+
+// locate 经纬度对应的格网行列坐标 (浮点)
+func (g *grid) locate(lat, lon float64) (y, x float64, err error) {
+	y = (g.north - lat) / g.dlat
+	const eps = 1e-9
+	if y < -eps || y > float64(g.rows-1)+eps {
+		return 0, 0, fmt.Errorf("grid: latitude %g outside [%g, %g]", lat, g.north-float64(g.rows-1)*g.dlat, g.north)
+	}
+	d := lon - g.west
+	if g.period > 0 {
+		d = math.Mod(d, 360)
+		if d < 0 {
+			d += 360
+		}
+	}
+	x = d / g.dlon
+	if g.period == 0 && (x < -eps || x > float64(g.cols-1)+eps) {
+		return 0, 0, fmt.Errorf("grid: longitude %g outside [%g, %g]", lon, g.west, g.west+float64(g.cols-1)*g.dlon)
+	}
+	return math.Max(0, math.Min(y, float64(g.rows-1))), math.Max(0, x), nil
+}
+
+// interpolate 在 (lat, lon) 处插值
+func (g *grid) interpolate(lat, lon float64, method Interpolation) (float64, error) {
+	y, x, err := g.locate(lat, lon)
+	if err != nil {
+		return 0, err
+	}
+	i, j := int(math.Floor(y)), int(math.Floor(x))
+	if i == g.rows-1 {
+		i--
+	}
+	if g.period == 0 && j == g.cols-1 {
+		j--
+	}
+	fy, fx := y-float64(i), x-float64(j)
+
+	if method == Bicubic {
+		var rowv [4]float64
+		for r := 0; r < 4; r++ {
+			rowv[r] = cubicConv(g.at(i-1+r, j-1), g.at(i-1+r, j), g.at(i-1+r, j+1), g.at(i-1+r, j+2), fx)
+		}
+		return cubicConv(rowv[0], rowv[1], rowv[2], rowv[3], fy), nil
+	}
+	v00, v01 := g.at(i, j), g.at(i, j+1)
+	v10, v11 := g.at(i+1, j), g.at(i+1, j+1)
+	return (1-fy)*((1-fx)*v00+fx*v01) + fy*((1-fx)*v10+fx*v11), nil
+}
+
+// cubicConv Keys 三次卷积插值 (a = -0.5)，t ∈ [0, 1] 位于 p1 与 p2 之间
+func cubicConv(p0, p1, p2, p3, t float64) float64 {
+	return p1 + 0.5*t*(p2-p0+t*(2*p0-5*p1+4*p2-p3+t*(3*(p1-p2)+p3-p0)))
+}
+
+// Geoid 大地水准面格网模型
+type Geoid struct {
+	Name   string
+	Method Interpolation // 插值方法，默认双线性
+	grid   *grid
+}
+
+// Undulation 大地水准面差距 N (m)，超出格网范围时返回错误
+func (g *Geoid) Undulation(lat, lon float64) (float64, error) {
+	return g.grid.interpolate(lat, lon, g.Method)
+}
+
+// LoadGeoid 从文件读取大地水准面格网，按内容识别 PGM 或 NGA ASCII 格式
+func LoadGeoid(path string) (*Geoid, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	magic, _ := br.Peek(2)
+	var g *Geoid
+	if string(magic) == "P5" {
+		g, err = ParseGeoidPGM(br)
+	} else {
+		g, err = ParseGeoidGRD(br)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if g.Name == "" {
+		g.Name = path
+	}
+	return g, nil
+}
+
+// ParseGeoidGRD 解析 NGA ASCII 格网 (.GRD)
+func ParseGeoidGRD(r io.Reader) (*Geoid, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 1<<16), 1<<20)
+	sc.Split(bufio.ScanWords)
+	next := func() (float64, error) {
+		if !sc.Scan() {
+			if err := sc.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.ErrUnexpectedEOF
+		}
+		return strconv.ParseFloat(sc.Text(), 64)
+	}
+	var h [6]float64
+	for k := range h {
+		v, err := next()
+		if err != nil {
+			return nil, fmt.Errorf("geoid grd header: %w", err)
+		}
+		h[k] = v
+	}
+	south, north, west, east, dlat, dlon := h[0], h[1], h[2], h[3], h[4], h[5]
+	if !(dlat > 0) || !(dlon > 0) || north <= south || east <= west {
+		return nil, fmt.Errorf("geoid grd: invalid header %v", h)
+	}
+	rows := int(math.Round((north-south)/dlat)) + 1
+	cols := int(math.Round((east-west)/dlon)) + 1
+	gr, err := newGrid(north, west, dlat, dlon, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	for k := range gr.v {
+		v, err := next()
+		if err != nil {
+			return nil, fmt.Errorf("geoid grd: value %d of %d: %w", k+1, len(gr.v), err)
+		}
+		gr.v[k] = float32(v)
+	}
+	return &Geoid{grid: gr}, nil
+}
+
+// ParseGeoidPGM 解析 GeographicLib PGM 格网
+func ParseGeoidPGM(r io.Reader) (*Geoid, error) {
+	br := bufio.NewReader(r)
+	offset, scale := math.NaN(), math.NaN()
+	var desc string
+	var header []int
+	for len(header) < 4 {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("geoid pgm header: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case len(header) == 0 && line == "P5":
+			header = append(header, 0)
+			continue
+		case len(header) == 0:
+			return nil, fmt.Errorf("geoid pgm: not a binary PGM file")
+		case strings.HasPrefix(line, "#"):
+			f := strings.Fields(line[1:])
+			if len(f) < 2 {
+				continue
+			}
+			switch f[0] {
+			case "Offset":
+				offset, err = strconv.ParseFloat(f[1], 64)
+			case "Scale":
+				scale, err = strconv.ParseFloat(f[1], 64)
+			case "Description":
+				desc = strings.Join(f[1:], " ")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("geoid pgm: %s: %w", line, err)
+			}
+			continue
+		}
+		for _, s := range strings.Fields(line) {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("geoid pgm: %w", err)
+			}
+			header = append(header, v)
+		}
+	}
+	if len(header) != 4 || header[3] != 65535 {
+		return nil, fmt.Errorf("geoid pgm: expected 16-bit width/height/maxval, got %v", header[1:])
+	}
+	if math.IsNaN(offset) || math.IsNaN(scale) {
+		return nil, fmt.Errorf("geoid pgm: missing Offset or Scale")
+	}
+	width, height := header[1], header[2]
+	if width < 2 || height < 2 {
+		return nil, fmt.Errorf("geoid pgm: invalid size %d×%d", width, height)
+	}
+	gr, err := newScaledGrid(90, 0, 180/float64(height-1), 360/float64(width), height, width, offset, scale)
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]byte, 2*width)
+	for i := 0; i < height; i++ {
+		if _, err := io.ReadFull(br, raw); err != nil {
+			return nil, fmt.Errorf("geoid pgm: row %d: %w", i, err)
+		}
+		for j := 0; j < width; j++ {
+			gr.raw[i*width+j] = binary.BigEndian.Uint16(raw[2*j:])
+		}
+	}
+	return &Geoid{Name: desc, grid: gr}, nil
+}
+
+// OrthometricHeight 正高 H = h - N (m)
+func (geo *Geodetic) OrthometricHeight(g *Geoid) (float64, error) {
+	n, err := g.Undulation(geo.Latitude, geo.Longitude)
+	if err != nil {
+		return 0, err
+	}
+	return geo.Altitude - n, nil
+}
+
+// WithOrthometricHeight 以正高 H (m) 设置高度，返回椭球高 h = H + N 的副本
+func (geo *Geodetic) WithOrthometricHeight(H float64, g *Geoid) (Geodetic, error) {
+	n, err := g.Undulation(geo.Latitude, geo.Longitude)
+	if err != nil {
+		return Geodetic{}, err
+	}
+	out := *geo
+	out.Altitude = H + n
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package gomap3d
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGeoidGRD(t *testing.T) {
+	g, err := LoadGeoid("test_data/geoid_region.grd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 格网值由 N = -20 + 1.5Δφ - 0.8Δλ + 0.3ΔφΔλ 生成，双线性插值精确
+	f := func(lat, lon float64) float64 {
+		return -20 + 1.5*(lat-30) - 0.8*(lon-110) + 0.3*(lat-30)*(lon-110)
+	}
+	pts := [][2]float64{{30, 110}, {32, 113}, {31.2, 111.7}, {30.1, 112.95}, {31.75, 110.25}}
+	for _, p := range pts {
+		n, err := g.Undulation(p[0], p[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(n-f(p[0], p[1])) > 1e-5 {
+			t.Errorf("bilinear N(%v) = %.6f, want %.6f", p, n, f(p[0], p[1]))
+		}
+	}
+	// 三次卷积在内部格元同样精确重现双线性函数
+	g.Method = Bicubic
+	for _, p := range [][2]float64{{31.2, 111.7}, {30.8, 111.1}} {
+		if n, _ := g.Undulation(p[0], p[1]); math.Abs(n-f(p[0], p[1])) > 1e-5 {
+			t.Errorf("bicubic N(%v) = %.6f, want %.6f", p, n, f(p[0], p[1]))
+		}
+	}
+	for _, p := range [][2]float64{{29.9, 111}, {31, 113.1}, {31, 109}} {
+		if _, err := g.Undulation(p[0], p[1]); err == nil {
+			t.Errorf("expected out-of-region error at %v", p)
+		}
+	}
+
+	if _, err := ParseGeoidGRD(strings.NewReader("30 32 110 113 0.5 0.5\n1 2 3\n")); err == nil {
+		t.Error("expected error for truncated grid")
+	}
+}
+
+func TestGeoidPGM(t *testing.T) {
+	g, err := LoadGeoid("test_data/geoid_global.pgm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Name != "test grid, 45-degree" {
+		t.Errorf("name %q", g.Name)
+	}
+	// 保留 16 位原始样本，不展开为浮点
+	if g.grid.v != nil || len(g.grid.raw) != g.grid.rows*g.grid.cols {
+		t.Errorf("pgm grid stored as float32: %d raw samples", len(g.grid.raw))
+	}
+	// 格网值 N = 10i - 5j，第 i 行纬度 90 - 45i，第 j 列经度 45j
+	cases := []struct{ lat, lon, want float64 }{
+		{90, 0, 0},
+		{45, 90, 0},
+		{-90, 315, 5},
+		{0, -45, -15},     // 等价于 315°E
+		{45, 337.5, -7.5}, // 跨 0° 经线插值: 第 7 列 -25 与第 0 列 10 的均值
+		{22.5, 22.5, 12.5},
+	}
+	for _, c := range cases {
+		n, err := g.Undulation(c.lat, c.lon)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(n-c.want) > 2e-3 {
+			t.Errorf("N(%g, %g) = %.4f, want %.4f", c.lat, c.lon, n, c.want)
+		}
+	}
+
+	// 正高与椭球高互换
+	ell, _ := NewEllipsoid("wgs84")
+	geo := Geodetic{Latitude: 22.5, Longitude: 22.5, Altitude: 100, Ell: ell}
+	H, err := geo.OrthometricHeight(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(H-87.5) > 2e-3 {
+		t.Errorf("orthometric height %.4f", H)
+	}
+	back, err := geo.WithOrthometricHeight(H, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(back.Altitude-geo.Altitude) > 1e-12 || back.Ell != ell || back.Latitude != geo.Latitude {
+		t.Errorf("round trip: %+v", back)
+	}
+
+	if _, err := ParseGeoidPGM(strings.NewReader("P5\n# Offset 0\n2 2\n255\n\x00\x00\x00\x00")); err == nil {
+		t.Error("expected error for 8-bit PGM")
+	}
+}
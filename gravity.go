@@ -0,0 +1,298 @@
+package gomap3d
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ============================================================
+// 球谐引力场
+//
+// 完全规格化系数 C̄nm、S̄nm，加速度按 Montenbruck & Gill (Satellite Orbits, 3.2)
+// 的 V/W 递推计算，递推与加速度公式均改写为规格化形式，高阶次不溢出。
+// 位置与加速度均在地固系 (ECEF) 中表示。
+//
+// 支持的系数文件:
+//   - ICGEM .gfc (EGM2008、EIGEN 等，含文件头 earth_gravity_constant / radius)
+//   - EGM96 / EGM2008 NGA 原始 ASCII (n m C S σC σS，允许 Fortran D 指数)
+// ============================================================
+
+// EGM96 地球引力常数与参考半径
+const (
+	egm96GM     = 3.986004415e14
+	egm96Radius = 6378136.3
+)
+
+// egm96Zonal EGM96 完全规格化带谐系数 C̄n0 (n = 2..6)
+var egm96Zonal = [...]float64{
+	2: -0.484165371736e-3,
+	3: 0.957254173792e-6,
+	4: 0.539873863789e-6,
+	5: 0.685323475630e-7,
+	6: -0.149957994714e-6,
+}
+
+// GravityField 球谐引力场模型
+type GravityField struct {
+	Name   string
+	GM     float64     // 引力常数 (m³/s²)
+	Radius float64     // 参考半径 (m)
+	Degree int         // 最高阶
+	C, S   [][]float64 // 完全规格化系数，下标 [n][m]
+}
+
+// newGravityField 分配 0..degree 阶的系数表，C̄00 = 1
+func newGravityField(name string, gm, radius float64, degree int) *GravityField {
+	g := &GravityField{Name: name, GM: gm, Radius: radius, Degree: degree}
+	g.C = make([][]float64, degree+1)
+	g.S = make([][]float64, degree+1)
+	for n := range g.C {
+		g.C[n] = make([]float64, n+1)
+		g.S[n] = make([]float64, n+1)
+	}
+	g.C[0][0] = 1
+	return g
+}
+
+// ZonalField EGM96 带谐项引力场 J2..Jn (2 ≤ n ≤ 6)
+func ZonalField(n int) (*GravityField, error) {
+	if n < 2 || n >= len(egm96Zonal) {
+		return nil, fmt.Errorf("gravity: zonal degree %d out of range 2..%d", n, len(egm96Zonal)-1)
+	}
+	g := newGravityField(fmt.Sprintf("EGM96 J2-J%d", n), egm96GM, egm96Radius, n)
+	for i := 2; i <= n; i++ {
+		g.C[i][0] = egm96Zonal[i]
+	}
+	return g, nil
+}
+
+// J 非规格化带谐系数 Jn = -√(2n+1)·C̄n0
+func (g *GravityField) J(n int) float64 {
+	if n > g.Degree {
+		return 0
+	}
+	return -math.Sqrt(float64(2*n+1)) * g.C[n][0]
+}
+
+// LoadGravityField 从本地文件读取引力场系数，nmax > 0 时截断到 nmax 阶
+func LoadGravityField(path string, nmax int) (*GravityField, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseGravityField(f, nmax)
+}
+
+// ParseGravityField 解析 ICGEM .gfc 或 EGM ASCII 系数文件，nmax > 0 时截断到 nmax 阶
+// 无文件头的 EGM ASCII 格式使用 EGM96/EGM2008 的 GM 与参考半径。
+func ParseGravityField(r io.Reader, nmax int) (*GravityField, error) {
+	type coef struct {
+		n, m int
+		c, s float64
+	}
+	var (
+		coefs  []coef
+		name   = "EGM"
+		gm     = egm96GM
+		radius = egm96Radius
+		maxDeg = 0
+		inHead = true
+		lineNo = 0
+	)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lineNo++
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		// ICGEM 文件头：关键字行直到 end_of_head；无文件头时首行即为数据
+		if inHead {
+			if fields[0] == "end_of_head" {
+				inHead = false
+				continue
+			}
+			if _, err := strconv.Atoi(fields[0]); err != nil && fields[0] != "gfc" {
+				if len(fields) < 2 {
+					continue
+				}
+				var err error
+				switch fields[0] {
+				case "modelname":
+					name = fields[1]
+				case "earth_gravity_constant":
+					gm, err = strconv.ParseFloat(fields[1], 64)
+				case "radius":
+					radius, err = strconv.ParseFloat(fields[1], 64)
+				case "norm":
+					if fields[1] != "fully_normalized" {
+						return nil, fmt.Errorf("gravity: unsupported normalization %q", fields[1])
+					}
+				}
+				if err != nil {
+					return nil, fmt.Errorf("gravity: line %d: %v", lineNo, err)
+				}
+				continue
+			}
+			inHead = false
+		}
+		for i, f := range fields {
+			fields[i] = strings.NewReplacer("D", "E", "d", "e").Replace(f)
+		}
+		switch fields[0] {
+		case "gfc":
+			fields = fields[1:]
+		case "gfct", "trnd", "acos", "asin":
+			return nil, fmt.Errorf("gravity: line %d: time-variable coefficients not supported", lineNo)
+		}
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("gravity: line %d: expected n m C S", lineNo)
+		}
+		n, err1 := strconv.Atoi(fields[0])
+		m, err2 := strconv.Atoi(fields[1])
+		c, err3 := strconv.ParseFloat(fields[2], 64)
+		s, err4 := strconv.ParseFloat(fields[3], 64)
+		for _, err := range []error{err1, err2, err3, err4} {
+			if err != nil {
+				return nil, fmt.Errorf("gravity: line %d: %v", lineNo, err)
+			}
+		}
+		if n < 0 || m < 0 || m > n {
+			return nil, fmt.Errorf("gravity: line %d: invalid degree/order %d/%d", lineNo, n, m)
+		}
+		if nmax > 0 && n > nmax {
+			continue
+		}
+		if n > maxDeg {
+			maxDeg = n
+		}
+		coefs = append(coefs, coef{n, m, c, s})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(coefs) == 0 {
+		return nil, fmt.Errorf("gravity: no coefficients found")
+	}
+
+	g := newGravityField(name, gm, radius, maxDeg)
+	for _, k := range coefs {
+		g.C[k.n][k.m] = k.c
+		g.S[k.n][k.m] = k.s
+	}
+	return g, nil
+}
+
+// vw 规格化 V̄nm、W̄nm (0 ≤ m ≤ n ≤ nmax)
+func (g *GravityField) vw(x, y, z float64, nmax int) (v, w [][]float64) {
+	r2 := x*x + y*y + z*z
+	R := g.Radius
+	rho := R * R / r2
+	x0, y0, z0 := x*R/r2, y*R/r2, z*R/r2
+
+	v = make([][]float64, nmax+1)
+	w = make([][]float64, nmax+1)
+	for n := range v {
+		v[n] = make([]float64, n+1)
+		w[n] = make([]float64, n+1)
+	}
+	v[0][0] = R / math.Sqrt(r2)
+	for m := 0; m <= nmax; m++ {
+		if m > 0 {
+			// 扇谐项 V̄mm、W̄mm
+			f := math.Sqrt(float64(2*m+1) / float64(2*m))
+			if m == 1 {
+				f = math.Sqrt(3)
+			}
+			v[m][m] = f * (x0*v[m-1][m-1] - y0*w[m-1][m-1])
+			w[m][m] = f * (x0*w[m-1][m-1] + y0*v[m-1][m-1])
+		}
+		for n := m + 1; n <= nmax; n++ {
+			fn, fm := float64(n), float64(m)
+			a := math.Sqrt((2*fn + 1) * (2*fn - 1) / ((fn - fm) * (fn + fm)))
+			v[n][m] = a * z0 * v[n-1][m]
+			w[n][m] = a * z0 * w[n-1][m]
+			if n-1 > m {
+				b := math.Sqrt((2*fn + 1) * (fn + fm - 1) * (fn - fm - 1) / ((2*fn - 3) * (fn + fm) * (fn - fm)))
+				v[n][m] -= b * rho * v[n-2][m]
+				w[n][m] -= b * rho * w[n-2][m]
+			}
+		}
+	}
+	return v, w
+}
+
+// truncate 截断阶次，0 或超出范围时取最大值
+func (g *GravityField) truncate(degree, order int) (int, int) {
+	if degree <= 0 || degree > g.Degree {
+		degree = g.Degree
+	}
+	if order <= 0 || order > degree {
+		order = degree
+	}
+	return degree, order
+}
+
+// Potential 地固系位置 (m) 处的引力位 (m²/s²)，degree/order 为 0 时使用全部系数
+func (g *GravityField) Potential(x, y, z float64, degree, order int) float64 {
+	degree, order = g.truncate(degree, order)
+	v, w := g.vw(x, y, z, degree)
+	var u float64
+	for n := degree; n >= 0; n-- {
+		for m := imin(n, order); m >= 0; m-- {
+			u += g.C[n][m]*v[n][m] + g.S[n][m]*w[n][m]
+		}
+	}
+	return g.GM / g.Radius * u
+}
+
+// Acceleration 地固系位置 (m) 处的引力加速度 (m/s²)，degree/order 为 0 时使用全部系数
+func (g *GravityField) Acceleration(x, y, z float64, degree, order int) (ax, ay, az float64) {
+	degree, order = g.truncate(degree, order)
+	v, w := g.vw(x, y, z, degree+1)
+
+	// 由高阶向低阶累加以减小舍入误差
+	for n := degree; n >= 0; n-- {
+		fn := float64(n)
+		q := (2*fn + 1) / (2*fn + 3)
+		for m := imin(n, order); m >= 0; m-- {
+			fm := float64(m)
+			c, s := g.C[n][m], g.S[n][m]
+			if c == 0 && s == 0 {
+				continue
+			}
+			// 规格化因子之比 N̄nm / N̄(n+1,m+1) 等
+			k1 := math.Sqrt(q * (fn + fm + 2) * (fn + fm + 1))
+			if m == 0 {
+				k1 /= math.Sqrt2
+				ax -= c * k1 * v[n+1][1]
+				ay -= c * k1 * w[n+1][1]
+			} else {
+				dm := 1.0
+				if m == 1 {
+					dm = 2
+				}
+				km := math.Sqrt(dm * q * (fn - fm + 2) * (fn - fm + 1))
+				ax += 0.5 * (k1*(-c*v[n+1][m+1]-s*w[n+1][m+1]) + km*(c*v[n+1][m-1]+s*w[n+1][m-1]))
+				ay += 0.5 * (k1*(-c*w[n+1][m+1]+s*v[n+1][m+1]) + km*(-c*w[n+1][m-1]+s*v[n+1][m-1]))
+			}
+			k0 := math.Sqrt(q * (fn + fm + 1) * (fn - fm + 1))
+			az += k0 * (-c*v[n+1][m] - s*w[n+1][m])
+		}
+	}
+	f := g.GM / (g.Radius * g.Radius)
+	return f * ax, f * ay, f * az
+}
+
+func imin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,180 @@
+package gomap3d
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// legendrePotential 以非规格化缔合勒让德函数直接求和计算引力位，用于交叉验证 V/W 递推
+func legendrePotential(g *GravityField, x, y, z float64) float64 {
+	r := math.Sqrt(x*x + y*y + z*z)
+	sinPhi := z / r
+	cosPhi := math.Hypot(x, y) / r
+	lam := math.Atan2(y, x)
+	N := g.Degree
+
+	p := make([][]float64, N+1)
+	for n := range p {
+		p[n] = make([]float64, n+1)
+	}
+	for m := 0; m <= N; m++ {
+		pmm := 1.0
+		for k := 1; k <= m; k++ {
+			pmm *= float64(2*k-1) * cosPhi
+		}
+		p[m][m] = pmm
+		for n := m + 1; n <= N; n++ {
+			p[n][m] = float64(2*n-1) * sinPhi * p[n-1][m]
+			if n-2 >= m {
+				p[n][m] -= float64(n+m-1) * p[n-2][m]
+			}
+			p[n][m] /= float64(n - m)
+		}
+	}
+	var u float64
+	for n := 0; n <= N; n++ {
+		for m := 0; m <= n; m++ {
+			// N̄nm = √((2-δm)(2n+1)(n-m)!/(n+m)!)
+			f := float64(2*n + 1)
+			if m > 0 {
+				f *= 2
+			}
+			for k := n - m + 1; k <= n+m; k++ {
+				f /= float64(k)
+			}
+			pb := math.Sqrt(f) * p[n][m]
+			u += math.Pow(g.Radius/r, float64(n)) * pb *
+				(g.C[n][m]*math.Cos(float64(m)*lam) + g.S[n][m]*math.Sin(float64(m)*lam))
+		}
+	}
+	return g.GM / r * u
+}
+
+func TestParseGravityField(t *testing.T) {
+	g, err := LoadGravityField("test_data/egm96_n4.gfc", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Name != "EGM96" || g.Degree != 4 || g.GM != 3.986004415e14 || g.Radius != 6378136.3 {
+		t.Errorf("header: %s degree %d GM %g R %g", g.Name, g.Degree, g.GM, g.Radius)
+	}
+	if g.C[2][2] != 0.243914352398e-5 || g.S[4][4] != 0.308853169333e-6 {
+		t.Errorf("C22 = %g, S44 = %g", g.C[2][2], g.S[4][4])
+	}
+	if j2 := g.J(2); math.Abs(j2-EarthJ2) > 1e-12 {
+		t.Errorf("J2 = %.12e", j2)
+	}
+
+	// 截断
+	g3, err := LoadGravityField("test_data/egm96_n4.gfc", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g3.Degree != 3 {
+		t.Errorf("truncated degree = %d", g3.Degree)
+	}
+
+	// NGA ASCII 格式，Fortran D 指数，无 C00 行
+	ascii := "    2    0 -0.484165371736D-03  0.000000000000D+00  0.0000D+00  0.0000D+00\n" +
+		"    2    2  0.243914352398D-05 -0.140016683654D-05  0.0000D+00  0.0000D+00\n"
+	ga, err := ParseGravityField(strings.NewReader(ascii), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ga.C[0][0] != 1 || ga.C[2][0] != g.C[2][0] || ga.S[2][2] != g.S[2][2] || ga.GM != g.GM {
+		t.Errorf("ascii field: %+v", ga)
+	}
+
+	bad := "norm unnormalized\nend_of_head\ngfc 2 0 1e-3 0\n"
+	if _, err := ParseGravityField(strings.NewReader(bad), 0); err == nil {
+		t.Error("expected error for unnormalized coefficients")
+	}
+}
+
+func TestGravityPotentialAndAcceleration(t *testing.T) {
+	egm, err := LoadGravityField("test_data/egm96_n4.gfc", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 高阶随机场检验递推稳定性
+	rnd := rand.New(rand.NewSource(1))
+	hi := newGravityField("random", egm96GM, egm96Radius, 40)
+	for n := 2; n <= hi.Degree; n++ {
+		for m := 0; m <= n; m++ {
+			hi.C[n][m] = rnd.NormFloat64() * 1e-5 / float64(n*n)
+			if m > 0 {
+				hi.S[n][m] = rnd.NormFloat64() * 1e-5 / float64(n*n)
+			}
+		}
+	}
+
+	points := [][3]float64{
+		{6778e3, 0, 0},
+		{-3000e3, 4000e3, 4500e3},
+		{1000e3, -2000e3, -6500e3},
+		{20e3, 10e3, 6400e3}, // 近极点
+	}
+	for _, g := range []*GravityField{egm, hi} {
+		for _, p := range points {
+			u := g.Potential(p[0], p[1], p[2], 0, 0)
+			if g.Degree <= 4 {
+				if ref := legendrePotential(g, p[0], p[1], p[2]); math.Abs(u-ref) > 1e-13*math.Abs(ref) {
+					t.Errorf("%s %v: potential %.15e, Legendre %.15e", g.Name, p, u, ref)
+				}
+			}
+			// 加速度与位函数数值梯度一致
+			ax, ay, az := g.Acceleration(p[0], p[1], p[2], 0, 0)
+			const h = 1.0
+			var grad [3]float64
+			for i := range grad {
+				q1, q2 := p, p
+				q1[i] += h
+				q2[i] -= h
+				grad[i] = (g.Potential(q1[0], q1[1], q1[2], 0, 0) - g.Potential(q2[0], q2[1], q2[2], 0, 0)) / (2 * h)
+			}
+			if d := vecDist([3]float64{ax, ay, az}, grad); d > 1e-7 {
+				t.Errorf("%s %v: acceleration differs from gradient by %.3e m/s²", g.Name, p, d)
+			}
+		}
+	}
+
+	// 截断阶次
+	p := points[1]
+	ax4, _, _ := egm.Acceleration(p[0], p[1], p[2], 0, 0)
+	ax2, _, _ := egm.Acceleration(p[0], p[1], p[2], 2, 2)
+	ax20, _, _ := egm.Acceleration(p[0], p[1], p[2], 2, 0)
+	if ax4 == ax2 || ax2 != ax20 {
+		t.Errorf("truncation: %g %g %g", ax4, ax2, ax20)
+	}
+}
+
+func TestZonalField(t *testing.T) {
+	g, err := ZonalField(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 解析 J2 加速度
+	r := [3]float64{-3000e3, 4000e3, 4500e3}
+	rn := norm3(r)
+	zr := r[2] / rn
+	c := -1.5 * EarthJ2 * g.GM * g.Radius * g.Radius / math.Pow(rn, 5)
+	want := [3]float64{
+		-g.GM*r[0]/(rn*rn*rn) + c*(1-5*zr*zr)*r[0],
+		-g.GM*r[1]/(rn*rn*rn) + c*(1-5*zr*zr)*r[1],
+		-g.GM*r[2]/(rn*rn*rn) + c*(3-5*zr*zr)*r[2],
+	}
+	ax, ay, az := g.Acceleration(r[0], r[1], r[2], 0, 0)
+	if d := vecDist([3]float64{ax, ay, az}, want); d > 1e-12 {
+		t.Errorf("J2 acceleration differs by %.3e m/s²", d)
+	}
+
+	g6, _ := ZonalField(6)
+	if math.Abs(g6.J(6)-5.40681239e-7) > 1e-14 || math.Abs(g6.J(3)+2.53265649e-6) > 1e-14 {
+		t.Errorf("J3 = %.9e, J6 = %.9e", g6.J(3), g6.J(6))
+	}
+	if _, err := ZonalField(7); err == nil {
+		t.Error("expected error for J7")
+	}
+}
@@ -0,0 +1,249 @@
+package gomap3d
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ============================================================
+// IAU 2000A 章动 (678 项日月项 + 687 项行星项)
+//
+// 系数从 IERS Conventions 2010 电子表格读取:
+//   tab5.3a.txt  黄经章动 Δψ (1320 + 38 项)
+//   tab5.3b.txt  交角章动 Δε (1037 + 19 项)
+// 并按 IAU 2006 岁差做 J2 长期变化修正 (SOFA iauNut06a)。
+// CIP 坐标 X、Y 级数 (tab5.2a / tab5.2b) 采用同样的表格格式。
+// 参考: IERS Conventions 2010, Chapter 5.5.4
+// ============================================================
+
+// nutation2000ATerm IERS 表格中的一项
+type nutation2000ATerm struct {
+	mult  [14]int // l, l', F, D, Ω, L_Me, L_Ve, L_E, L_Ma, L_J, L_Sa, L_U, L_Ne, p_A
+	sinC  float64 // sin 系数 (µas)
+	cosC  float64 // cos 系数 (µas)
+	power int     // 乘以 t^power
+}
+
+// Nutation2000A IAU 2000A 章动序列
+type Nutation2000A struct {
+	psi []nutation2000ATerm
+	eps []nutation2000ATerm
+}
+
+// LoadNutation2000A 从本地 IERS 表格 tab5.3a.txt (Δψ) 和 tab5.3b.txt (Δε) 读取 IAU 2000A 章动序列
+func LoadNutation2000A(psiPath, epsPath string) (*Nutation2000A, error) {
+	fp, err := os.Open(psiPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	fe, err := os.Open(epsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fe.Close()
+	return ParseNutation2000A(fp, fe)
+}
+
+// ParseNutation2000A 解析 IERS tab5.3a / tab5.3b 格式
+// 数据行: 序号 系数1 系数2 14 个整数乘数；"j = n" 行标记后续各项乘以 t^n。
+// tab5.3a 中系数1为 sin 项、系数2为 cos 项；tab5.3b 中系数1为 cos 项、系数2为 sin 项。
+func ParseNutation2000A(psi, eps io.Reader) (*Nutation2000A, error) {
+	n := &Nutation2000A{}
+	var err error
+	if n.psi, err = parseNutationTable(psi, false); err != nil {
+		return nil, fmt.Errorf("nutation in longitude: %v", err)
+	}
+	if n.eps, err = parseNutationTable(eps, true); err != nil {
+		return nil, fmt.Errorf("nutation in obliquity: %v", err)
+	}
+	return n, nil
+}
+
+func parseNutationTable(r io.Reader, cosFirst bool) ([]nutation2000ATerm, error) {
+	var terms []nutation2000ATerm
+	power := 0
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		s := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(s, "j ") || strings.HasPrefix(s, "j=") {
+			// 形如 "j = 0  Number of terms = 1320"
+			f := strings.Fields(strings.TrimLeft(s[1:], " ="))
+			if len(f) == 0 {
+				return nil, fmt.Errorf("line %d: invalid power marker", line)
+			}
+			p, err := strconv.Atoi(f[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid power marker: %v", line, err)
+			}
+			power = p
+			continue
+		}
+		f := strings.Fields(s)
+		if len(f) != 17 {
+			continue
+		}
+		if _, err := strconv.Atoi(f[0]); err != nil {
+			continue
+		}
+		var t nutation2000ATerm
+		c1, err := strconv.ParseFloat(f[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		c2, err := strconv.ParseFloat(f[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		for i := range t.mult {
+			if t.mult[i], err = strconv.Atoi(f[i+3]); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		if cosFirst {
+			t.cosC, t.sinC = c1, c2
+		} else {
+			t.sinC, t.cosC = c1, c2
+		}
+		t.power = power
+		terms = append(terms, t)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("no terms found")
+	}
+	return terms, nil
+}
+
+// fundArgs2000A IERS 2003 基本幅角 (rad): 5 个 Delaunay 幅角 + 8 个行星平黄经 + 黄经总岁差
+func fundArgs2000A(T float64) [14]float64 {
+	var a [14]float64
+	a[0], a[1], a[2], a[3], a[4] = fundArgs(T)
+	a[5] = math.Mod(4.402608842+2608.7903141574*T, tau)
+	a[6] = math.Mod(3.176146697+1021.3285546211*T, tau)
+	a[7] = math.Mod(1.753470314+628.3075849991*T, tau)
+	a[8] = math.Mod(6.203480913+334.0612426700*T, tau)
+	a[9] = math.Mod(0.599546497+52.9690962641*T, tau)
+	a[10] = math.Mod(0.874016757+21.3299104960*T, tau)
+	a[11] = math.Mod(5.481293872+7.4781598567*T, tau)
+	a[12] = math.Mod(5.311886287+3.8133035638*T, tau)
+	a[13] = (0.024381750 + 0.00000538691*T) * T
+	return a
+}
+
+func sumNutationSeries(terms []nutation2000ATerm, fa [14]float64, T float64) float64 {
+	sum := 0.0
+	for i := len(terms) - 1; i >= 0; i-- {
+		t := terms[i]
+		arg := 0.0
+		for k, m := range t.mult {
+			if m != 0 {
+				arg += float64(m) * fa[k]
+			}
+		}
+		s, c := math.Sincos(arg)
+		sum += (t.sinC*s + t.cosC*c) * math.Pow(T, float64(t.power))
+	}
+	return sum
+}
+
+// Nutation IAU 2000A 章动 (Δψ, Δε)，单位 rad，T 为 TT 儒略世纪数
+func (n *Nutation2000A) Nutation(T float64) (dpsi, deps float64) {
+	fa := fundArgs2000A(T)
+	dpsi = sumNutationSeries(n.psi, fa, T) * 1e-6 * as2r
+	deps = sumNutationSeries(n.eps, fa, T) * 1e-6 * as2r
+	return
+}
+
+// nutation06 IAU 2006/2000A 章动：对 IAU 2000A 施加 IAU 2006 J2 变化率修正
+// 参考: SOFA iauNut06a
+func (n *Nutation2000A) nutation06(T float64) (dpsi, deps float64) {
+	fj2 := -2.7774e-6 * T
+	dpsi, deps = n.Nutation(T)
+	dpsi *= 1 + 0.4697e-6 + fj2
+	deps *= 1 + fj2
+	return
+}
+
+// ---- CIP 坐标 X、Y 级数 (IAU 2006/2000A, SOFA iauXy06) ----
+
+// xyPoly X、Y 多项式部分 (角秒)，下标为 t 的幂次
+var xyPoly = [2][6]float64{
+	{-0.016617, 2004.191898, -0.4297829, -0.19861834, 0.000007578, 0.0000059285},
+	{-0.006951, -0.025896, -22.4072747, 0.00190059, 0.001112526, 0.0000001358},
+}
+
+// CIPSeries IAU 2006/2000A CIP 坐标 X、Y 级数
+// 直接给出 GCRS 中的 CIP 坐标，无需经 NPB 矩阵 (IERS Conventions 2010 式 5.16)。
+type CIPSeries struct {
+	x []nutation2000ATerm
+	y []nutation2000ATerm
+}
+
+// LoadCIPSeries 从本地 IERS 表格 tab5.2a.txt (X) 和 tab5.2b.txt (Y) 读取 CIP 坐标级数
+func LoadCIPSeries(xPath, yPath string) (*CIPSeries, error) {
+	fx, err := os.Open(xPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fx.Close()
+	fy, err := os.Open(yPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fy.Close()
+	return ParseCIPSeries(fx, fy)
+}
+
+// ParseCIPSeries 解析 IERS tab5.2a / tab5.2b 格式
+// 数据行与 tab5.3a 相同 (sin 系数在前)；多项式部分为 IAU 2006 常数，不从文件读取。
+func ParseCIPSeries(x, y io.Reader) (*CIPSeries, error) {
+	c := &CIPSeries{}
+	var err error
+	if c.x, err = parseNutationTable(x, false); err != nil {
+		return nil, fmt.Errorf("CIP X: %v", err)
+	}
+	if c.y, err = parseNutationTable(y, false); err != nil {
+		return nil, fmt.Errorf("CIP Y: %v", err)
+	}
+	return c, nil
+}
+
+// XY CIP 在 GCRS 中的坐标 X、Y (rad)，T 为 TT 儒略世纪数
+func (c *CIPSeries) XY(T float64) (x, y float64) {
+	fa := fundArgs2000A(T)
+	poly := func(p [6]float64) float64 {
+		return p[0] + (p[1]+(p[2]+(p[3]+(p[4]+p[5]*T)*T)*T)*T)*T
+	}
+	x = (poly(xyPoly[0]) + sumNutationSeries(c.x, fa, T)*1e-6) * as2r
+	y = (poly(xyPoly[1]) + sumNutationSeries(c.y, fa, T)*1e-6) * as2r
+	return
+}
+
+// SetCIPSeries 设置默认转换器使用的 CIP 坐标级数，传入 nil 恢复由 NPB 矩阵计算 X、Y
+func SetCIPSeries(c *CIPSeries) {
+	updateDefaultOptions(func(o *Options) { o.CIP = c })
+}
+
+// SetNutation2000A 设置默认转换器使用的 IAU 2000A 章动序列
+// 设置后完整模式使用 IAU 2006/2000A 与 CIO 归算链 (GCRF2ITRFCIO)；传入 nil 恢复 IAU 2000B。
+func SetNutation2000A(n *Nutation2000A) {
+	updateDefaultOptions(func(o *Options) {
+		o.Nutation = n
+		if o.Model != ModelGMST {
+			o.Model = ModelIAU2006B
+			if n != nil {
+				o.Model = ModelIAU2006A
+			}
+		}
+	})
+}
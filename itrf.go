@@ -0,0 +1,260 @@
+package gomap3d
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================
+// ITRF 各实现间的十四参数转换与板块运动
+//
+// 参数取自 IERS 发布的 ITRF2020 → 历史实现转换表 (参考历元 2015.0，位置矢量约定)：
+//   X₂ = X₁ + T + D·X₁ + R·X₁，P(t) = P(t₀) + Ṗ·(t - t₀)
+// 任意两实现间经 ITRF2020 中转。CGCS2000 定义为 ITRF97 框架、2000.0 历元。
+// 板块运动采用 ITRF2014-PMM 欧拉极 (Altamimi et al., 2017)，不含原点速率偏差 (< 1 mm/a)。
+// ============================================================
+
+// Realization ITRF 实现
+type Realization int
+
+const (
+	ITRF2020 Realization = iota
+	ITRF2014
+	ITRF2008
+	ITRF2005
+	ITRF2000
+	ITRF97
+)
+
+// CGCS2000Epoch CGCS2000 坐标的参考历元 (年)，框架为 ITRF97
+const CGCS2000Epoch = 2000.0
+
+func (r Realization) String() string {
+	switch r {
+	case ITRF2020:
+		return "ITRF2020"
+	case ITRF2014:
+		return "ITRF2014"
+	case ITRF2008:
+		return "ITRF2008"
+	case ITRF2005:
+		return "ITRF2005"
+	case ITRF2000:
+		return "ITRF2000"
+	case ITRF97:
+		return "ITRF97"
+	}
+	return fmt.Sprintf("Realization(%d)", int(r))
+}
+
+// Helmert14 十四参数 (七参数及其变化率) 相似变换，位置矢量约定
+type Helmert14 struct {
+	Helmert               // 参考历元参数
+	DTX, DTY, DTZ float64 // 平移速率 (m/a)
+	DRX, DRY, DRZ float64 // 旋转速率 (角秒/a)
+	DS            float64 // 尺度速率 (ppm/a)
+	Epoch         float64 // 参考历元 (年)
+}
+
+// At 历元 epoch (年) 的七参数
+func (p Helmert14) At(epoch float64) Helmert {
+	dt := epoch - p.Epoch
+	h := p.Helmert
+	h.TX += p.DTX * dt
+	h.TY += p.DTY * dt
+	h.TZ += p.DTZ * dt
+	h.RX += p.DRX * dt
+	h.RY += p.DRY * dt
+	h.RZ += p.DRZ * dt
+	h.S += p.DS * dt
+	return h
+}
+
+// rate 参数速率对应的七参数 (单位为每年)
+func (p Helmert14) rate() Helmert {
+	return Helmert{TX: p.DTX, TY: p.DTY, TZ: p.DTZ, RX: p.DRX, RY: p.DRY, RZ: p.DRZ, S: p.DS,
+		Convention: p.Convention}
+}
+
+// TransformVel 历元 epoch 的位置 (m) 与速度 (m/a) 正向变换
+// Ẋ₂ = Ẋ₁ + Ṫ + Ḋ·X₁ + Ṙ·X₁，忽略 D·Ẋ₁ 等二阶小量
+func (p Helmert14) TransformVel(x, y, z, vx, vy, vz, epoch float64) (xo, yo, zo, vxo, vyo, vzo float64) {
+	xo, yo, zo = p.At(epoch).Transform(x, y, z)
+	dx, dy, dz := p.rate().delta(x, y, z)
+	return xo, yo, zo, vx + dx, vy + dy, vz + dz
+}
+
+// InverseTransformVel TransformVel 的逆变换
+func (p Helmert14) InverseTransformVel(x, y, z, vx, vy, vz, epoch float64) (xo, yo, zo, vxo, vyo, vzo float64) {
+	xo, yo, zo = p.At(epoch).InverseTransform(x, y, z)
+	dx, dy, dz := p.rate().delta(xo, yo, zo)
+	return xo, yo, zo, vx - dx, vy - dy, vz - dz
+}
+
+// delta 七参数引起的坐标增量 T + D·X + R·X
+func (h Helmert) delta(x, y, z float64) (float64, float64, float64) {
+	xo, yo, zo := h.Transform(x, y, z)
+	return xo - x, yo - y, zo - z
+}
+
+// itrfParams ITRF2020 → 目标实现参数，发布单位
+var itrfParams = map[Realization]struct {
+	T  [3]float64 // mm
+	D  float64    // ppb
+	R  [3]float64 // mas
+	DT [3]float64 // mm/a
+	DD float64    // ppb/a
+	DR [3]float64 // mas/a
+}{
+	ITRF2020: {},
+	ITRF2014: {[3]float64{-1.4, -0.9, 1.4}, -0.42, [3]float64{}, [3]float64{0.0, -0.1, 0.2}, 0.00, [3]float64{}},
+	ITRF2008: {[3]float64{0.2, 1.0, 3.3}, -0.29, [3]float64{}, [3]float64{0.0, -0.1, 0.1}, 0.03, [3]float64{}},
+	ITRF2005: {[3]float64{2.7, 0.1, -1.4}, 0.65, [3]float64{}, [3]float64{0.3, -0.1, 0.1}, 0.03, [3]float64{}},
+	ITRF2000: {[3]float64{-0.2, 0.8, -34.2}, 2.25, [3]float64{}, [3]float64{0.1, 0.0, -1.7}, 0.11, [3]float64{}},
+	ITRF97: {[3]float64{6.5, -3.9, -77.9}, 3.98, [3]float64{0, 0, 0.36}, [3]float64{0.1, -0.6, -3.1}, 0.12,
+		[3]float64{0, 0, 0.02}},
+}
+
+// ITRFParams ITRF2020 → to 的十四参数
+func ITRFParams(to Realization) (Helmert14, error) {
+	p, ok := itrfParams[to]
+	if !ok {
+		return Helmert14{}, fmt.Errorf("unknown realization %v", to)
+	}
+	return Helmert14{
+		Helmert: Helmert{
+			TX: p.T[0] * 1e-3, TY: p.T[1] * 1e-3, TZ: p.T[2] * 1e-3,
+			RX: p.R[0] * 1e-3, RY: p.R[1] * 1e-3, RZ: p.R[2] * 1e-3,
+			S: p.D * 1e-3,
+		},
+		DTX: p.DT[0] * 1e-3, DTY: p.DT[1] * 1e-3, DTZ: p.DT[2] * 1e-3,
+		DRX: p.DR[0] * 1e-3, DRY: p.DR[1] * 1e-3, DRZ: p.DR[2] * 1e-3,
+		DS:    p.DD * 1e-3,
+		Epoch: 2015.0,
+	}, nil
+}
+
+// ITRFTransform 历元 epoch (年) 的地心坐标由实现 from 转换到 to (m)
+func ITRFTransform(x, y, z float64, from, to Realization, epoch float64) (xo, yo, zo float64, err error) {
+	xo, yo, zo, _, _, _, err = ITRFTransformVel(x, y, z, 0, 0, 0, from, to, epoch)
+	return
+}
+
+// ITRFTransformVel 历元 epoch (年) 的地心位置 (m) 与速度 (m/a) 由实现 from 转换到 to
+func ITRFTransformVel(x, y, z, vx, vy, vz float64, from, to Realization, epoch float64) (xo, yo, zo, vxo, vyo, vzo float64, err error) {
+	pf, err := ITRFParams(from)
+	if err != nil {
+		return
+	}
+	pt, err := ITRFParams(to)
+	if err != nil {
+		return
+	}
+	if from == to {
+		return x, y, z, vx, vy, vz, nil
+	}
+	x, y, z, vx, vy, vz = pf.InverseTransformVel(x, y, z, vx, vy, vz, epoch)
+	xo, yo, zo, vxo, vyo, vzo = pt.TransformVel(x, y, z, vx, vy, vz, epoch)
+	return
+}
+
+// ITRFToCGCS2000 将实现 from、历元 epoch (年) 的地心位置 (m) 与速度 (m/a) 归算到 CGCS2000 (ITRF97，2000.0 历元)
+func ITRFToCGCS2000(x, y, z, vx, vy, vz float64, from Realization, epoch float64) (xo, yo, zo float64, err error) {
+	x, y, z, vx, vy, vz, err = ITRFTransformVel(x, y, z, vx, vy, vz, from, ITRF97, epoch)
+	if err != nil {
+		return
+	}
+	xo, yo, zo = EpochShift(x, y, z, vx, vy, vz, epoch, CGCS2000Epoch)
+	return
+}
+
+// DecimalYear 时刻 t 的小数年，如 2000-07-02 ≈ 2000.5
+func DecimalYear(t time.Time) float64 {
+	t = t.UTC()
+	y := t.Year()
+	start := time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(y+1, 1, 1, 0, 0, 0, 0, time.UTC)
+	return float64(y) + t.Sub(start).Seconds()/end.Sub(start).Seconds()
+}
+
+// EpochShift 以测站速度 (m/a) 将历元 from 的地心坐标推算到历元 to (年)
+func EpochShift(x, y, z, vx, vy, vz, from, to float64) (xo, yo, zo float64) {
+	dt := to - from
+	return x + vx*dt, y + vy*dt, z + vz*dt
+}
+
+// platePoles ITRF2014-PMM 板块欧拉矢量 (mas/a)
+var platePoles = map[string][3]float64{
+	"AMUR": {-0.131, -0.551, 0.837},
+	"ANTA": {-0.248, -0.324, 0.675},
+	"ARAB": {1.154, -0.136, 1.444},
+	"AUST": {1.510, 1.182, 1.215},
+	"CARB": {0.207, -1.422, 0.726},
+	"EURA": {-0.085, -0.531, 0.770},
+	"INDI": {1.154, -0.005, 1.454},
+	"NAZC": {-0.333, -1.544, 1.623},
+	"NOAM": {0.024, -0.694, -0.063},
+	"NUBI": {0.099, -0.614, 0.733},
+	"PCFC": {-0.409, 1.047, -2.169},
+	"SOAM": {-0.270, -0.301, -0.140},
+	"SOMA": {-0.121, -0.794, 0.884},
+}
+
+// PlateVelocity 板块 plate (ITRF2014-PMM 缩写，如 "EURA") 上地心坐标处的刚体运动速度 (m/a)
+// v = ω × X
+func PlateVelocity(x, y, z float64, plate string) (vx, vy, vz float64, err error) {
+	w, ok := platePoles[strings.ToUpper(plate)]
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("unknown plate: %s", plate)
+	}
+	for i := range w {
+		w[i] *= 1e-3 * as2r
+	}
+	v := cross3(w, [3]float64{x, y, z})
+	return v[0], v[1], v[2], nil
+}
+
+// ToRealization 历元 epoch (年) 的坐标由实现 from 转换到 to
+func (ecef ECEF) ToRealization(from, to Realization, epoch float64) (ECEF, error) {
+	x, y, z, err := ITRFTransform(ecef.X, ecef.Y, ecef.Z, from, to, epoch)
+	if err != nil {
+		return ECEF{}, err
+	}
+	return ECEF{X: x, Y: y, Z: z, Ell: ecef.Ell}, nil
+}
+
+// AtEpoch 以速度 (m/a) 将历元 from 的坐标推算到历元 to (年)
+func (ecef ECEF) AtEpoch(vx, vy, vz, from, to float64) ECEF {
+	x, y, z := EpochShift(ecef.X, ecef.Y, ecef.Z, vx, vy, vz, from, to)
+	return ECEF{X: x, Y: y, Z: z, Ell: ecef.Ell}
+}
+
+// ToRealizationEpoch 将实现 from、历元 fromEpoch 的坐标 (速度 m/a，以 from 表示) 转换到实现 to、历元 toEpoch
+// 先在 fromEpoch 转换位置与速度，再以转换后的速度推算历元，速度可由 PlateVelocity 给出。
+func (ecef ECEF) ToRealizationEpoch(vx, vy, vz float64, from Realization, fromEpoch float64, to Realization, toEpoch float64) (ECEF, error) {
+	x, y, z, vx, vy, vz, err := ITRFTransformVel(ecef.X, ecef.Y, ecef.Z, vx, vy, vz, from, to, fromEpoch)
+	if err != nil {
+		return ECEF{}, err
+	}
+	x, y, z = EpochShift(x, y, z, vx, vy, vz, fromEpoch, toEpoch)
+	return ECEF{X: x, Y: y, Z: z, Ell: ecef.Ell}, nil
+}
+
+// ToCGCS2000 将实现 from、历元 epoch 的坐标 (速度 m/a) 归算到 CGCS2000，结果椭球为 CGCS2000
+func (ecef ECEF) ToCGCS2000(vx, vy, vz float64, from Realization, epoch float64) (ECEF, error) {
+	x, y, z, err := ITRFToCGCS2000(ecef.X, ecef.Y, ecef.Z, vx, vy, vz, from, epoch)
+	if err != nil {
+		return ECEF{}, err
+	}
+	ell, err := NewEllipsoid("cgcs2000")
+	if err != nil {
+		return ECEF{}, err
+	}
+	return ECEF{X: x, Y: y, Z: z, Ell: ell}, nil
+}
+
+// PlateVelocity 坐标所在板块的刚体运动速度 (m/a)
+func (ecef ECEF) PlateVelocity(plate string) (vx, vy, vz float64, err error) {
+	return PlateVelocity(ecef.X, ecef.Y, ecef.Z, plate)
+}
@@ -0,0 +1,120 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestITRFTransform(t *testing.T) {
+	// 北京附近测站
+	p := [3]float64{-2148744.3, 4426641.2, 4044655.9}
+
+	// 参考历元 2015.0: ITRF2020 → ITRF2014 仅平移与尺度
+	x, y, z, err := ITRFTransform(p[0], p[1], p[2], ITRF2020, ITRF2014, 2015.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := -0.42e-9
+	want := [3]float64{p[0] - 1.4e-3 + k*p[0], p[1] - 0.9e-3 + k*p[1], p[2] + 1.4e-3 + k*p[2]}
+	if d := vecDist([3]float64{x, y, z}, want); d > 1e-9 {
+		t.Errorf("ITRF2014 at 2015.0: error %.3e m", d)
+	}
+
+	// 参数随历元线性变化: ITRF2000 的 TZ 每年 -1.7 mm
+	_, _, z1, _ := ITRFTransform(0, 0, 0, ITRF2020, ITRF2000, 2015.0)
+	_, _, z2, _ := ITRFTransform(0, 0, 0, ITRF2020, ITRF2000, 2025.0)
+	if math.Abs(z1+34.2e-3) > 1e-12 || math.Abs(z2-z1+17e-3) > 1e-12 {
+		t.Errorf("ITRF2000 TZ: %.6f m at 2015, %.6f m at 2025", z1, z2)
+	}
+
+	// 经 ITRF2020 中转的往返，量级检查: ITRF97 与 ITRF2020 在 2000.0 相差约 3 cm
+	x97, y97, z97, vx, vy, vz, _ := ITRFTransformVel(p[0], p[1], p[2], 0.01, 0.02, -0.005, ITRF2014, ITRF97, 2000.0)
+	if d := vecDist([3]float64{x97, y97, z97}, p); d < 0.01 || d > 0.1 {
+		t.Errorf("ITRF2014 → ITRF97 shift %.4f m", d)
+	}
+	xr, yr, zr, vxr, vyr, vzr, _ := ITRFTransformVel(x97, y97, z97, vx, vy, vz, ITRF97, ITRF2014, 2000.0)
+	if d := vecDist([3]float64{xr, yr, zr}, p); d > 1e-8 {
+		t.Errorf("round trip position error %.3e m", d)
+	}
+	if d := vecDist([3]float64{vxr, vyr, vzr}, [3]float64{0.01, 0.02, -0.005}); d > 1e-12 {
+		t.Errorf("round trip velocity error %.3e m/a", d)
+	}
+
+	// 速度变换与位置变换对历元的导数一致
+	const dt = 1.0
+	xa, ya, za, _ := ITRFTransform(p[0], p[1], p[2], ITRF2014, ITRF97, 2000.0)
+	xb, yb, zb, _ := ITRFTransform(p[0]+0.01*dt, p[1]+0.02*dt, p[2]-0.005*dt, ITRF2014, ITRF97, 2000.0+dt)
+	num := [3]float64{(xb - xa) / dt, (yb - ya) / dt, (zb - za) / dt}
+	if d := vecDist(num, [3]float64{vx, vy, vz}); d > 1e-6 {
+		t.Errorf("velocity differs from position rate by %.3e m/a", d)
+	}
+
+	e := ECEF{X: p[0], Y: p[1], Z: p[2]}
+	if r, err := e.ToRealization(ITRF2014, ITRF97, 2000.0); err != nil || r.X != xa || r.Y != ya || r.Z != za {
+		t.Errorf("ToRealization: %+v %v", r, err)
+	}
+	// 当前历元 ITRF2014 → CGCS2000 (ITRF97, 2000.0)
+	c, err := e.ToRealizationEpoch(0.01, 0.02, -0.005, ITRF2014, 2024.5, ITRF97, CGCS2000Epoch)
+	want = [3]float64{x97 - 0.01*24.5, y97 - 0.02*24.5, z97 + 0.005*24.5}
+	if d := vecDist([3]float64{c.X, c.Y, c.Z}, want); err != nil || d > 1e-3 {
+		t.Errorf("ToRealizationEpoch error %.3e m %v", d, err)
+	}
+	cg, err := e.ToCGCS2000(0.01, 0.02, -0.005, ITRF2014, 2024.5)
+	if err != nil || cg.X != c.X || cg.Y != c.Y || cg.Z != c.Z || cg.Ell == nil || cg.Ell.Model != "cgcs2000" {
+		t.Errorf("ToCGCS2000: %+v %v", cg, err)
+	}
+
+	// 未知实现返回错误，相同实现直接返回
+	if _, err := ITRFParams(Realization(42)); err == nil {
+		t.Error("expected error for unknown realization")
+	}
+	if _, _, _, err := ITRFTransform(p[0], p[1], p[2], Realization(42), Realization(42), 2000.0); err == nil {
+		t.Error("expected error for unknown realization")
+	}
+	if _, err := e.ToCGCS2000(0, 0, 0, Realization(-1), 2024.5); err == nil {
+		t.Error("expected error for unknown realization")
+	}
+	if x, y, z, err := ITRFTransform(p[0], p[1], p[2], ITRF2008, ITRF2008, 2030.0); err != nil || x != p[0] || y != p[1] || z != p[2] {
+		t.Errorf("identity transform: %v %v %v %v", x, y, z, err)
+	}
+}
+
+func TestPlateVelocity(t *testing.T) {
+	// Wettzell 测站，ITRF2014 观测速度约 (-15.7, 17.1, 10.4) mm/a
+	w := ECEF{X: 4075580.4, Y: 931853.9, Z: 4801568.2}
+	vx, vy, vz, err := w.PlateVelocity("eura")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := vecDist([3]float64{vx, vy, vz}, [3]float64{-0.0157, 0.0171, 0.0104}); d > 1.5e-3 {
+		t.Errorf("EURA velocity (%.4f, %.4f, %.4f) m/a", vx, vy, vz)
+	}
+	// 刚体转动速度垂直于地心矢量
+	if r := vx*w.X + vy*w.Y + vz*w.Z; math.Abs(r) > 1e-6*norm3([3]float64{w.X, w.Y, w.Z}) {
+		t.Errorf("radial velocity component %.3e", r)
+	}
+	moved := w.AtEpoch(vx, vy, vz, 2010, 2020)
+	if math.Abs(moved.X-w.X-10*vx) > 1e-9 {
+		t.Errorf("AtEpoch: %+v", moved)
+	}
+	if _, _, _, err := PlateVelocity(0, 0, 0, "XXXX"); err == nil {
+		t.Error("expected error for unknown plate")
+	}
+}
+
+func TestDecimalYear(t *testing.T) {
+	cases := []struct {
+		t    time.Time
+		want float64
+	}{
+		{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), 2000},
+		{time.Date(2000, 7, 2, 0, 0, 0, 0, time.UTC), 2000 + 183.0/366},
+		{time.Date(2023, 12, 31, 12, 0, 0, 0, time.UTC), 2023 + 364.5/365},
+	}
+	for _, c := range cases {
+		if got := DecimalYear(c.t); math.Abs(got-c.want) > 1e-12 {
+			t.Errorf("DecimalYear(%v) = %.12f, want %.12f", c.t, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package gomap3d
+
+import (
+	"errors"
+	"math"
+)
+
+// ============================================================
+// 视线与椭球面求交
+//
+// 射线 P + t·u (ECEF, |u| = 1) 与半轴 (a+h, a+h, b+h) 的膨胀椭球求交，按轴长缩放后
+// 化为单位球上的一元二次方程，取 t ≥ 0 的最小根，即沿视线的第一个交点。
+// h ≠ 0 时膨胀椭球并非严格的等大地高面 (偏差约 h·f 量级)，再以牛顿迭代
+// 精化至大地高恰为 h，导数 dh/dt 为 u 在交点椭球法线上的投影。
+// ============================================================
+
+// ErrNoIntersection 视线与椭球面无交点
+var ErrNoIntersection = errors.New("lookat: line of sight does not intersect the ellipsoid")
+
+// RayEllipsoidIntersect 求 ECEF 射线 (x, y, z) + t·(ux, uy, uz) 与大地高为 height 的面的第一个交点
+// 方向无需归一化，返回交点沿视线的距离 srange (m)；射线起点位于该面以内时返回出射点。
+func RayEllipsoidIntersect(x, y, z, ux, uy, uz float64, ell *Ellipsoid, height float64) (srange float64, err error) {
+	un := math.Sqrt(ux*ux + uy*uy + uz*uz)
+	if un == 0 {
+		return 0, ErrNoIntersection
+	}
+	ux, uy, uz = ux/un, uy/un, uz/un
+	A, B := ell.SemimajorAxis+height, ell.SemiminorAxis+height
+	if A <= 0 || B <= 0 {
+		return 0, ErrNoIntersection
+	}
+
+	p := [3]float64{x / A, y / A, z / B}
+	v := [3]float64{ux / A, uy / A, uz / B}
+	qa := dot3(v, v)
+	qb := 2 * dot3(p, v)
+	qc := dot3(p, p) - 1
+	disc := qb*qb - 4*qa*qc
+	if disc < 0 {
+		return 0, ErrNoIntersection
+	}
+	// 数值稳定的求根公式
+	q := -(qb + math.Copysign(math.Sqrt(disc), qb)) / 2
+	t1, t2 := q/qa, qc/q
+	if q == 0 {
+		t1, t2 = 0, 0
+	}
+	if t1 > t2 {
+		t1, t2 = t2, t1
+	}
+	// 起点恰在面上时根可能因舍入略小于 0
+	if t2 < -1e-6 {
+		return 0, ErrNoIntersection
+	}
+	t := t1
+	if t < -1e-6 {
+		t = t2
+	}
+	t = math.Max(t, 0)
+
+	if height != 0 {
+		for i := 0; i < 5; i++ {
+			lat, lon, h := ECEF2Geodetic(x+t*ux, y+t*uy, z+t*uz, ell)
+			sl, cl := math.Sincos(lat * math.Pi / 180)
+			so, co := math.Sincos(lon * math.Pi / 180)
+			dhdt := ux*cl*co + uy*cl*so + uz*sl
+			if dhdt == 0 {
+				break
+			}
+			dt := (h - height) / dhdt
+			t -= dt
+			if math.Abs(dt) < 1e-9 {
+				break
+			}
+		}
+		if t < 0 {
+			return 0, ErrNoIntersection
+		}
+	}
+	return t, nil
+}
+
+// enu2ecefv 将站心 (lat0, lon0) 处的 ENU 方向矢量旋转至 ECEF
+func enu2ecefv(e, n, u, lat0, lon0 float64) (x, y, z float64) {
+	sl, cl := math.Sincos(lat0 * math.Pi / 180)
+	so, co := math.Sincos(lon0 * math.Pi / 180)
+	x = -so*e - sl*co*n + cl*co*u
+	y = co*e - sl*so*n + cl*so*u
+	z = cl*n + sl*u
+	return
+}
+
+// LookAtSpheroid 对标 pymap3d.lookAtSpheroid
+// 观测点 (lat0, lon0, h0) 沿方位 az、天底角 tilt (°，0 为垂直向下) 的视线与椭球面 (h = 0) 的交点，
+// srange 为视线距离 (m)；无交点时返回 ErrNoIntersection。
+func LookAtSpheroid(lat0, lon0, h0, az, tilt float64, ell *Ellipsoid) (lat, lon, srange float64, err error) {
+	p := Geodetic{Latitude: lat0, Longitude: lon0, Altitude: h0, Ell: ell}
+	g, srange, err := p.LookAt(az, tilt-90, 0)
+	return g.Latitude, g.Longitude, srange, err
+}
+
+// LookAt 沿方位 az、俯仰 el (°) 的视线与大地高为 height 的面的第一个交点
+// 返回交点与视线距离 (m)；无交点 (如水平以上视线越过地平) 时返回 ErrNoIntersection。
+func (geo *Geodetic) LookAt(az, el, height float64) (Geodetic, float64, error) {
+	e, n, u := AER2ENU(az, el, 1)
+	ux, uy, uz := enu2ecefv(e, n, u, geo.Latitude, geo.Longitude)
+	return geo.LookAtECEF(ux, uy, uz, height)
+}
+
+// LookAtECEF 沿 ECEF 方向矢量 (ux, uy, uz) 的视线与大地高为 height 的面的第一个交点
+func (geo *Geodetic) LookAtECEF(ux, uy, uz, height float64) (Geodetic, float64, error) {
+	x, y, z := Geodetic2ECEF(geo.Latitude, geo.Longitude, geo.Altitude, geo.Ell)
+	t, err := RayEllipsoidIntersect(x, y, z, ux, uy, uz, geo.Ell, height)
+	if err != nil {
+		return Geodetic{}, 0, err
+	}
+	un := math.Sqrt(ux*ux + uy*uy + uz*uz)
+	lat, lon, alt := ECEF2Geodetic(x+t*ux/un, y+t*uy/un, z+t*uz/un, geo.Ell)
+	return Geodetic{Latitude: lat, Longitude: lon, Altitude: alt, Ell: geo.Ell}, t, nil
+}
@@ -0,0 +1,89 @@
+package gomap3d
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLookAtSpheroid(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	// 垂直向下: 交点为星下点，距离为高度
+	lat, lon, sr, err := LookAtSpheroid(42, -82, 200, 0, 0, wgs)
+	if err != nil || math.Abs(lat-42) > 1e-12 || math.Abs(lon+82) > 1e-12 || math.Abs(sr-200) > 1e-6 {
+		t.Errorf("nadir: %g %g %g %v", lat, lon, sr, err)
+	}
+	// 水平及以上视线无交点
+	for _, tilt := range []float64{90, 120, 180} {
+		if _, _, _, err := LookAtSpheroid(42, -82, 200, 10, tilt, wgs); !errors.Is(err, ErrNoIntersection) {
+			t.Errorf("tilt %g: expected ErrNoIntersection, got %v", tilt, err)
+		}
+	}
+	// 位于地面的观测点向下看: 距离为 0
+	if _, _, sr, err := LookAtSpheroid(10, 20, 0, 45, 30, wgs); err != nil || math.Abs(sr) > 1e-6 {
+		t.Errorf("on surface: %g %v", sr, err)
+	}
+
+	// 球体解析解: d = (R+h)·sin(-el) - √(R² - (R+h)²·cos²el)
+	venus, _ := NewEllipsoid("venus")
+	R, h := venus.SemimajorAxis, 400e3
+	for _, el := range []float64{-89, -60, -30, -21} {
+		_, _, sr, err := LookAtSpheroid(12, 34, h, 77, el+90, venus)
+		s, c := math.Sincos(el * math.Pi / 180)
+		want := -(R+h)*s - math.Sqrt(R*R-(R+h)*(R+h)*c*c)
+		if err != nil || math.Abs(sr-want) > 1e-6 {
+			t.Errorf("sphere el %g: %.6f, want %.6f (%v)", el, sr, want, err)
+		}
+	}
+	// 高于地平俯角 (约 -20.3°) 的视线掠过行星
+	if _, _, _, err := LookAtSpheroid(12, 34, h, 77, 71, venus); !errors.Is(err, ErrNoIntersection) {
+		t.Errorf("grazing: %v", err)
+	}
+}
+
+func TestGeodeticLookAt(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	rnd := rand.New(rand.NewSource(3))
+	for i := 0; i < 500; i++ {
+		obs := Geodetic{Latitude: rnd.Float64()*180 - 90, Longitude: rnd.Float64()*360 - 180,
+			Altitude: 1e3 + rnd.Float64()*500e3, Ell: wgs}
+		az, el := rnd.Float64()*360, -5-rnd.Float64()*85
+		height := rnd.Float64() * 900
+		hit, sr, err := obs.LookAt(az, el, height)
+		if errors.Is(err, ErrNoIntersection) {
+			continue
+		}
+		if err != nil || math.Abs(hit.Altitude-height) > 1e-6 || hit.Ell != wgs {
+			t.Fatalf("%+v az %g el %g: %+v %v", obs, az, el, hit, err)
+		}
+		aer := hit.ToAER(obs)
+		if math.Abs(aer.SRange-sr) > 1e-6 || math.Abs(aer.Elevation-el) > 1e-7 ||
+			math.Abs(angNormalize(aer.Azimuth-az)) > 1e-7 {
+			t.Fatalf("%+v az %g el %g: AER %+v, srange %g", obs, az, el, aer, sr)
+		}
+		// 交点之前视线上各点均高于该面
+		for k := 1; k < 10; k++ {
+			p := AER{Azimuth: az, Elevation: el, SRange: sr * float64(k) / 10, Ell: wgs}
+			if g := p.ToGeodetic(obs); g.Altitude < height {
+				t.Fatalf("not first intersection: %+v at %g of %g", g, p.SRange, sr)
+			}
+		}
+	}
+
+	// 观测点位于高度面以下 (如云底 5 km): 向上视线返回出射点
+	obs := Geodetic{Latitude: 30, Longitude: 120, Altitude: 100, Ell: wgs}
+	hit, sr, err := obs.LookAt(0, 30, 5000)
+	if err != nil || math.Abs(hit.Altitude-5000) > 1e-6 || sr < 9780 || sr > 9800 {
+		t.Errorf("cloud base: %+v %g %v", hit, sr, err)
+	}
+	// ECEF 方向: 指向地心
+	x, y, z := Geodetic2ECEF(obs.Latitude, obs.Longitude, obs.Altitude, wgs)
+	hit, _, err = obs.LookAtECEF(-x, -y, -z, 0)
+	if err != nil || math.Abs(hit.Altitude) > 1e-6 || math.Abs(hit.Longitude-120) > 1e-9 || math.Abs(hit.Latitude-30) > 1e-4 {
+		t.Errorf("toward centre: %+v %v", hit, err)
+	}
+	if _, _, err := obs.LookAtECEF(0, 0, 0, 0); !errors.Is(err, ErrNoIntersection) {
+		t.Errorf("zero direction: %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+package gomap3d
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================
+// CCSDS 轨道平根数消息 (OMM, CCSDS 502.0-B)
+//
+// 支持 KVN（关键字 = 值）与 XML 两种编码，仅接受
+// MEAN_ELEMENT_THEORY = SGP4、REF_FRAME = TEME、TIME_SYSTEM = UTC 的消息，
+// 结果转换为 TLE 平根数，可直接用于 NewSGP4。
+// ============================================================
+
+// ParseOMM 读取 OMM，按首个非空字符自动识别 XML 或 KVN
+func ParseOMM(r io.Reader) ([]*TLE, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '<' {
+		return ParseOMMXML(bytes.NewReader(data))
+	}
+	return ParseOMMKVN(bytes.NewReader(data))
+}
+
+// LoadOMM 从本地文件读取 OMM
+func LoadOMM(path string) ([]*TLE, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseOMM(f)
+}
+
+// ParseOMMKVN 读取 KVN 编码的 OMM
+// 每条消息以 CCSDS_OMM_VERS 开始，一个文件可包含多条消息；COMMENT 行被忽略，
+// 值后的单位 "[...]" 被去除。
+func ParseOMMKVN(r io.Reader) ([]*TLE, error) {
+	var out []*TLE
+	var fields map[string]string
+	flush := func() error {
+		if fields == nil {
+			return nil
+		}
+		tle, err := ommToTLE(fields)
+		if err != nil {
+			return err
+		}
+		out = append(out, tle)
+		return nil
+	}
+
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		n++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "COMMENT") {
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("OMM line %d: missing '='", n)
+		}
+		key = strings.TrimSpace(key)
+		val = strings.TrimSpace(val)
+		if i := strings.Index(val, "["); i >= 0 {
+			val = strings.TrimSpace(val[:i])
+		}
+		if key == "CCSDS_OMM_VERS" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			fields = map[string]string{}
+		}
+		if fields == nil {
+			return nil, fmt.Errorf("OMM line %d: expected CCSDS_OMM_VERS", n)
+		}
+		fields[key] = val
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no OMM found")
+	}
+	return out, nil
+}
+
+// ParseOMMXML 读取 XML 编码的 OMM
+// 根元素可以是单个 <omm>，也可以是包含多个 <omm> 的 <ndm>（如 Space-Track 输出）。
+func ParseOMMXML(r io.Reader) ([]*TLE, error) {
+	var out []*TLE
+	var fields map[string]string
+	var text strings.Builder
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("OMM XML: %v", err)
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Local == "omm" {
+				fields = map[string]string{}
+			}
+			text.Reset()
+		case xml.CharData:
+			text.Write(el)
+		case xml.EndElement:
+			name := el.Name.Local
+			switch {
+			case name == "omm":
+				tle, err := ommToTLE(fields)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, tle)
+				fields = nil
+			case fields != nil:
+				// 叶子元素的关键字与 KVN 相同
+				if v := strings.TrimSpace(text.String()); v != "" {
+					fields[name] = v
+				}
+			}
+			text.Reset()
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no OMM found")
+	}
+	return out, nil
+}
+
+// ommToTLE 由 OMM 关键字构造 TLE 平根数
+func ommToTLE(f map[string]string) (*TLE, error) {
+	name := f["OBJECT_NAME"]
+	check := func(key, want string) error {
+		if v, ok := f[key]; ok && !strings.EqualFold(v, want) {
+			return fmt.Errorf("OMM %q: unsupported %s %q (want %s)", name, key, v, want)
+		}
+		return nil
+	}
+	if theory := strings.ToUpper(f["MEAN_ELEMENT_THEORY"]); theory != "SGP4" && theory != "SGP/SGP4" {
+		return nil, fmt.Errorf("OMM %q: unsupported MEAN_ELEMENT_THEORY %q", name, f["MEAN_ELEMENT_THEORY"])
+	}
+	for _, kv := range [][2]string{{"REF_FRAME", "TEME"}, {"TIME_SYSTEM", "UTC"}, {"CENTER_NAME", "EARTH"}} {
+		if err := check(kv[0], kv[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	var firstErr error
+	num := func(key string, required bool) float64 {
+		s, ok := f[key]
+		if !ok {
+			if required && firstErr == nil {
+				firstErr = fmt.Errorf("OMM %q: missing %s", name, key)
+			}
+			return 0
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("OMM %q: invalid %s: %v", name, key, err)
+		}
+		return v
+	}
+
+	tle := &TLE{
+		Name:           name,
+		SatNum:         int(num("NORAD_CAT_ID", false)),
+		IntlDesignator: ommIntlDesignator(f["OBJECT_ID"]),
+		MeanMotion:     num("MEAN_MOTION", true),
+		Eccentricity:   num("ECCENTRICITY", true),
+		Inclination:    num("INCLINATION", true),
+		RAAN:           num("RA_OF_ASC_NODE", true),
+		ArgPerigee:     num("ARG_OF_PERICENTER", true),
+		MeanAnomaly:    num("MEAN_ANOMALY", true),
+		EphemerisType:  int(num("EPHEMERIS_TYPE", false)),
+		ElementSetNo:   int(num("ELEMENT_SET_NO", false)),
+		RevNumber:      int(num("REV_AT_EPOCH", false)),
+		BStar:          num("BSTAR", false),
+		MeanMotionDot:  num("MEAN_MOTION_DOT", false),
+		MeanMotionDDot: num("MEAN_MOTION_DDOT", false),
+		Classification: 'U',
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if c := f["CLASSIFICATION_TYPE"]; c != "" {
+		tle.Classification = c[0]
+	}
+	ep, ok := f["EPOCH"]
+	if !ok {
+		return nil, fmt.Errorf("OMM %q: missing EPOCH", name)
+	}
+	t, err := parseCCSDSTime(ep)
+	if err != nil {
+		return nil, fmt.Errorf("OMM %q: invalid EPOCH: %v", name, err)
+	}
+	tle.Epoch = t
+	return tle, nil
+}
+
+// ommIntlDesignator 将 OBJECT_ID "1958-002B" 转为 TLE 格式 "58002B"
+func ommIntlDesignator(id string) string {
+	if len(id) >= 9 && id[4] == '-' {
+		return id[2:4] + id[5:]
+	}
+	return id
+}
+
+// parseCCSDSTime 解析 CCSDS 时间 "YYYY-MM-DDThh:mm:ss[.d]" 或 "YYYY-DDDThh:mm:ss[.d]"
+func parseCCSDSTime(s string) (time.Time, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "Z")
+	date, clock, ok := strings.Cut(s, "T")
+	if !ok {
+		clock = "00:00:00"
+	}
+	var day time.Time
+	var err error
+	if strings.Count(date, "-") == 1 {
+		day, err = time.Parse("2006-002", date)
+	} else {
+		day, err = time.Parse("2006-01-02", date)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	parts := strings.Split(clock, ":")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("invalid time of day %q", clock)
+	}
+	h, err1 := strconv.Atoi(parts[0])
+	m, err2 := strconv.Atoi(parts[1])
+	sec, err3 := strconv.ParseFloat(parts[2], 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return time.Time{}, fmt.Errorf("invalid time of day %q", clock)
+	}
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(sec*1e6+0.5)*time.Microsecond
+	return day.Add(d), nil
+}
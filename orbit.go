@@ -0,0 +1,356 @@
+package gomap3d
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ============================================================
+// 经典开普勒轨道根数与惯性系状态矢量互转
+//
+// 角度字段单位为度，近点角换算函数 (SolveKepler 等) 使用弧度。
+// 奇异情形约定 (Vallado, Fundamentals of Astrodynamics, Alg. 9/10):
+//   圆轨道      ω = 0，TrueAnomaly 为纬度幅角 u
+//   赤道轨道    Ω = 0，ArgPerigee 为近点经度 ϖ
+//   圆赤道轨道  Ω = ω = 0，TrueAnomaly 为真经度 λ
+// 逆行赤道轨道 (i = 180°) 的 ϖ、λ 按轨道运动方向计量。
+// ============================================================
+
+// orbitTol 判定圆轨道、赤道轨道与抛物线轨道的容差
+const orbitTol = 1e-11
+
+// ErrParabolic 抛物线轨道不存在半长轴、周期等量
+var ErrParabolic = errors.New("orbit: parabolic orbit")
+
+// OrbitalElements 经典开普勒轨道根数
+type OrbitalElements struct {
+	SemimajorAxis   float64 // 半长轴 a (m)，双曲轨道为负，抛物线为 +Inf
+	SemiLatusRectum float64 // 半通径 p (m)，为 0 时由 a(1-e²) 计算；抛物线轨道必需
+	Eccentricity    float64 // 偏心率 e
+	Inclination     float64 // 倾角 i (°)
+	RAAN            float64 // 升交点赤经 Ω (°)
+	ArgPerigee      float64 // 近地点幅角 ω (°)
+	TrueAnomaly     float64 // 真近点角 ν (°)
+	GM              float64 // 中心天体引力常数 μ (m³/s²)
+}
+
+// ElementsFromMeanAnomaly 由平近点角 (°) 构造轨道根数，a ≤ 0 或 e ≥ 1 时按双曲轨道处理
+func ElementsFromMeanAnomaly(a, e, inc, raan, argp, meanAnomaly, gm float64) OrbitalElements {
+	nu := MeanToTrue(meanAnomaly*math.Pi/180, e)
+	return OrbitalElements{
+		SemimajorAxis: a,
+		Eccentricity:  e,
+		Inclination:   inc,
+		RAAN:          raan,
+		ArgPerigee:    argp,
+		TrueAnomaly:   wrapDeg(nu * 180 / math.Pi),
+		GM:            gm,
+	}
+}
+
+// semiLatusRectum 半通径 p (m)
+func (el OrbitalElements) semiLatusRectum() float64 {
+	if el.SemiLatusRectum > 0 {
+		return el.SemiLatusRectum
+	}
+	return el.SemimajorAxis * (1 - el.Eccentricity*el.Eccentricity)
+}
+
+// EccentricAnomaly 偏近点角 (°)；双曲轨道为双曲近点角 H，抛物线轨道为 D = tan(ν/2)
+func (el OrbitalElements) EccentricAnomaly() float64 {
+	return TrueToEccentric(el.TrueAnomaly*math.Pi/180, el.Eccentricity) * 180 / math.Pi
+}
+
+// MeanAnomaly 平近点角 (°)；抛物线轨道为 Barker 方程中的 M = D + D³/3
+func (el OrbitalElements) MeanAnomaly() float64 {
+	m := TrueToMean(el.TrueAnomaly*math.Pi/180, el.Eccentricity) * 180 / math.Pi
+	if el.Eccentricity < 1 {
+		return wrapDeg(m)
+	}
+	return m
+}
+
+// MeanMotion 平均运动 n (rad/s)
+func (el OrbitalElements) MeanMotion() (float64, error) {
+	if math.Abs(el.Eccentricity-1) < orbitTol {
+		return 0, ErrParabolic
+	}
+	a := math.Abs(el.SemimajorAxis)
+	return math.Sqrt(el.GM / (a * a * a)), nil
+}
+
+// Period 轨道周期 (s)，仅对椭圆轨道有效
+func (el OrbitalElements) Period() (float64, error) {
+	if el.Eccentricity >= 1 {
+		return 0, fmt.Errorf("orbit: period undefined for e = %g", el.Eccentricity)
+	}
+	n, err := el.MeanMotion()
+	if err != nil {
+		return 0, err
+	}
+	return tau / n, nil
+}
+
+// StateToElements 由惯性系位置 (m) 与速度 (m/s) 计算经典轨道根数
+func StateToElements(x, y, z, vx, vy, vz, gm float64) (OrbitalElements, error) {
+	if gm <= 0 {
+		return OrbitalElements{}, fmt.Errorf("orbit: GM must be positive")
+	}
+	r := [3]float64{x, y, z}
+	v := [3]float64{vx, vy, vz}
+	rn := norm3(r)
+	vn := norm3(v)
+	h := cross3(r, v)
+	hn := norm3(h)
+	if rn == 0 || hn < orbitTol*rn*vn {
+		return OrbitalElements{}, fmt.Errorf("orbit: degenerate (rectilinear) state")
+	}
+
+	// 偏心率矢量 e = ((v² - μ/r) r - (r·v) v) / μ
+	rv := dot3(r, v)
+	c1 := vn*vn - gm/rn
+	ev := [3]float64{
+		(c1*r[0] - rv*v[0]) / gm,
+		(c1*r[1] - rv*v[1]) / gm,
+		(c1*r[2] - rv*v[2]) / gm,
+	}
+	e := norm3(ev)
+
+	el := OrbitalElements{Eccentricity: e, GM: gm}
+	el.SemiLatusRectum = hn * hn / gm
+	energy := vn*vn/2 - gm/rn
+	if math.Abs(e-1) < orbitTol {
+		el.SemimajorAxis = math.Inf(1)
+	} else {
+		el.SemimajorAxis = -gm / (2 * energy)
+	}
+
+	hhat := [3]float64{h[0] / hn, h[1] / hn, h[2] / hn}
+	inc := math.Atan2(math.Hypot(h[0], h[1]), h[2])
+	el.Inclination = inc * 180 / math.Pi
+
+	// 节线矢量 n = ẑ × h
+	nodeVec := [3]float64{-h[1], h[0], 0}
+	circular := e < orbitTol
+	equatorial := math.Hypot(h[0], h[1]) < orbitTol*hn
+
+	// angle 为 a 到 b 绕 ĥ 的有向夹角 (rad)
+	angle := func(a, b [3]float64) float64 {
+		return math.Atan2(dot3(cross3(a, b), hhat), dot3(a, b))
+	}
+	// 逆行赤道轨道沿运动方向计量经度
+	lon := func(a [3]float64) float64 {
+		l := math.Atan2(a[1], a[0])
+		if inc > math.Pi/2 {
+			l = -l
+		}
+		return l
+	}
+
+	switch {
+	case circular && equatorial:
+		el.TrueAnomaly = lon(r)
+	case circular:
+		el.RAAN = math.Atan2(nodeVec[1], nodeVec[0])
+		el.TrueAnomaly = angle(nodeVec, r)
+	case equatorial:
+		el.ArgPerigee = lon(ev)
+		el.TrueAnomaly = angle(ev, r)
+	default:
+		el.RAAN = math.Atan2(nodeVec[1], nodeVec[0])
+		el.ArgPerigee = angle(nodeVec, ev)
+		el.TrueAnomaly = angle(ev, r)
+	}
+	el.RAAN = wrapDeg(el.RAAN * 180 / math.Pi)
+	el.ArgPerigee = wrapDeg(el.ArgPerigee * 180 / math.Pi)
+	el.TrueAnomaly = wrapDeg(el.TrueAnomaly * 180 / math.Pi)
+	return el, nil
+}
+
+// ToState 由轨道根数计算惯性系位置 (m) 与速度 (m/s)
+func (el OrbitalElements) ToState() (x, y, z, vx, vy, vz float64, err error) {
+	p := el.semiLatusRectum()
+	e := el.Eccentricity
+	if el.GM <= 0 {
+		return 0, 0, 0, 0, 0, 0, fmt.Errorf("orbit: GM must be positive")
+	}
+	if !(p > 0) || e < 0 {
+		return 0, 0, 0, 0, 0, 0, fmt.Errorf("orbit: invalid shape a=%g e=%g p=%g", el.SemimajorAxis, e, p)
+	}
+	nu := el.TrueAnomaly * math.Pi / 180
+	sinNu, cosNu := math.Sin(nu), math.Cos(nu)
+	den := 1 + e*cosNu
+	if den <= 0 {
+		return 0, 0, 0, 0, 0, 0, fmt.Errorf("orbit: true anomaly %g° beyond hyperbolic asymptote", el.TrueAnomaly)
+	}
+
+	// 近焦点坐标系
+	rpf := [3]float64{p * cosNu / den, p * sinNu / den, 0}
+	sq := math.Sqrt(el.GM / p)
+	vpf := [3]float64{-sq * sinNu, sq * (e + cosNu), 0}
+
+	// 近焦点 → 惯性: [R3(ω) · R1(i) · R3(Ω)]ᵀ
+	m := transpose(mul33(R3(el.ArgPerigee*math.Pi/180),
+		mul33(Rx(el.Inclination*math.Pi/180), R3(el.RAAN*math.Pi/180))))
+	r := multiplyMatrixVector(m, rpf)
+	v := multiplyMatrixVector(m, vpf)
+	return r[0], r[1], r[2], v[0], v[1], v[2], nil
+}
+
+// ToOrbitalElements 由 ECI 位置与速度 (m/s) 计算轨道根数，GM 取自 eci.Ell
+func (eci *ECI) ToOrbitalElements(vx, vy, vz float64) (OrbitalElements, error) {
+	if eci.Ell == nil {
+		return OrbitalElements{}, fmt.Errorf("orbit: ECI has no ellipsoid")
+	}
+	return StateToElements(eci.X, eci.Y, eci.Z, vx, vy, vz, eci.Ell.GM)
+}
+
+// ToECI 由轨道根数得到时刻 t 的 ECI 位置与速度 (m/s)
+func (el OrbitalElements) ToECI(t time.Time, ell *Ellipsoid) (eci ECI, vx, vy, vz float64, err error) {
+	x, y, z, vx, vy, vz, err := el.ToState()
+	return ECI{X: x, Y: y, Z: z, T: t, Ell: ell}, vx, vy, vz, err
+}
+
+// ============================================================
+// 近点角换算 (rad)
+// e < 1 为椭圆 (偏近点角 E)，e > 1 为双曲 (H)，e = 1 为抛物线 (D = tan(ν/2))
+// ============================================================
+
+// TrueToEccentric 真近点角 → 偏近点角 (E / H / D)
+func TrueToEccentric(nu, e float64) float64 {
+	sinNu, cosNu := math.Sin(nu), math.Cos(nu)
+	switch {
+	case math.Abs(e-1) < orbitTol:
+		return math.Tan(nu / 2)
+	case e < 1:
+		return math.Atan2(math.Sqrt(1-e*e)*sinNu, e+cosNu)
+	default:
+		return math.Asinh(math.Sqrt(e*e-1) * sinNu / (1 + e*cosNu))
+	}
+}
+
+// EccentricToTrue 偏近点角 (E / H / D) → 真近点角
+func EccentricToTrue(ea, e float64) float64 {
+	switch {
+	case math.Abs(e-1) < orbitTol:
+		return 2 * math.Atan(ea)
+	case e < 1:
+		return math.Atan2(math.Sqrt(1-e*e)*math.Sin(ea), math.Cos(ea)-e)
+	default:
+		return math.Atan2(math.Sqrt(e*e-1)*math.Sinh(ea), e-math.Cosh(ea))
+	}
+}
+
+// EccentricToMean 偏近点角 → 平近点角
+// 椭圆 M = E - e·sinE，双曲 M = e·sinhH - H，抛物线 M = D + D³/3
+func EccentricToMean(ea, e float64) float64 {
+	switch {
+	case math.Abs(e-1) < orbitTol:
+		return ea + ea*ea*ea/3
+	case e < 1:
+		return ea - e*math.Sin(ea)
+	default:
+		return e*math.Sinh(ea) - ea
+	}
+}
+
+// MeanToEccentric 平近点角 → 偏近点角，求解对应的开普勒方程
+func MeanToEccentric(m, e float64) float64 {
+	switch {
+	case math.Abs(e-1) < orbitTol:
+		return SolveBarker(m)
+	case e < 1:
+		return SolveKepler(m, e)
+	default:
+		return SolveKeplerHyperbolic(m, e)
+	}
+}
+
+// TrueToMean 真近点角 → 平近点角
+func TrueToMean(nu, e float64) float64 {
+	return EccentricToMean(TrueToEccentric(nu, e), e)
+}
+
+// MeanToTrue 平近点角 → 真近点角
+func MeanToTrue(m, e float64) float64 {
+	return EccentricToTrue(MeanToEccentric(m, e), e)
+}
+
+// SolveKepler 求解椭圆开普勒方程 M = E - e·sinE (0 ≤ e < 1)，返回与 M 同周的 E
+func SolveKepler(m, e float64) float64 {
+	// 归一化到 (-π, π] 后迭代，结果再加回整周
+	k := math.Round(m / tau)
+	mr := m - k*tau
+	ea := mr
+	if e > 0.8 {
+		ea = math.Copysign(math.Pi, mr)
+	} else if e > 0 {
+		ea = mr + e*math.Sin(mr)
+	}
+	for i := 0; i < 50; i++ {
+		f := ea - e*math.Sin(ea) - mr
+		d := f / (1 - e*math.Cos(ea))
+		ea -= d
+		if math.Abs(d) < 1e-15 {
+			break
+		}
+	}
+	return ea + k*tau
+}
+
+// SolveKeplerHyperbolic 求解双曲开普勒方程 M = e·sinhH - H (e > 1)
+func SolveKeplerHyperbolic(m, e float64) float64 {
+	// 初值取 cbrt(6M/e) 与 asinh(M/(e-1)) 中较小者，二者均不小于真根，
+	// 牛顿迭代对凸函数单调收敛，近抛物线 (e → 1) 时同样稳定
+	am := math.Abs(m)
+	h := math.Min(math.Cbrt(6*am/e), math.Asinh(am/(e-1)))
+	for i := 0; i < 200; i++ {
+		f := e*math.Sinh(h) - h - am
+		d := f / (e*math.Cosh(h) - 1)
+		h -= d
+		if math.Abs(d) < 1e-15*math.Max(1, h) {
+			break
+		}
+	}
+	return math.Copysign(h, m)
+}
+
+// SolveBarker 求解抛物线 Barker 方程 M = D + D³/3，返回 D = tan(ν/2)
+func SolveBarker(m float64) float64 {
+	// D³ + 3D - 3M = 0 的唯一实根 (Cardano)，写成 A - 1/A 避免大 M 时的相消
+	w := 1.5 * math.Abs(m)
+	a := math.Cbrt(w + math.Sqrt(w*w+1))
+	d := a - 1/a
+	d -= (d + d*d*d/3 - math.Abs(m)) / (1 + d*d)
+	return math.Copysign(d, m)
+}
+
+// wrapDeg 角度归一化到 [0, 360)
+func wrapDeg(a float64) float64 {
+	a = math.Mod(a, 360)
+	if a < 0 {
+		a += 360
+	}
+	if a >= 360 {
+		a = 0
+	}
+	return a
+}
+
+func dot3(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross3(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func norm3(a [3]float64) float64 {
+	return math.Sqrt(dot3(a, a))
+}
@@ -0,0 +1,259 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+const gmEarth = 3.986004418e14
+
+// angleDiff 两角度差 (°)，考虑 360° 周期
+func angleDiff(a, b float64) float64 {
+	d := math.Mod(a-b, 360)
+	if d > 180 {
+		d -= 360
+	} else if d < -180 {
+		d += 360
+	}
+	return math.Abs(d)
+}
+
+func TestStateToElementsVallado(t *testing.T) {
+	// Vallado, Fundamentals of Astrodynamics, Example 2-5
+	el, err := StateToElements(6524834, 6862875, 6448296, 4901.327, 5533.756, -1976.341, gmEarth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(el.SemiLatusRectum-11067790) > 50 || math.Abs(el.SemimajorAxis-36127343) > 200 ||
+		math.Abs(el.Eccentricity-0.832853) > 1e-6 {
+		t.Errorf("shape: p=%.1f a=%.1f e=%.7f", el.SemiLatusRectum, el.SemimajorAxis, el.Eccentricity)
+	}
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"i", el.Inclination, 87.870},
+		{"Ω", el.RAAN, 227.898},
+		{"ω", el.ArgPerigee, 53.38},
+		{"ν", el.TrueAnomaly, 92.335},
+	} {
+		if angleDiff(c.got, c.want) > 0.01 {
+			t.Errorf("%s = %.4f°, want %.3f°", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestElementsRoundtrip(t *testing.T) {
+	moon, _ := NewEllipsoid("moon")
+	mars, _ := NewEllipsoid("mars")
+	tests := []struct {
+		name string
+		el   OrbitalElements
+	}{
+		{"general", OrbitalElements{SemimajorAxis: 7000e3, Eccentricity: 0.1, Inclination: 51.6, RAAN: 30, ArgPerigee: 40, TrueAnomaly: 50, GM: gmEarth}},
+		{"circular inclined", OrbitalElements{SemimajorAxis: 7000e3, Inclination: 98, RAAN: 120, TrueAnomaly: 200, GM: gmEarth}},
+		{"equatorial", OrbitalElements{SemimajorAxis: 24000e3, Eccentricity: 0.7, ArgPerigee: 300, TrueAnomaly: 10, GM: gmEarth}},
+		{"circular equatorial", OrbitalElements{SemimajorAxis: 42164e3, TrueAnomaly: 75, GM: gmEarth}},
+		{"retrograde equatorial", OrbitalElements{SemimajorAxis: 8000e3, Eccentricity: 0.2, Inclination: 180, ArgPerigee: 60, TrueAnomaly: 100, GM: gmEarth}},
+		{"retrograde circular equatorial", OrbitalElements{SemimajorAxis: 8000e3, Inclination: 180, TrueAnomaly: 100, GM: gmEarth}},
+		{"polar", OrbitalElements{SemimajorAxis: 7500e3, Eccentricity: 0.01, Inclination: 90, RAAN: 350, ArgPerigee: 270, TrueAnomaly: 359, GM: gmEarth}},
+		{"hyperbolic", OrbitalElements{SemimajorAxis: -20000e3, Eccentricity: 1.5, Inclination: 30, RAAN: 10, ArgPerigee: 20, TrueAnomaly: -100, GM: gmEarth}},
+		{"parabolic", OrbitalElements{SemimajorAxis: math.Inf(1), SemiLatusRectum: 14000e3, Eccentricity: 1, Inclination: 45, RAAN: 80, ArgPerigee: 120, TrueAnomaly: 60, GM: gmEarth}},
+		{"moon", OrbitalElements{SemimajorAxis: 1838e3, Eccentricity: 0.02, Inclination: 85, RAAN: 5, ArgPerigee: 90, TrueAnomaly: 45, GM: moon.GM}},
+		{"mars", OrbitalElements{SemimajorAxis: 3800e3, Eccentricity: 0.05, Inclination: 93, RAAN: 200, ArgPerigee: 10, TrueAnomaly: 300, GM: mars.GM}},
+	}
+	for _, tc := range tests {
+		x, y, z, vx, vy, vz, err := tc.el.ToState()
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		el, err := StateToElements(x, y, z, vx, vy, vz, tc.el.GM)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if math.Abs(el.Eccentricity-tc.el.Eccentricity) > 1e-9 || angleDiff(el.Inclination, tc.el.Inclination) > 1e-9 ||
+			angleDiff(el.RAAN, tc.el.RAAN) > 1e-7 || angleDiff(el.ArgPerigee, tc.el.ArgPerigee) > 1e-6 ||
+			angleDiff(el.TrueAnomaly, tc.el.TrueAnomaly) > 1e-6 {
+			t.Errorf("%s: got %+v", tc.name, el)
+		}
+		if !math.IsInf(tc.el.SemimajorAxis, 1) && math.Abs(el.SemimajorAxis/tc.el.SemimajorAxis-1) > 1e-9 {
+			t.Errorf("%s: a = %.3f", tc.name, el.SemimajorAxis)
+		}
+		x2, y2, z2, vx2, vy2, vz2, _ := el.ToState()
+		if d := vecDist([3]float64{x, y, z}, [3]float64{x2, y2, z2}); d > 1e-5 {
+			t.Errorf("%s: position roundtrip error %.3e m", tc.name, d)
+		}
+		if d := vecDist([3]float64{vx, vy, vz}, [3]float64{vx2, vy2, vz2}); d > 1e-8 {
+			t.Errorf("%s: velocity roundtrip error %.3e m/s", tc.name, d)
+		}
+	}
+}
+
+func TestElementsSingularConventions(t *testing.T) {
+	// 圆轨道: ω = 0，ν 为纬度幅角
+	v := math.Sqrt(gmEarth / 7000e3)
+	el, _ := StateToElements(0, 7000e3, 0, 0, 0, v, gmEarth)
+	if el.ArgPerigee != 0 || angleDiff(el.Inclination, 90) > 1e-9 || angleDiff(el.RAAN, 90) > 1e-9 ||
+		angleDiff(el.TrueAnomaly, 0) > 1e-6 {
+		t.Errorf("circular: %+v", el)
+	}
+	// 赤道轨道: Ω = 0，ω 为近点经度
+	el, _ = StateToElements(0, 7000e3, 0, -8500, 0, 0, gmEarth)
+	if el.RAAN != 0 || angleDiff(el.ArgPerigee, 90) > 1e-9 || angleDiff(el.TrueAnomaly, 0) > 1e-9 {
+		t.Errorf("equatorial: %+v", el)
+	}
+	// 圆赤道轨道: Ω = ω = 0，ν 为真经度
+	el, _ = StateToElements(0, 7000e3, 0, -v, 0, 0, gmEarth)
+	if el.RAAN != 0 || el.ArgPerigee != 0 || angleDiff(el.TrueAnomaly, 90) > 1e-9 || el.Eccentricity > 1e-12 {
+		t.Errorf("circular equatorial: %+v", el)
+	}
+	// 逆行圆赤道轨道: 真经度沿运动方向计量
+	el, _ = StateToElements(0, 7000e3, 0, v, 0, 0, gmEarth)
+	if angleDiff(el.Inclination, 180) > 1e-12 || angleDiff(el.TrueAnomaly, 270) > 1e-9 {
+		t.Errorf("retrograde circular equatorial: %+v", el)
+	}
+	// 直线运动无法定义根数
+	if _, err := StateToElements(7000e3, 0, 0, 1000, 0, 0, gmEarth); err == nil {
+		t.Error("expected error for rectilinear state")
+	}
+	if _, err := (OrbitalElements{SemimajorAxis: 7000e3, Eccentricity: 2, TrueAnomaly: 150, GM: gmEarth}).Period(); err == nil {
+		t.Error("expected error for hyperbolic period")
+	}
+}
+
+func TestKeplerSolvers(t *testing.T) {
+	for _, e := range []float64{0, 0.001, 0.1, 0.5, 0.9, 0.99, 0.999999} {
+		for m := -7.0; m <= 7.0; m += 0.25 {
+			ea := SolveKepler(m, e)
+			if d := math.Abs(ea - e*math.Sin(ea) - m); d > 1e-13 {
+				t.Errorf("elliptic e=%g M=%g: residual %.3e", e, m, d)
+			}
+		}
+	}
+	for _, e := range []float64{1.000001, 1.1, 2, 10, 100} {
+		for _, m := range []float64{-1000, -10, -1, -0.01, 0, 0.01, 1, 10, 1000} {
+			h := SolveKeplerHyperbolic(m, e)
+			if d := math.Abs(e*math.Sinh(h) - h - m); d > 1e-12*math.Max(1, math.Abs(m)) {
+				t.Errorf("hyperbolic e=%g M=%g: residual %.3e", e, m, d)
+			}
+		}
+	}
+	for _, m := range []float64{-100, -1, 0, 0.5, 3, 1e4} {
+		d := SolveBarker(m)
+		if r := math.Abs(d + d*d*d/3 - m); r > 1e-12*math.Max(1, math.Abs(m)) {
+			t.Errorf("Barker M=%g: residual %.3e", m, r)
+		}
+	}
+
+	// 真近点角 ↔ 平近点角往返
+	for _, e := range []float64{0, 0.3, 0.95, 1, 1.5, 5} {
+		for _, nu := range []float64{-2.5, -1, 0, 0.3, 1.5, 2.5} {
+			if e > 1 && 1+e*math.Cos(nu) <= 0 {
+				continue
+			}
+			if got := MeanToTrue(TrueToMean(nu, e), e); math.Abs(got-nu) > 1e-10 {
+				t.Errorf("e=%g ν=%g: roundtrip %g", e, nu, got)
+			}
+		}
+	}
+}
+
+func TestEquinoctial(t *testing.T) {
+	tests := []OrbitalElements{
+		{SemimajorAxis: 7000e3, Eccentricity: 0.1, Inclination: 51.6, RAAN: 30, ArgPerigee: 40, TrueAnomaly: 50, GM: gmEarth},
+		{SemimajorAxis: 42164e3, Eccentricity: 1e-14, Inclination: 1e-13, RAAN: 0, ArgPerigee: 0, TrueAnomaly: 123, GM: gmEarth},
+		{SemimajorAxis: 26560e3, Eccentricity: 0.7, Inclination: 63.4, RAAN: 250, ArgPerigee: 270, TrueAnomaly: 5, GM: gmEarth},
+		{SemimajorAxis: 8000e3, Eccentricity: 0.2, Inclination: 170, RAAN: 45, ArgPerigee: 60, TrueAnomaly: 100, GM: gmEarth},
+		{SemimajorAxis: 8000e3, Eccentricity: 0.2, Inclination: 180, ArgPerigee: 60, TrueAnomaly: 100, GM: gmEarth},
+	}
+	for i, el := range tests {
+		x, y, z, vx, vy, vz, _ := el.ToState()
+		r0 := [3]float64{x, y, z}
+		v0 := [3]float64{vx, vy, vz}
+
+		eq, err := el.ToEquinoctial()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mee, err := el.ToModifiedEquinoctial()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if eq.Retrograde != (el.Inclination > 90) || mee.Retrograde != eq.Retrograde {
+			t.Errorf("case %d: retrograde flag %v/%v", i, eq.Retrograde, mee.Retrograde)
+		}
+
+		// 与经典根数的关系: λ = M + ϖ，L = ν + ϖ，ϖ = ω + I·Ω
+		fr := retroFactor(eq.Retrograde)
+		varpi := el.ArgPerigee + fr*el.RAAN
+		if angleDiff(eq.MeanLongitude, el.MeanAnomaly()+varpi) > 1e-7 {
+			t.Errorf("case %d: λ = %.9f, want %.9f", i, eq.MeanLongitude, wrapDeg(el.MeanAnomaly()+varpi))
+		}
+		if angleDiff(mee.TrueLongitude, el.TrueAnomaly+varpi) > 1e-7 {
+			t.Errorf("case %d: L = %.9f, want %.9f", i, mee.TrueLongitude, wrapDeg(el.TrueAnomaly+varpi))
+		}
+		if math.Abs(math.Hypot(eq.H, eq.K)-el.Eccentricity) > 1e-12 || math.Abs(eq.A/el.SemimajorAxis-1) > 1e-12 {
+			t.Errorf("case %d: equinoctial %+v", i, eq)
+		}
+
+		for name, f := range map[string]func() (float64, float64, float64, float64, float64, float64, error){
+			"equinoctial":          eq.ToState,
+			"modified equinoctial": mee.ToState,
+		} {
+			x, y, z, vx, vy, vz, err := f()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := vecDist(r0, [3]float64{x, y, z}); d > 1e-5 {
+				t.Errorf("case %d %s: position error %.3e m", i, name, d)
+			}
+			if d := vecDist(v0, [3]float64{vx, vy, vz}); d > 1e-8 {
+				t.Errorf("case %d %s: velocity error %.3e m/s", i, name, d)
+			}
+		}
+	}
+
+	// 修正春分点根数适用于双曲轨道，春分点根数不适用
+	hyp := OrbitalElements{SemimajorAxis: -20000e3, Eccentricity: 1.5, Inclination: 30, RAAN: 10, ArgPerigee: 20, TrueAnomaly: 40, GM: gmEarth}
+	if _, err := hyp.ToEquinoctial(); err == nil {
+		t.Error("expected error for hyperbolic equinoctial elements")
+	}
+	mee, err := hyp.ToModifiedEquinoctial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, _ := mee.ToElements()
+	if math.Abs(back.Eccentricity-1.5) > 1e-12 || angleDiff(back.TrueAnomaly, 40) > 1e-9 {
+		t.Errorf("hyperbolic MEE roundtrip: %+v", back)
+	}
+}
+
+func TestECIOrbitalElements(t *testing.T) {
+	ell, _ := NewEllipsoid("wgs84")
+	if ell.GM != gmEarth {
+		t.Fatalf("wgs84 GM = %g", ell.GM)
+	}
+	el := ElementsFromMeanAnomaly(7000e3, 0.01, 51.6, 30, 40, 10, ell.GM)
+	if angleDiff(el.MeanAnomaly(), 10) > 1e-10 {
+		t.Errorf("mean anomaly = %.12f", el.MeanAnomaly())
+	}
+	tUTC := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	eci, vx, vy, vz, err := el.ToECI(tUTC, ell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := eci.ToOrbitalElements(vx, vy, vz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(back.SemimajorAxis-7000e3) > 1e-6 || angleDiff(back.MeanAnomaly(), 10) > 1e-9 {
+		t.Errorf("ECI roundtrip: %+v", back)
+	}
+	period, _ := back.Period()
+	if want := tau * math.Sqrt(7000e3*7000e3*7000e3/gmEarth); math.Abs(period-want) > 1e-6 {
+		t.Errorf("period = %.6f, want %.6f", period, want)
+	}
+}
@@ -0,0 +1,331 @@
+package gomap3d
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ============================================================
+// 过境预报
+//
+// 对星历回调按固定步长粗搜索可见性变化，再以二分法将进出境时刻求精到
+// Tolerance；过境内以黄金分割搜索最大仰角 (中天)。
+// 可见条件: 仰角 ≥ 遮蔽角 (常数或随方位角变化) 且斜距 ≤ MaxRange。
+// 时长短于搜索步长的过境可能漏检。
+// ============================================================
+
+// Ephemeris 目标星历回调，返回时刻 t 目标的 ECEF 位置 (m)
+type Ephemeris func(t time.Time) (ECEF, error)
+
+// SGP4Ephemeris 以 SGP4 传播器作为星历
+func SGP4Ephemeris(s *SGP4, ell *Ellipsoid) Ephemeris {
+	return func(t time.Time) (ECEF, error) { return s.ECEF(t, ell) }
+}
+
+// PropagatorEphemeris 以数值传播器作为星历，ECI → ECEF 使用传播器的转换器
+// 每次调用从初始历元积分，适合短弧段；长时间窗口宜先 PropagateEpochs 再插值。
+func PropagatorEphemeris(p *Propagator) Ephemeris {
+	return func(t time.Time) (ECEF, error) {
+		s, err := p.Propagate(t)
+		if err != nil {
+			return ECEF{}, err
+		}
+		return s.ToECEFWith(p.tr), nil
+	}
+}
+
+// ElevationMask 测站遮蔽角
+type ElevationMask interface {
+	// MinElevation 方位角 az (°) 处的最低可见仰角 (°)
+	MinElevation(az float64) float64
+}
+
+// ConstantMask 固定遮蔽角 (°)
+type ConstantMask float64
+
+// MinElevation 实现 ElevationMask
+func (m ConstantMask) MinElevation(az float64) float64 { return float64(m) }
+
+// AzimuthMask 随方位角变化的遮蔽角，表点间线性插值，跨 360° 首尾相接
+type AzimuthMask struct {
+	Azimuth   []float64 // 方位角 (°)，升序，位于 [0, 360)
+	Elevation []float64 // 对应的遮蔽角 (°)
+}
+
+// NewAzimuthMask 由方位角-遮蔽角表创建遮蔽，方位角自动归一化并排序
+func NewAzimuthMask(az, el []float64) (*AzimuthMask, error) {
+	if len(az) == 0 || len(az) != len(el) {
+		return nil, fmt.Errorf("mask: need equal-length non-empty tables, got %d/%d", len(az), len(el))
+	}
+	idx := make([]int, len(az))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(a, b int) bool { return wrapDeg(az[idx[a]]) < wrapDeg(az[idx[b]]) })
+	m := &AzimuthMask{Azimuth: make([]float64, len(az)), Elevation: make([]float64, len(az))}
+	for k, i := range idx {
+		m.Azimuth[k] = wrapDeg(az[i])
+		m.Elevation[k] = el[i]
+		if k > 0 && m.Azimuth[k] == m.Azimuth[k-1] {
+			return nil, fmt.Errorf("mask: duplicate azimuth %g°", m.Azimuth[k])
+		}
+	}
+	return m, nil
+}
+
+// MinElevation 实现 ElevationMask
+func (m *AzimuthMask) MinElevation(az float64) float64 {
+	n := len(m.Azimuth)
+	if n == 1 {
+		return m.Elevation[0]
+	}
+	az = wrapDeg(az)
+	i := sort.SearchFloat64s(m.Azimuth, az)
+	// 区间 [a0, a1]，必要时跨越 360°
+	var a0, e0, a1, e1 float64
+	switch {
+	case i == 0 || i == n:
+		a0, e0 = m.Azimuth[n-1], m.Elevation[n-1]
+		a1, e1 = m.Azimuth[0]+360, m.Elevation[0]
+		if az < a0 {
+			az += 360
+		}
+	default:
+		a0, e0 = m.Azimuth[i-1], m.Elevation[i-1]
+		a1, e1 = m.Azimuth[i], m.Elevation[i]
+	}
+	if az == a1 {
+		return e1
+	}
+	return e0 + (e1-e0)*(az-a0)/(a1-a0)
+}
+
+// PassOptions 过境预报选项，nil 等价于零值
+type PassOptions struct {
+	Mask      ElevationMask // 遮蔽角，nil 为 0°
+	MaxRange  float64       // 最大作用距离 (m)，0 为不限
+	Step      time.Duration // 粗搜索步长，默认 60 s
+	Tolerance time.Duration // 进出境与中天时刻精度，默认 1 ms
+}
+
+// PassEvent 过境事件的时刻与视角
+type PassEvent struct {
+	T   time.Time
+	AER AER
+}
+
+// Pass 一次过境
+type Pass struct {
+	Rise        PassEvent // 进境 (AOS)
+	Culmination PassEvent // 中天，过境内仰角最大处
+	Set         PassEvent // 出境 (LOS)
+	RiseClipped bool      // 时间窗起点已在境内，Rise 为窗口起点
+	SetClipped  bool      // 时间窗终点仍在境内，Set 为窗口终点
+}
+
+// Duration 过境时长
+func (p Pass) Duration() time.Duration { return p.Set.T.Sub(p.Rise.T) }
+
+// FindPasses 在 [start, end] 内搜索目标对测站 station 的全部过境
+func FindPasses(station Geodetic, eph Ephemeris, start, end time.Time, opts *PassOptions) ([]Pass, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("passes: end %v not after start %v", end, start)
+	}
+	f := &passFinder{station: station, eph: eph, start: start}
+	if opts != nil {
+		f.opts = *opts
+	}
+	if f.opts.Mask == nil {
+		f.opts.Mask = ConstantMask(0)
+	}
+	if f.opts.Step <= 0 {
+		f.opts.Step = time.Minute
+	}
+	if f.opts.Tolerance <= 0 {
+		f.opts.Tolerance = time.Millisecond
+	}
+	step := f.opts.Step.Seconds()
+	tol := f.opts.Tolerance.Seconds()
+	span := end.Sub(start).Seconds()
+
+	var passes []Pass
+	var cur *Pass
+	prevT := 0.0
+	prevV, err := f.visibility(0)
+	if err != nil {
+		return nil, err
+	}
+	if prevV >= 0 {
+		ev, err := f.event(0)
+		if err != nil {
+			return nil, err
+		}
+		cur = &Pass{Rise: ev, RiseClipped: true}
+	}
+	for t := math.Min(step, span); ; t = math.Min(t+step, span) {
+		v, err := f.visibility(t)
+		if err != nil {
+			return nil, err
+		}
+		if (prevV >= 0) != (v >= 0) {
+			tc, err := f.bisect(prevT, t, tol)
+			if err != nil {
+				return nil, err
+			}
+			ev, err := f.event(tc)
+			if err != nil {
+				return nil, err
+			}
+			if v >= 0 {
+				cur = &Pass{Rise: ev}
+			} else if cur != nil {
+				cur.Set = ev
+				if err := f.culminate(cur, tol); err != nil {
+					return nil, err
+				}
+				passes = append(passes, *cur)
+				cur = nil
+			}
+		}
+		prevT, prevV = t, v
+		if t >= span {
+			break
+		}
+	}
+	if cur != nil {
+		ev, err := f.event(span)
+		if err != nil {
+			return nil, err
+		}
+		cur.Set, cur.SetClipped = ev, true
+		if err := f.culminate(cur, tol); err != nil {
+			return nil, err
+		}
+		passes = append(passes, *cur)
+	}
+	return passes, nil
+}
+
+// passFinder 单次搜索的上下文，时间以相对 start 的秒数表示
+type passFinder struct {
+	station Geodetic
+	eph     Ephemeris
+	start   time.Time
+	opts    PassOptions
+}
+
+func (f *passFinder) at(s float64) time.Time {
+	return f.start.Add(time.Duration(math.Round(s * float64(time.Second))))
+}
+
+func (f *passFinder) aer(s float64) (AER, error) {
+	ecef, err := f.eph(f.at(s))
+	if err != nil {
+		return AER{}, err
+	}
+	if ecef.Ell == nil {
+		ecef.Ell = f.station.Ell
+	}
+	return ecef.ToAER(f.station), nil
+}
+
+// visibility 可见性函数，≥ 0 为可见
+// 取仰角余量 (°) 与距离余量 (km) 中较小者，二者均连续，过零点即进出境时刻
+func (f *passFinder) visibility(s float64) (float64, error) {
+	a, err := f.aer(s)
+	if err != nil {
+		return 0, err
+	}
+	v := a.Elevation - f.opts.Mask.MinElevation(a.Azimuth)
+	if f.opts.MaxRange > 0 {
+		v = math.Min(v, (f.opts.MaxRange-a.SRange)/1000)
+	}
+	return v, nil
+}
+
+func (f *passFinder) event(s float64) (PassEvent, error) {
+	a, err := f.aer(s)
+	return PassEvent{T: f.at(s), AER: a}, err
+}
+
+// bisect 在 [a, b] 内二分求可见性变号点
+func (f *passFinder) bisect(a, b, tol float64) (float64, error) {
+	va, err := f.visibility(a)
+	if err != nil {
+		return 0, err
+	}
+	for b-a > tol {
+		m := (a + b) / 2
+		vm, err := f.visibility(m)
+		if err != nil {
+			return 0, err
+		}
+		if (vm >= 0) == (va >= 0) {
+			a, va = m, vm
+		} else {
+			b = m
+		}
+	}
+	return (a + b) / 2, nil
+}
+
+// culminate 在过境区间内搜索最大仰角
+func (f *passFinder) culminate(p *Pass, tol float64) error {
+	a := p.Rise.T.Sub(f.start).Seconds()
+	b := p.Set.T.Sub(f.start).Seconds()
+	elev := func(s float64) (float64, error) {
+		x, err := f.aer(s)
+		return x.Elevation, err
+	}
+
+	// 以粗步长采样定位最大值附近，再黄金分割求精
+	step := math.Min(f.opts.Step.Seconds(), b-a)
+	best, bestEl := a, math.Inf(-1)
+	for s := a; ; s = math.Min(s+step, b) {
+		e, err := elev(s)
+		if err != nil {
+			return err
+		}
+		if e > bestEl {
+			best, bestEl = s, e
+		}
+		if s >= b {
+			break
+		}
+	}
+	lo, hi := math.Max(a, best-step), math.Min(b, best+step)
+	const g = 0.6180339887498949
+	x1, x2 := hi-g*(hi-lo), lo+g*(hi-lo)
+	e1, err := elev(x1)
+	if err != nil {
+		return err
+	}
+	e2, err := elev(x2)
+	if err != nil {
+		return err
+	}
+	for hi-lo > tol {
+		if e1 < e2 {
+			lo, x1, e1 = x1, x2, e2
+			x2 = lo + g*(hi-lo)
+			if e2, err = elev(x2); err != nil {
+				return err
+			}
+		} else {
+			hi, x2, e2 = x2, x1, e1
+			x1 = hi - g*(hi-lo)
+			if e1, err = elev(x1); err != nil {
+				return err
+			}
+		}
+	}
+	s := (lo + hi) / 2
+	if e, _ := elev(s); e < bestEl {
+		s = best
+	}
+	ev, err := f.event(s)
+	p.Culmination = ev
+	return err
+}
@@ -0,0 +1,159 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// passTestSetup 以 00005 号卫星 SGP4 星历和中纬度测站构造过境搜索场景
+func passTestSetup(t *testing.T) (Geodetic, Ephemeris, time.Time) {
+	t.Helper()
+	tle, err := ParseTLE(
+		"1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753",
+		"2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSGP4(tle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ell, _ := NewEllipsoid("wgs84")
+	station := Geodetic{Latitude: 30, Longitude: 120, Altitude: 50, Ell: ell}
+	return station, SGP4Ephemeris(s, ell), s.Epoch()
+}
+
+// bruteVisible 逐秒扫描统计可见区段，返回各区段起止时刻
+func bruteVisible(t *testing.T, station Geodetic, eph Ephemeris, start, end time.Time, opts PassOptions) [][2]time.Time {
+	t.Helper()
+	var segs [][2]time.Time
+	in := false
+	for tt := start; !tt.After(end); tt = tt.Add(time.Second) {
+		ecef, err := eph(tt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := ecef.ToAER(station)
+		v := a.Elevation >= opts.Mask.MinElevation(a.Azimuth) && (opts.MaxRange == 0 || a.SRange <= opts.MaxRange)
+		if v && !in {
+			segs = append(segs, [2]time.Time{tt, tt})
+		}
+		if v {
+			segs[len(segs)-1][1] = tt
+		}
+		in = v
+	}
+	return segs
+}
+
+func TestFindPasses(t *testing.T) {
+	station, eph, epoch := passTestSetup(t)
+	end := epoch.Add(24 * time.Hour)
+	opts := PassOptions{Mask: ConstantMask(5)}
+	passes, err := FindPasses(station, eph, epoch, end, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	segs := bruteVisible(t, station, eph, epoch, end, opts)
+	if len(passes) == 0 || len(passes) != len(segs) {
+		t.Fatalf("found %d passes, brute force %d", len(passes), len(segs))
+	}
+	for i, p := range passes {
+		// 与逐秒扫描相差不足 1 s
+		if d := p.Rise.T.Sub(segs[i][0]); d > 0 || d < -time.Second {
+			t.Errorf("pass %d: rise %v, brute force %v", i, p.Rise.T, segs[i][0])
+		}
+		if d := p.Set.T.Sub(segs[i][1]); d < 0 || d > time.Second {
+			t.Errorf("pass %d: set %v, brute force %v", i, p.Set.T, segs[i][1])
+		}
+		if !p.RiseClipped && math.Abs(p.Rise.AER.Elevation-5) > 1e-3 {
+			t.Errorf("pass %d: rise elevation %.6f°", i, p.Rise.AER.Elevation)
+		}
+		if !p.SetClipped && math.Abs(p.Set.AER.Elevation-5) > 1e-3 {
+			t.Errorf("pass %d: set elevation %.6f°", i, p.Set.AER.Elevation)
+		}
+		if p.Duration() <= 0 || p.Culmination.T.Before(p.Rise.T) || p.Culmination.T.After(p.Set.T) {
+			t.Errorf("pass %d: inconsistent times %+v", i, p)
+		}
+		// 中天仰角不低于过境内任一时刻
+		for tt := p.Rise.T; tt.Before(p.Set.T); tt = tt.Add(time.Second) {
+			ecef, _ := eph(tt)
+			if e := ecef.ToAER(station).Elevation; e > p.Culmination.AER.Elevation+1e-8 {
+				t.Errorf("pass %d: elevation %.8f° at %v exceeds culmination %.8f°",
+					i, e, tt, p.Culmination.AER.Elevation)
+				break
+			}
+		}
+	}
+
+	// 时间窗起点位于过境中
+	mid := passes[0].Culmination.T
+	clipped, err := FindPasses(station, eph, mid, passes[0].Set.T.Add(time.Hour), &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clipped) == 0 || !clipped[0].RiseClipped || !clipped[0].Rise.T.Equal(mid) ||
+		clipped[0].Set.T.Sub(passes[0].Set.T).Abs() > 10*time.Millisecond {
+		t.Errorf("clipped pass: %+v", clipped)
+	}
+	if _, err := FindPasses(station, eph, end, epoch, nil); err == nil {
+		t.Error("expected error for reversed window")
+	}
+}
+
+func TestFindPassesMaskAndRange(t *testing.T) {
+	station, eph, epoch := passTestSetup(t)
+	end := epoch.Add(24 * time.Hour)
+	mask, err := NewAzimuthMask([]float64{0, 90, 180, 270}, []float64{20, 5, 10, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := PassOptions{Mask: mask, MaxRange: 3000e3, Step: 30 * time.Second}
+	passes, err := FindPasses(station, eph, epoch, end, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	segs := bruteVisible(t, station, eph, epoch, end, opts)
+	if len(passes) == 0 || len(passes) != len(segs) {
+		t.Fatalf("found %d passes, brute force %d", len(passes), len(segs))
+	}
+	ranged := 0
+	for i, p := range passes {
+		for _, ev := range []PassEvent{p.Rise, p.Set} {
+			// 进出境处仰角等于遮蔽角或斜距等于作用距离
+			de := ev.AER.Elevation - mask.MinElevation(ev.AER.Azimuth)
+			dr := opts.MaxRange - ev.AER.SRange
+			if de < -1e-3 || dr < -10 || (de > 1e-3 && dr > 10) {
+				t.Errorf("pass %d at %v: elevation margin %.6f°, range margin %.3f m", i, ev.T, de, dr)
+			}
+			if math.Abs(dr) <= 10 {
+				ranged++
+			}
+		}
+	}
+	if ranged == 0 {
+		t.Error("expected at least one range-limited event")
+	}
+}
+
+func TestAzimuthMask(t *testing.T) {
+	m, err := NewAzimuthMask([]float64{270, 90, 0}, []float64{6, 2, 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct{ az, want float64 }{
+		{0, 10}, {45, 6}, {90, 2}, {180, 4}, {270, 6}, {315, 8}, {-45, 8}, {360, 10},
+	}
+	for _, c := range cases {
+		if got := m.MinElevation(c.az); math.Abs(got-c.want) > 1e-12 {
+			t.Errorf("MinElevation(%g) = %g, want %g", c.az, got, c.want)
+		}
+	}
+	if _, err := NewAzimuthMask([]float64{0, 360}, []float64{1, 2}); err == nil {
+		t.Error("expected error for duplicate azimuth")
+	}
+	if _, err := NewAzimuthMask(nil, nil); err == nil {
+		t.Error("expected error for empty table")
+	}
+}
@@ -0,0 +1,171 @@
+package gomap3d
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================================
+// 椭球面多边形
+//
+// 边为相邻顶点间的大地线，首尾自动闭合。面积按 Karney 方法累加每条边与赤道、
+// 两端子午线围成的面积 S12 (以授权纬度将椭球面积映射到等面积球 R² = c²)，
+// 并按跨越本初子午线的次数修正包含极点的多边形；跨越 ±180° 经线无需特殊处理。
+// 参考: Karney, Algorithms for geodesics, J. Geodesy 87 (2013) 43–55, §6
+//
+// 椭球面上闭合曲线把表面分为两部分，此处约定多边形内部为面积较小的一侧，
+// 与顶点顺序无关；顶点顺序只决定面积符号。
+// ============================================================
+
+// accumulator 双精度补偿求和
+type accumulator struct{ s, t float64 }
+
+func (a *accumulator) add(y float64) {
+	z, u := sumx(y, a.t)
+	a.s, a.t = sumx(z, a.s)
+	if a.s == 0 {
+		a.s = u
+	} else {
+		a.t += u
+	}
+}
+
+// transit 边 lon1 → lon2 向东跨越本初子午线时返回 1，向西返回 -1，否则返回 0
+func transit(lon1, lon2 float64) int {
+	lon1, lon2 = angNormalize(lon1), angNormalize(lon2)
+	lon12, _ := angDiff(lon1, lon2)
+	switch {
+	case lon1 <= 0 && lon2 > 0 && lon12 > 0:
+		return 1
+	case lon2 <= 0 && lon1 > 0 && lon12 < 0:
+		return -1
+	}
+	return 0
+}
+
+// GeodesicPolygon 椭球面大地线多边形
+type GeodesicPolygon struct {
+	g        *Geodesic
+	lat, lon []float64
+
+	area      float64 // 逆时针为正，(-A₀/2, A₀/2]
+	perimeter float64
+	northLeft bool // 北极位于沿顶点顺序前进方向的左侧
+}
+
+// NewGeodesicPolygon 由顶点创建多边形，椭球取 vertices[0].Ell，忽略高度
+// 顶点少于 3 个或未指定椭球时返回错误；首尾顶点无需重复。
+func NewGeodesicPolygon(vertices []Geodetic) (*GeodesicPolygon, error) {
+	if len(vertices) < 3 {
+		return nil, fmt.Errorf("polygon: need at least 3 vertices, got %d", len(vertices))
+	}
+	if vertices[0].Ell == nil {
+		return nil, fmt.Errorf("polygon: ellipsoid not specified")
+	}
+	p := &GeodesicPolygon{g: NewGeodesic(vertices[0].Ell),
+		lat: make([]float64, len(vertices)), lon: make([]float64, len(vertices))}
+	for i, v := range vertices {
+		if math.Abs(v.Latitude) > 90 {
+			return nil, fmt.Errorf("polygon: vertex %d latitude %g out of range", i, v.Latitude)
+		}
+		p.lat[i], p.lon[i] = v.Latitude, angNormalize(v.Longitude)
+	}
+	p.compute()
+	return p, nil
+}
+
+// compute 计算面积、周长与极点相对边界的位置
+func (p *GeodesicPolygon) compute() {
+	n := len(p.lat)
+	var A, P accumulator
+	crossings := 0
+	var planar, lonUnrolled float64
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		r := p.g.genInverse(p.lat[i], p.lon[i], p.lat[j], p.lon[j], true)
+		P.add(r.s12)
+		A.add(r.S12)
+		crossings += transit(p.lon[i], p.lon[j])
+		dlon, _ := angDiff(p.lon[i], p.lon[j])
+		planar -= dlon * (p.lat[i] + p.lat[j]) / 2
+		lonUnrolled += dlon
+	}
+	area0 := 4 * math.Pi * p.g.c2
+	if crossings&1 != 0 {
+		if A.s < 0 {
+			A.add(area0 / 2)
+		} else {
+			A.add(-area0 / 2)
+		}
+	}
+	// 累加结果以顺时针为正，转为逆时针为正并归化到 (-A₀/2, A₀/2]
+	area := -(A.s + A.t)
+	if area > area0/2 {
+		area -= area0
+	} else if area <= -area0/2 {
+		area += area0
+	}
+	p.area = area + 0
+	p.perimeter = P.s + P.t
+
+	// 绕极一周的多边形: 自北极俯视逆时针 (向东) 前进时北极在左侧；
+	// 不绕极时两极同侧，在经纬度平面上为有界区域的外部，有界区域在左侧当且仅当平面面积为正
+	switch k := math.Round(lonUnrolled / 360); {
+	case k > 0:
+		p.northLeft = true
+	case k < 0:
+		p.northLeft = false
+	default:
+		p.northLeft = planar < 0
+	}
+}
+
+// Area 面积 (m²)，顶点逆时针排列时为正，绝对值为多边形 (较小一侧) 的面积
+func (p *GeodesicPolygon) Area() float64 { return p.area }
+
+// Perimeter 周长 (m)
+func (p *GeodesicPolygon) Perimeter() float64 { return p.perimeter }
+
+// Contains 判断点是否位于多边形内部 (较小一侧)
+// 沿 geo 所在经线向北作射线，统计与各边的交点数，结合北极所在一侧判定。
+func (p *GeodesicPolygon) Contains(geo Geodetic) bool {
+	if p.area == 0 {
+		return false
+	}
+	lat, lon := geo.Latitude, angNormalize(geo.Longitude)
+	n := len(p.lat)
+	left := p.northLeft
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		a, _ := angDiff(lon, p.lon[i])
+		b, _ := angDiff(lon, p.lon[j])
+		if (a > 0) == (b > 0) || math.Abs(a-b) >= 180 {
+			continue
+		}
+		if p.crossLat(i, lon) > lat {
+			left = !left
+		}
+	}
+	// 逆时针时左侧为较小一侧
+	return left == (p.area > 0)
+}
+
+// crossLat 第 i 条边与经线 lon 交点的纬度，二分求解沿边距离
+func (p *GeodesicPolygon) crossLat(i int, lon float64) float64 {
+	j := (i + 1) % len(p.lat)
+	l := p.g.InverseLine(p.lat[i], p.lon[i], p.lat[j], p.lon[j])
+	target, _ := angDiff(p.lon[i], lon)
+	east := target > 0
+	lo, hi := 0.0, l.Distance
+	for k := 0; k < 64 && hi-lo > 1e-6; k++ {
+		mid := (lo + hi) / 2
+		_, lm, _ := l.Position(mid)
+		if d, _ := angDiff(p.lon[i], lm); (d < target) == east {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	lat, _, _ := l.Position((lo + hi) / 2)
+	return lat
+}
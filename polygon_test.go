@@ -0,0 +1,102 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+)
+
+func polygonOf(t *testing.T, ell *Ellipsoid, pts [][2]float64) *GeodesicPolygon {
+	t.Helper()
+	v := make([]Geodetic, len(pts))
+	for i, p := range pts {
+		v[i] = Geodetic{Latitude: p[0], Longitude: p[1], Ell: ell}
+	}
+	p, err := NewGeodesicPolygon(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestGeodesicPolygonArea(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	// GeographicLib testgeodesic.c Planimeter 算例
+	cases := []struct {
+		pts             [][2]float64
+		perimeter, area float64
+	}{
+		{[][2]float64{{89, 0}, {89, 90}, {89, 180}, {89, 270}}, 631819.8745, 24952305678.0},      // 包含北极
+		{[][2]float64{{-89, 0}, {-89, 90}, {-89, 180}, {-89, 270}}, 631819.8745, -24952305678.0}, // 包含南极
+		{[][2]float64{{0, -1}, {-1, 0}, {0, 1}, {1, 0}}, 627598.2731, 24619419146.0},
+		{[][2]float64{{90, 0}, {0, 0}, {0, 90}}, 30022685, 63758202715511.0}, // 1/8 椭球面
+	}
+	for _, c := range cases {
+		p := polygonOf(t, wgs, c.pts)
+		if math.Abs(p.Perimeter()-c.perimeter) > 1 || math.Abs(p.Area()-c.area) > 1 {
+			t.Errorf("%v: perimeter %.4f, area %.1f", c.pts, p.Perimeter(), p.Area())
+		}
+	}
+
+	// 1/8 椭球面精确值 πc²/2，c² 为授权半径平方
+	g := NewGeodesic(wgs)
+	p := polygonOf(t, wgs, [][2]float64{{0, 0}, {0, 90}, {90, 0}})
+	if want := math.Pi * g.c2 / 2; math.Abs(p.Area()-want) > 0.1 {
+		t.Errorf("octant %.3f, want %.3f", p.Area(), want)
+	}
+	// 跨 ±180° 经线与不跨时面积相同
+	a := polygonOf(t, wgs, [][2]float64{{-1, 179}, {-1, -179}, {1, -179}, {1, 179}})
+	b := polygonOf(t, wgs, [][2]float64{{-1, -1}, {-1, 1}, {1, 1}, {1, -1}})
+	if math.Abs(a.Area()-b.Area()) > 1e-3 || a.Area() <= 0 {
+		t.Errorf("antimeridian %.4f vs %.4f", a.Area(), b.Area())
+	}
+	// 反序只改变符号
+	r := polygonOf(t, wgs, [][2]float64{{1, -1}, {1, 1}, {-1, 1}, {-1, -1}})
+	if math.Abs(r.Area()+b.Area()) > 1e-3 || math.Abs(r.Perimeter()-b.Perimeter()) > 1e-6 {
+		t.Errorf("reversed %.4f vs %.4f", r.Area(), b.Area())
+	}
+
+	if _, err := NewGeodesicPolygon([]Geodetic{{Ell: wgs}, {Ell: wgs}}); err == nil {
+		t.Error("expected error for 2 vertices")
+	}
+	if _, err := NewGeodesicPolygon([]Geodetic{{}, {}, {}}); err == nil {
+		t.Error("expected error for nil ellipsoid")
+	}
+}
+
+func TestGeodesicPolygonContains(t *testing.T) {
+	cgcs, _ := NewEllipsoid("cgcs2000")
+	cases := []struct {
+		name    string
+		pts     [][2]float64
+		in, out [][2]float64
+	}{
+		{"north cap", [][2]float64{{89, 0}, {89, 90}, {89, 180}, {89, 270}},
+			[][2]float64{{89.5, 45}, {90, 0}, {89.2, -170}}, [][2]float64{{88, 0}, {0, 0}, {-90, 0}}},
+		{"north cap reversed", [][2]float64{{89, 270}, {89, 180}, {89, 90}, {89, 0}},
+			[][2]float64{{89.5, 45}, {90, 0}}, [][2]float64{{88, 0}, {-90, 0}}},
+		{"south cap", [][2]float64{{-80, 0}, {-80, 120}, {-80, 240}},
+			[][2]float64{{-85, 123}, {-90, 0}, {-86, 60}}, [][2]float64{{-75, 0}, {90, 0}, {-79, 60}}},
+		{"antimeridian", [][2]float64{{-1, 179}, {-1, -179}, {1, -179}, {1, 179}},
+			[][2]float64{{0, 180}, {0, -179.5}, {0.5, 179.5}}, [][2]float64{{0, 0}, {0, 178}, {2, 180}}},
+		{"no-fly zone", [][2]float64{{39.8, 116.2}, {39.8, 116.6}, {40.1, 116.6}, {40.1, 116.2}},
+			[][2]float64{{39.9, 116.4}, {40.09, 116.21}}, [][2]float64{{39.9, 116.7}, {40.2, 116.4}, {-39.9, -63.6}}},
+		{"concave", [][2]float64{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {5, 5}},
+			[][2]float64{{2, 5}, {8, 9}, {7, 5}}, [][2]float64{{5, 2}, {7, 2}, {-1, 5}}},
+		// 大地线边向极侧凸出: 60°N 两端点间的边在 45°E 处约达 67.8°N
+		{"geodesic edge", [][2]float64{{60, 0}, {60, 90}, {50, 45}},
+			[][2]float64{{62, 45}, {67.5, 45}}, [][2]float64{{68, 45}, {61, 1}}},
+	}
+	for _, c := range cases {
+		p := polygonOf(t, cgcs, c.pts)
+		for _, q := range c.in {
+			if !p.Contains(Geodetic{Latitude: q[0], Longitude: q[1]}) {
+				t.Errorf("%s: %v should be inside", c.name, q)
+			}
+		}
+		for _, q := range c.out {
+			if p.Contains(Geodetic{Latitude: q[0], Longitude: q[1]}) {
+				t.Errorf("%s: %v should be outside", c.name, q)
+			}
+		}
+	}
+}
@@ -21,31 +21,22 @@ const (
 
 // ---- Frame Bias (IERS Conventions 2010) ----
 // GCRF → J2000.0 mean equator/equinox 的常值偏移
-// 3 个独立旋转角 (mas, 即 milliarcsecond)
+// 3 个独立旋转角 (角秒)
 const (
-	frameBiasDX = -0.0146  // dα0, mas, 转为角秒 = -14.6 mas
-	frameBiasDE = -0.016617 // ξ0,  mas, 转为角秒 = -16.617 mas
-	frameBiasDP = -0.0068192 // η0,  mas, 转为角秒 =  -6.8192 mas
+	frameBiasDX = -0.0146    // dα0 = -14.6 mas
+	frameBiasDE = -0.016617  // ξ0  = -16.617 mas (= δψ_B · sin ε0)
+	frameBiasDP = -0.0068192 // η0  = -6.8192 mas (= δε_B)
 )
 
 // frameBiasMatrix 计算 GCRF→J2000 框架偏差矩阵
-// B = Rz(-dα0) · Ry(η0) · Rx(-ξ0)
+// B = Rx(-η0) · Ry(ξ0) · Rz(dα0)
+// 参考: SOFA iauBi00 / iauBp06
 func frameBiasMatrix() [3][3]float64 {
-	// mas → rad
-	dAlpha := frameBiasDX * 1e-3 * as2r // mas → rad
-	xi := frameBiasDE * 1e-3 * as2r     // mas → rad
-	eta := frameBiasDP * 1e-3 * as2r    // mas → rad
+	dAlpha := frameBiasDX * as2r
+	xi := frameBiasDE * as2r
+	eta := frameBiasDP * as2r
 
-	sda, cda := math.Sin(dAlpha), math.Cos(dAlpha)
-	sxi, cxi := math.Sin(xi), math.Cos(xi)
-	seta, ceta := math.Sin(eta), math.Cos(eta)
-
-	// B = Rz(-dα0) · Ry(η0) · RX(-ξ0)
-	return [3][3]float64{
-		{cda*ceta + sda*sxi*seta, -sda*ceta + cda*sxi*seta, cxi * seta},
-		{sda * cxi, cda * cxi, sxi},
-		{-cda*seta + sda*sxi*ceta, sda*seta + cda*sxi*ceta, cxi * ceta},
-	}
+	return mul33(Rx(-eta), mul33(Ry(xi), R3(dAlpha)))
 }
 
 // ---- IAU 2006 Precession (Classical 3-angle: ζ, z, θ) ----
@@ -101,116 +92,130 @@ func fundArgs(T float64) (l, lp, F, D, Om float64) {
 }
 
 // nut00bTerm 单个章动项的系数
-// 系数来自 SOFA iau_nut00b，存储在内部整数单位 (×1e-8 得到角秒)
+// 系数来自 SOFA iauNut00b，单位 0.1 µas
 type nut00bTerm struct {
 	nl, nlp, nF, nD, nOm int     // 自变量乘数
-	sp, spt             float64 // Δψ 系数
-	cp, cpt             float64 // Δε 系数
+	sp, spt, cp          float64 // Δψ 系数: sin、sin·t、cos
+	ce, cet, se          float64 // Δε 系数: cos、cos·t、sin
 }
 
-func initNut00b() []nut00bTerm {
-	return []nut00bTerm{
-		{0, 0, 0, 0, 1, -172064161, -174666, 92052331, 9086},
-		{0, 0, 2, -2, 2, -13170906, -1675, 5730336, -3015},
-		{0, 0, 2, 0, 2, -2276413, -234, 978459, -485},
-		{0, 0, 0, 0, 2, 2074554, 207, -897492, 470},
-		{0, 1, 0, 0, 0, 1475877, -3633, 73871, -184},
-		{0, 1, 2, -2, 2, -516821, 1226, 224386, -677},
-		{1, 0, 0, 0, 0, 711159, 73, -6750, 0},
-		{0, 0, 2, 0, 1, -387298, -367, 200728, 18},
-		{1, 0, 2, 0, 2, -301461, -36, 129025, -63},
-		{0, -1, 2, -2, 2, 215829, -494, -95929, 299},
-		{0, 0, 2, -2, 1, 128227, 137, -68982, -9},
-		{-1, 0, 2, 0, 2, 123457, 11, -53311, 32},
-		{-1, 0, 0, 2, 0, 156994, 10, -1235, 0},
-		{1, 0, 0, 0, 1, 63110, 63, -33228, 0},
-		{-1, 0, 0, 0, 1, -57976, -63, 31429, 0},
-		{-1, 0, 2, 2, 2, -59641, -11, 25543, -11},
-		{1, 0, 2, 0, 1, -51613, -42, 26366, 0},
-		{-2, 0, 2, 0, 1, 45893, 50, -24236, -10},
-		{0, 0, 0, 2, 0, 63384, 11, -1220, 0},
-		{0, 0, 2, 2, 2, -38571, -1, 16452, -11},
-		{0, -2, 2, -2, 2, 32481, 0, -13870, 0},
-		{-2, 0, 0, 2, 0, -47722, 0, 477, 0},
-		{2, 0, 2, 0, 2, -31046, -1, 13238, -11},
-		{1, 0, 2, -2, 2, 28593, 0, -12338, 10},
-		{-1, 0, 2, 0, 1, 20441, 21, -10758, 0},
-		{2, 0, 0, 0, 0, 29243, 0, -609, 0},
-		{0, 0, 2, 0, 0, 25887, 0, -550, 0},
-		{0, 1, 0, 0, 1, -14053, -25, 7571, -2},
-		{-1, 0, 0, 2, 1, 15164, 10, -8005, 0},
-		{0, 2, 2, -2, 2, -15794, 72, 6850, -42},
-		{0, 0, -2, 2, 0, 21783, 0, 167, 0},
-		{1, 0, 0, -2, 1, -12873, -10, 6953, 0},
-		{0, -1, 0, 0, 1, -12654, 11, 6415, 0},
-		{-1, 0, 2, 2, 1, -10204, 0, 5222, 0},
-		{0, 2, 0, 0, 0, 16707, -85, 168, -1},
-		{1, 0, 2, 2, 2, -7691, 0, 3268, 0},
-		{-2, 0, 2, 0, 0, -11024, 0, 104, 0},
-		{0, 1, 2, 0, 2, 7566, -21, -3250, 0},
-		{0, 0, 2, 2, 1, -6637, -11, 3353, 0},
-		{0, -1, 2, 0, 2, -7141, 21, 3070, 0},
-		{0, 0, 0, 2, 1, -6302, -11, 3272, 0},
-		{1, 0, 2, -2, 1, 5800, 10, -3045, 0},
-		{2, 0, 2, -2, 2, 6443, 0, -2768, 0},
-		{-2, 0, 0, 2, 1, -5774, -11, 3041, 0},
-		{2, 0, 2, 0, 1, -5350, 0, 2695, 0},
-		{0, -1, 2, -2, 1, -4752, -11, 2719, 0},
-		{0, 0, 0, -2, 1, -4940, -11, 2720, 0},
-		{-1, -1, 0, 2, 0, 7350, 0, -51, 0},
-		{2, 0, 0, -2, 1, 4065, 0, -2206, 0},
-		{1, 0, 0, 2, 0, 6579, 0, -199, 0},
-		{0, 1, 2, -2, 1, 3579, 0, -1900, 0},
-		{1, -1, 0, 0, 0, 4725, 0, -41, 0},
-		{-2, 0, 2, 0, 2, -3075, 0, 1313, 0},
-		{3, 0, 2, 0, 2, -2904, 0, 1233, 0},
-		{0, -1, 0, 2, 0, 4348, 0, -81, 0},
-		{1, -1, 2, 0, 2, -2878, 0, 1232, 0},
-		{0, 0, 0, 1, 0, -4230, 0, -20, 0},
-		{-1, -1, 2, 2, 2, -2819, 0, 1207, 0},
-		{-1, 0, 2, 0, 0, -4056, 0, 40, 0},
-		{0, -1, 2, 2, 2, -2647, 0, 1129, 0},
-		{-2, 0, 0, 0, 1, -2294, 0, 1266, 0},
-		{1, 1, 2, 0, 2, 2481, 0, -1062, 0},
-		{2, 0, 0, 0, 1, 2179, 0, -1129, 0},
-		{-1, 1, 0, 1, 0, 3276, 0, -9, 0},
-		{1, 1, 0, 0, 0, -3389, 0, 35, 0},
-		{1, 0, 2, 0, 0, 3339, 0, -107, 0},
-		{-1, 0, 2, -2, 1, -1987, 0, 1073, 0},
-		{1, 0, 0, 0, 2, -1981, 0, 854, 0},
-		{-1, 0, 0, 1, 0, 4026, 0, -55, 0},
-		{0, 0, 2, 1, 2, 1660, 0, -710, 0},
-		{-1, 0, 2, 4, 2, -1521, 0, 647, 0},
-		{-1, 1, 0, 1, 1, 1314, 0, -700, 0},
-		{0, -2, 2, -2, 1, -1283, 0, 672, 0},
-		{1, 0, 2, 2, 1, -1331, 0, 663, 0},
-		{-2, 0, 2, 2, 2, 1383, 0, -594, 0},
-		{-1, 0, 0, 0, 2, 1405, 0, -610, 0},
-		{1, 1, 2, -2, 2, 1290, 0, -556, 0},
-	}
+// nut00bTerms IAU 2000B 章动项 (77 项日月项)
+var nut00bTerms = []nut00bTerm{
+	{0, 0, 0, 0, 1, -172064161, -174666, 33386, 92052331, 9086, 15377},
+	{0, 0, 2, -2, 2, -13170906, -1675, -13696, 5730336, -3015, -4587},
+	{0, 0, 2, 0, 2, -2276413, -234, 2796, 978459, -485, 1374},
+	{0, 0, 0, 0, 2, 2074554, 207, -698, -897492, 470, -291},
+	{0, 1, 0, 0, 0, 1475877, -3633, 11817, 73871, -184, -1924},
+	{0, 1, 2, -2, 2, -516821, 1226, -524, 224386, -677, -174},
+	{1, 0, 0, 0, 0, 711159, 73, -872, -6750, 0, 358},
+	{0, 0, 2, 0, 1, -387298, -367, 380, 200728, 18, 318},
+	{1, 0, 2, 0, 2, -301461, -36, 816, 129025, -63, 367},
+	{0, -1, 2, -2, 2, 215829, -494, 111, -95929, 299, 132},
+	{0, 0, 2, -2, 1, 128227, 137, 181, -68982, -9, 39},
+	{-1, 0, 2, 0, 2, 123457, 11, 19, -53311, 32, -4},
+	{-1, 0, 0, 2, 0, 156994, 10, -168, -1235, 0, 82},
+	{1, 0, 0, 0, 1, 63110, 63, 27, -33228, 0, -9},
+	{-1, 0, 0, 0, 1, -57976, -63, -189, 31429, 0, -75},
+	{-1, 0, 2, 2, 2, -59641, -11, 149, 25543, -11, 66},
+	{1, 0, 2, 0, 1, -51613, -42, 129, 26366, 0, 78},
+	{-2, 0, 2, 0, 1, 45893, 50, 31, -24236, -10, 20},
+	{0, 0, 0, 2, 0, 63384, 11, -150, -1220, 0, 29},
+	{0, 0, 2, 2, 2, -38571, -1, 158, 16452, -11, 68},
+	{0, -2, 2, -2, 2, 32481, 0, 0, -13870, 0, 0},
+	{-2, 0, 0, 2, 0, -47722, 0, -18, 477, 0, -25},
+	{2, 0, 2, 0, 2, -31046, -1, 131, 13238, -11, 59},
+	{1, 0, 2, -2, 2, 28593, 0, -1, -12338, 10, -3},
+	{-1, 0, 2, 0, 1, 20441, 21, 10, -10758, 0, -3},
+	{2, 0, 0, 0, 0, 29243, 0, -74, -609, 0, 13},
+	{0, 0, 2, 0, 0, 25887, 0, -66, -550, 0, 11},
+	{0, 1, 0, 0, 1, -14053, -25, 79, 8551, -2, -45},
+	{-1, 0, 0, 2, 1, 15164, 10, 11, -8001, 0, -1},
+	{0, 2, 2, -2, 2, -15794, 72, -16, 6850, -42, -5},
+	{0, 0, -2, 2, 0, 21783, 0, 13, -167, 0, 13},
+	{1, 0, 0, -2, 1, -12873, -10, -37, 6953, 0, -14},
+	{0, -1, 0, 0, 1, -12654, 11, 63, 6415, 0, 26},
+	{-1, 0, 2, 2, 1, -10204, 0, 25, 5222, 0, 15},
+	{0, 2, 0, 0, 0, 16707, -85, -10, 168, -1, 10},
+	{1, 0, 2, 2, 2, -7691, 0, 44, 3268, 0, 19},
+	{-2, 0, 2, 0, 0, -11024, 0, -14, 104, 0, 2},
+	{0, 1, 2, 0, 2, 7566, -21, -11, -3250, 0, -5},
+	{0, 0, 2, 2, 1, -6637, -11, 25, 3353, 0, 14},
+	{0, -1, 2, 0, 2, -7141, 21, 8, 3070, 0, 4},
+	{0, 0, 0, 2, 1, -6302, -11, 2, 3272, 0, 4},
+	{1, 0, 2, -2, 1, 5800, 10, 2, -3045, 0, -1},
+	{2, 0, 2, -2, 2, 6443, 0, -7, -2768, 0, -4},
+	{-2, 0, 0, 2, 1, -5774, -11, -15, 3041, 0, -5},
+	{2, 0, 2, 0, 1, -5350, 0, 21, 2695, 0, 12},
+	{0, -1, 2, -2, 1, -4752, -11, -3, 2719, 0, -3},
+	{0, 0, 0, -2, 1, -4940, -11, -21, 2720, 0, -9},
+	{-1, -1, 0, 2, 0, 7350, 0, -8, -51, 0, 4},
+	{2, 0, 0, -2, 1, 4065, 0, 6, -2206, 0, 1},
+	{1, 0, 0, 2, 0, 6579, 0, -24, -199, 0, 2},
+	{0, 1, 2, -2, 1, 3579, 0, 5, -1900, 0, 1},
+	{1, -1, 0, 0, 0, 4725, 0, -6, -41, 0, 3},
+	{-2, 0, 2, 0, 2, -3075, 0, -2, 1313, 0, -1},
+	{3, 0, 2, 0, 2, -2904, 0, 15, 1233, 0, 7},
+	{0, -1, 0, 2, 0, 4348, 0, -10, -81, 0, 2},
+	{1, -1, 2, 0, 2, -2878, 0, 8, 1232, 0, 4},
+	{0, 0, 0, 1, 0, -4230, 0, 5, -20, 0, -2},
+	{-1, -1, 2, 2, 2, -2819, 0, 7, 1207, 0, 3},
+	{-1, 0, 2, 0, 0, -4056, 0, 5, 40, 0, -2},
+	{0, -1, 2, 2, 2, -2647, 0, 11, 1129, 0, 5},
+	{-2, 0, 0, 0, 1, -2294, 0, -10, 1266, 0, -4},
+	{1, 1, 2, 0, 2, 2481, 0, -7, -1062, 0, -3},
+	{2, 0, 0, 0, 1, 2179, 0, -2, -1129, 0, -2},
+	{-1, 1, 0, 1, 0, 3276, 0, 1, -9, 0, 0},
+	{1, 1, 0, 0, 0, -3389, 0, 5, 35, 0, -2},
+	{1, 0, 2, 0, 0, 3339, 0, -13, -107, 0, 1},
+	{-1, 0, 2, -2, 1, -1987, 0, -6, 1073, 0, -2},
+	{1, 0, 0, 0, 2, -1981, 0, 0, 854, 0, 0},
+	{-1, 0, 0, 1, 0, 4026, 0, -353, -553, 0, -139},
+	{0, 0, 2, 1, 2, 1660, 0, -5, -710, 0, -2},
+	{-1, 0, 2, 4, 2, -1521, 0, 9, 647, 0, 4},
+	{-1, 1, 0, 1, 1, 1314, 0, 0, -700, 0, 0},
+	{0, -2, 2, -2, 1, -1283, 0, 0, 672, 0, 0},
+	{1, 0, 2, 2, 1, -1331, 0, 8, 663, 0, 4},
+	{-2, 0, 2, 2, 2, 1383, 0, -2, -594, 0, -2},
+	{-1, 0, 0, 0, 2, 1405, 0, 4, -610, 0, 2},
+	{1, 1, 2, -2, 2, 1290, 0, 0, -556, 0, 0},
 }
 
-// nutation 计算章动 (Δψ, Δε)，单位 rad
+// nutation 计算章动 (Δψ, Δε)，单位 rad，T 为 TT 儒略世纪数
+// 默认转换器已通过 SetNutation2000A 载入 IAU 2000A 序列时使用 IAU 2006/2000A，
+// 否则使用内置 IAU 2000B。
 func nutation(T float64) (dpsi, deps float64) {
-	l, lp, F, D, Om := fundArgs(T)
-	terms := initNut00b()
+	return DefaultTransformer().nutation(T)
+}
+
+// nutation00b IAU 2000B 章动 (Δψ, Δε)，单位 rad
+// 参考: SOFA iauNut00b，精度约 1 mas
+func nutation00b(T float64) (dpsi, deps float64) {
+	// 简化的线性基本幅角 (角秒)
+	const turnas = 1296000.0
+	l := math.Mod(485868.249036+1717915923.2178*T, turnas) * as2r
+	lp := math.Mod(1287104.79305+129596581.0481*T, turnas) * as2r
+	F := math.Mod(335779.526232+1739527262.8478*T, turnas) * as2r
+	D := math.Mod(1072260.70369+1602961601.2090*T, turnas) * as2r
+	Om := math.Mod(450160.398036-6962890.5431*T, turnas) * as2r
 
 	dpsiSum := 0.0
 	depsSum := 0.0
 
-	for _, t := range terms {
-		arg := float64(t.nl)*l + float64(t.nlp)*lp + float64(t.nF)*F +
-			float64(t.nD)*D + float64(t.nOm)*Om
+	// 从小项到大项累加以减小舍入误差
+	for i := len(nut00bTerms) - 1; i >= 0; i-- {
+		t := nut00bTerms[i]
+		arg := math.Mod(float64(t.nl)*l+float64(t.nlp)*lp+float64(t.nF)*F+
+			float64(t.nD)*D+float64(t.nOm)*Om, tau)
+		sarg, carg := math.Sincos(arg)
 
-		// Δψ: (sp + spt × T) × sin(arg) × 0.1 mas
-		dpsiSum += (t.sp + t.spt*T) * math.Sin(arg)
-		// Δε: (cp + cpt × T) × cos(arg) × 0.1 mas
-		depsSum += (t.cp + t.cpt*T) * math.Cos(arg)
+		dpsiSum += (t.sp+t.spt*T)*sarg + t.cp*carg
+		depsSum += (t.ce+t.cet*T)*carg + t.se*sarg
 	}
 
-	// 系数存储在 0.1 µas × 1000 单位 → 乘以 1e-8 得到角秒
-	dpsi = dpsiSum * 1e-8 * as2r
-	deps = depsSum * 1e-8 * as2r
+	// 0.1 µas → rad，并加上行星章动的常值偏移
+	const u2r = as2r / 1e7
+	dpsi = dpsiSum*u2r - 0.135e-3*as2r
+	deps = depsSum*u2r + 0.388e-3*as2r
 	return
 }
 
@@ -230,7 +235,11 @@ func meanObliquity(T float64) float64 {
 // N = Rx(-ε) · Rz(-Δψ) · Rx(ε_mean)
 func nutationMatrix(T float64) [3][3]float64 {
 	dpsi, deps := nutation(T)
-	epsMean := meanObliquity(T)
+	return nutationMatrixFrom(dpsi, deps, meanObliquity(T))
+}
+
+// nutationMatrixFrom 由给定的 Δψ、Δε 和平黄赤交角构造章动矩阵
+func nutationMatrixFrom(dpsi, deps, epsMean float64) [3][3]float64 {
 	epsTrue := epsMean + deps
 
 	sep, cep := math.Sin(epsTrue), math.Cos(epsTrue)
@@ -247,14 +256,31 @@ func nutationMatrix(T float64) [3][3]float64 {
 
 // ---- GAST (格林威治视恒星时) ----
 // GAST = GMST + 赤经章动 (equation of equinoxes)
-// equation of equinoxes = Δψ · cos(ε_true)
-func GAST(jd float64) float64 {
-	T := (jd - 2451545.0) / 36525.0
-	gmst := greenwichsrt(jd)
-	dpsi, deps := nutation(T)
-	epsTrue := meanObliquity(T) + deps
-	eeq := dpsi * math.Cos(epsTrue) // 赤经章动 (分点差)
-	return math.Mod(gmst+eeq, tau)
+// equation of equinoxes = Δψ · cos(ε_A) + 补充项
+// jdUT1 用于恒星时，jdTT 用于章动与黄赤交角
+func GAST(jdUT1, jdTT float64) float64 {
+	T := (jdTT - 2451545.0) / 36525.0
+	dpsi, _ := nutation(T)
+	return gastFrom(jdUT1, T, dpsi)
+}
+
+// GMST06 IAU 2006 格林威治平恒星时 (rad)，与 IAU 2006 岁差自洽
+// GMST = ERA + 多项式(T)，参考: SOFA iauGmst06
+func GMST06(jdUT1, jdTT float64) float64 {
+	T := (jdTT - 2451545.0) / 36525.0
+	poly := (0.014506 + (4612.156534+(1.3915817+(-0.00000044+(-0.000029956+(-0.0000000368)*T)*T)*T)*T)*T) * as2r
+	return math.Mod(EarthRotationAngle(jdUT1)+poly, tau)
+}
+
+// gastFrom 由章动 Δψ 计算 GAST (rad)
+func gastFrom(jdUT1, T, dpsi float64) float64 {
+	jdTT := 2451545.0 + T*36525.0
+	eeq := dpsi*math.Cos(meanObliquity(T)) + eqeqComplementary(T) // 分点差
+	gast := math.Mod(GMST06(jdUT1, jdTT)+eeq, tau)
+	if gast < 0 {
+		gast += tau
+	}
+	return gast
 }
 
 // ---- 完整变换链 ----
@@ -263,16 +289,64 @@ func GAST(jd float64) float64 {
 
 // GCRF2ITRF 计算完整 GCRF → ITRF 旋转矩阵（不含极移）
 // M = Rz(GAST) · N · P · B
-func GCRF2ITRF(jd float64) [3][3]float64 {
-	T := (jd - 2451545.0) / 36525.0
+// 岁差章动以 TT 儒略日 jdTT 为自变量，恒星时以 UT1 儒略日 jdUT1 为自变量
+func GCRF2ITRF(jdUT1, jdTT float64) [3][3]float64 {
+	T := (jdTT - 2451545.0) / 36525.0
 	B := frameBiasMatrix()
 	P := precessionMatrix(T)
 	N := nutationMatrix(T)
-	gast := GAST(jd)
+	gast := GAST(jdUT1, jdTT)
 
 	return mul33(R3(gast), mul33(N, mul33(P, B)))
 }
 
+// ---- EOP 修正 ----
+
+// polarMotionMatrix 极移矩阵 W (TIRS → ITRS)，xp、yp 单位 rad
+// W = Rx(-yp) · Ry(-xp) · Rz(s')，s' = -47 µas · T (TIO locator)
+// 参考: SOFA iauPom00
+func polarMotionMatrix(xp, yp, T float64) [3][3]float64 {
+	sp := -47e-6 * as2r * T
+	return mul33(Rx(-yp), mul33(Ry(-xp), R3(sp)))
+}
+
+// cipOffsetNutation 将天极偏差 dX/dY 换算为章动修正 (δΔψ, δΔε)，单位 rad
+// 一阶近似: δΔψ = dX / sin(ε_A), δΔε = dY
+func cipOffsetNutation(T float64, eop EOPRecord) (ddpsi, ddeps float64) {
+	ddpsi = eop.DX * as2r / math.Sin(meanObliquity(T))
+	ddeps = eop.DY * as2r
+	return
+}
+
+// GCRF2ITRFEOP 计算含 EOP 修正的 GCRF → ITRF 旋转矩阵
+// M = W · Rz(GAST) · N · P · B
+//   - 章动叠加天极偏差 dX/dY
+//   - W 为极移矩阵 (xp, yp)
+//
+// jdUT1 应已包含 eop.UT1UTC；eop 为零值时与 GCRF2ITRF 相同。
+func GCRF2ITRFEOP(jdUT1, jdTT float64, eop EOPRecord) [3][3]float64 {
+	dpsi, deps := nutation((jdTT - 2451545.0) / 36525.0)
+	return gcrf2itrfEquinox(jdUT1, jdTT, eop, dpsi, deps)
+}
+
+// gcrf2itrfEquinox 以给定章动 (Δψ, Δε) 计算分点链 GCRF → ITRF 矩阵
+func gcrf2itrfEquinox(jdUT1, jdTT float64, eop EOPRecord, dpsi, deps float64) [3][3]float64 {
+	T := (jdTT - 2451545.0) / 36525.0
+
+	ddpsi, ddeps := cipOffsetNutation(T, eop)
+	dpsi += ddpsi
+	deps += ddeps
+	epsMean := meanObliquity(T)
+
+	B := frameBiasMatrix()
+	P := precessionMatrix(T)
+	N := nutationMatrixFrom(dpsi, deps, epsMean)
+	gast := gastFrom(jdUT1, T, dpsi)
+	W := polarMotionMatrix(eop.Xp*as2r, eop.Yp*as2r, T)
+
+	return mul33(W, mul33(R3(gast), mul33(N, mul33(P, B))))
+}
+
 // ---- 辅助矩阵运算 ----
 
 // Rx 绕 X 轴旋转 angle 弧度的旋转矩阵
@@ -81,7 +81,11 @@ func TestOldVsNewDelta(t *testing.T) {
 	rEcef := [3]float64{-2.174e6, 4.389e6, 4.077e6} // ~500km 高度
 
 	// 新：完整链
-	xiNew, yiNew, ziNew := ECEF2ECI(rEcef[0], rEcef[1], rEcef[2], tUTC)
+	tr, err := NewTransformer(Options{Model: ModelIAU2006B})
+	if err != nil {
+		t.Fatal(err)
+	}
+	xiNew, yiNew, ziNew := tr.ECEF2ECI(rEcef[0], rEcef[1], rEcef[2], tUTC)
 	// 旧：纯GMST
 	xiOld, yiOld, ziOld := ecef2eciOld(rEcef[0], rEcef[1], rEcef[2], tUTC)
 
@@ -150,15 +154,15 @@ func TestGASTvsGMST(t *testing.T) {
 	// GAST = GMST + equation of equinoxes
 	jd2024 := juliandate(time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC))
 	gmst := greenwichsrt(jd2024)
-	gast := GAST(jd2024)
+	gast := GAST(jd2024, jd2024)
 
 	// 赤经章动 = GAST - GMST (rad) → arcsec
 	eeqArcsec := math.Abs(gast-gmst) * 180.0 * 3600.0 / math.Pi
 	t.Logf("GMST = %.4f°, GAST = %.4f°, eq of equinoxes = %.6f arcsec",
 		gmst*180/math.Pi, gast*180/math.Pi, eeqArcsec)
 
-	// 赤经章动 < 2 arcsec
-	if eeqArcsec > 2 {
+	// 赤经章动 = Δψ·cos ε，|Δψ| ≤ 约 19"，故 < 18 arcsec
+	if eeqArcsec > 18 {
 		t.Errorf("equation of equinoxes too large: %.3f arcsec", eeqArcsec)
 	}
 }
@@ -166,7 +170,7 @@ func TestGASTvsGMST(t *testing.T) {
 func TestConsistencyGCRF2ITRF(t *testing.T) {
 	// GCRF2ITRF 矩阵应为正交矩阵
 	jd2024 := juliandate(time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC))
-	M := GCRF2ITRF(jd2024)
+	M := GCRF2ITRF(jd2024, jd2024)
 
 	// M^T · M 应 ≈ I
 	Mt := transpose(M)
@@ -224,12 +228,12 @@ func TestMatrixChain(t *testing.T) {
 	B := frameBiasMatrix()
 	P := precessionMatrix(T)
 	N := nutationMatrix(T)
-	gast := GAST(jd2024)
+	gast := GAST(jd2024, jd2024)
 	R := R3(gast)
 
 	// 组合矩阵
 	M := mul33(R, mul33(N, mul33(P, B)))
-	Mref := GCRF2ITRF(jd2024)
+	Mref := GCRF2ITRF(jd2024, jd2024)
 
 	// 应与 GCRF2ITRF 结果一致
 	for i := 0; i < 3; i++ {
@@ -0,0 +1,243 @@
+package gomap3d
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================================
+// 地图投影
+//
+// Projection 接口统一平面投影正反算，x 为东向、y 为北向 (m):
+//
+//   TransverseMercator     横轴墨卡托 / 高斯-克吕格 (tmerc.go)
+//   WebMercator            Web 墨卡托 (EPSG:3857)，球面公式作用于 WGS84 经纬度
+//   LambertConformalConic  椭球兰勃特等角圆锥，单标准纬线 (EPSG 9801) 与双标准纬线 (EPSG 9802)
+//   PolarStereographic     椭球极球面，变体 A (EPSG 9810) 与变体 B (EPSG 9829)
+//   AzimuthalEquidistant   以测站为中心的椭球方位等距投影，距离与方位沿大地线量测
+//
+// 公式参考: IOGP Guidance Note 7-2, Coordinate Conversions and Transformations including Formulas
+// ============================================================
+
+// Projection 地图投影
+type Projection interface {
+	// Forward 正算，忽略高度，经纬度视为投影所用椭球上的坐标
+	Forward(geo Geodetic) (x, y float64, err error)
+	// Inverse 反算，返回高度为 0、椭球为投影椭球的大地坐标
+	Inverse(x, y float64) (Geodetic, error)
+}
+
+// conformalT EPSG 公式中的 t = tan(π/4 - φ/2) / [(1 - e sinφ)/(1 + e sinφ)]^(e/2)，φ 为弧度
+func conformalT(phi, e float64) float64 {
+	es := e * math.Sin(phi)
+	return math.Tan(math.Pi/4-phi/2) / math.Pow((1-es)/(1+es), e/2)
+}
+
+// conformalTInverse 由 t 迭代反解纬度 φ (弧度)
+func conformalTInverse(t, e float64) float64 {
+	phi := math.Pi/2 - 2*math.Atan(t)
+	for i := 0; i < 15; i++ {
+		es := e * math.Sin(phi)
+		next := math.Pi/2 - 2*math.Atan(t*math.Pow((1-es)/(1+es), e/2))
+		if math.Abs(next-phi) < 1e-15 {
+			return next
+		}
+		phi = next
+	}
+	return phi
+}
+
+// ---------------- Web 墨卡托 ----------------
+
+// webMercatorRadius Web 墨卡托球半径，取 WGS84 长半轴 (m)
+const webMercatorRadius = 6378137.0
+
+// WebMercatorMaxLatitude 正方形世界地图的纬度界限 (°)，此处 y = ±πR
+const WebMercatorMaxLatitude = 85.051128779806592
+
+// WebMercator Web 墨卡托投影 (EPSG:3857)
+// 球面墨卡托公式直接作用于 WGS84 经纬度，因而并不严格等角。
+type WebMercator struct{}
+
+// Forward 实现 Projection，经度归化到 [-180°, 180°]
+func (WebMercator) Forward(geo Geodetic) (x, y float64, err error) {
+	if math.Abs(geo.Latitude) >= 90 {
+		return 0, 0, fmt.Errorf("webmercator: latitude %g out of range", geo.Latitude)
+	}
+	x = webMercatorRadius * math.Remainder(geo.Longitude, 360) * math.Pi / 180
+	y = webMercatorRadius * math.Asinh(math.Tan(geo.Latitude*math.Pi/180))
+	return x, y, nil
+}
+
+// Inverse 实现 Projection
+func (WebMercator) Inverse(x, y float64) (Geodetic, error) {
+	lat := math.Atan(math.Sinh(y/webMercatorRadius)) * 180 / math.Pi
+	lon := x / webMercatorRadius * 180 / math.Pi
+	return Geodetic{Latitude: lat, Longitude: lon, Ell: wgs84Ellipsoid}, nil
+}
+
+// ---------------- 兰勃特等角圆锥 ----------------
+
+// LambertConformalConic 椭球兰勃特等角圆锥投影，由 NewLambertConformalConic1SP/2SP 创建
+type LambertConformalConic struct {
+	Ell           *Ellipsoid
+	LatOrigin     float64 // 原点纬度 (°)，单标准纬线时即标准纬线
+	LonOrigin     float64 // 中央经线 (°)
+	StdParallel1  float64 // 标准纬线 (°)
+	StdParallel2  float64
+	Scale         float64 // 标准纬线比例因子 (双标准纬线时为 1)
+	FalseEasting  float64 // 东伪偏移 (m)
+	FalseNorthing float64 // 北伪偏移 (m)
+
+	n    float64 // 锥常数
+	af   float64 // a·F·k₀
+	rho0 float64 // 原点处投影半径
+}
+
+// NewLambertConformalConic1SP 单标准纬线兰勃特投影 (EPSG 9801)，lat0 为标准纬线兼原点纬度
+func NewLambertConformalConic1SP(ell *Ellipsoid, lat0, lon0, k0, fe, fn float64) (*LambertConformalConic, error) {
+	if lat0 == 0 || math.Abs(lat0) >= 90 {
+		return nil, fmt.Errorf("lcc: invalid latitude of origin %g", lat0)
+	}
+	p := &LambertConformalConic{Ell: ell, LatOrigin: lat0, LonOrigin: lon0,
+		StdParallel1: lat0, StdParallel2: lat0, Scale: k0, FalseEasting: fe, FalseNorthing: fn}
+	phi0 := lat0 * math.Pi / 180
+	p.n = math.Sin(phi0)
+	p.init(phi0)
+	return p, nil
+}
+
+// NewLambertConformalConic2SP 双标准纬线兰勃特投影 (EPSG 9802)
+// latF、lonF 为伪原点经纬度，lat1、lat2 为两条标准纬线 (°)，fe、fn 为伪原点处的坐标。
+func NewLambertConformalConic2SP(ell *Ellipsoid, latF, lonF, lat1, lat2, fe, fn float64) (*LambertConformalConic, error) {
+	if math.Abs(lat1) >= 90 || math.Abs(lat2) >= 90 || lat1 == -lat2 {
+		return nil, fmt.Errorf("lcc: invalid standard parallels %g, %g", lat1, lat2)
+	}
+	p := &LambertConformalConic{Ell: ell, LatOrigin: latF, LonOrigin: lonF,
+		StdParallel1: lat1, StdParallel2: lat2, Scale: 1, FalseEasting: fe, FalseNorthing: fn}
+	e := ell.Eccentricity
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	if lat1 == lat2 {
+		p.n = math.Sin(phi1)
+	} else {
+		p.n = (math.Log(lccM(phi1, e)) - math.Log(lccM(phi2, e))) /
+			(math.Log(conformalT(phi1, e)) - math.Log(conformalT(phi2, e)))
+	}
+	p.init(phi1)
+	return p, nil
+}
+
+// lccM m = cosφ / √(1 - e²sin²φ)
+func lccM(phi, e float64) float64 {
+	s := e * math.Sin(phi)
+	return math.Cos(phi) / math.Sqrt(1-s*s)
+}
+
+// init 由锥常数与一条标准纬线 phi1 (弧度) 计算 a·F·k₀ 与原点半径
+func (p *LambertConformalConic) init(phi1 float64) {
+	e := p.Ell.Eccentricity
+	f := lccM(phi1, e) / (p.n * math.Pow(conformalT(phi1, e), p.n))
+	p.af = p.Ell.SemimajorAxis * f * p.Scale
+	p.rho0 = p.af * math.Pow(conformalT(p.LatOrigin*math.Pi/180, e), p.n)
+}
+
+// Forward 实现 Projection
+func (p *LambertConformalConic) Forward(geo Geodetic) (x, y float64, err error) {
+	if math.Abs(geo.Latitude) > 90 || geo.Latitude == math.Copysign(90, -p.n) {
+		return 0, 0, fmt.Errorf("lcc: latitude %g cannot be projected", geo.Latitude)
+	}
+	r := p.af * math.Pow(conformalT(geo.Latitude*math.Pi/180, p.Ell.Eccentricity), p.n)
+	theta := p.n * math.Remainder(geo.Longitude-p.LonOrigin, 360) * math.Pi / 180
+	st, ct := math.Sincos(theta)
+	return p.FalseEasting + r*st, p.FalseNorthing + p.rho0 - r*ct, nil
+}
+
+// Inverse 实现 Projection
+func (p *LambertConformalConic) Inverse(x, y float64) (Geodetic, error) {
+	dx := x - p.FalseEasting
+	dy := p.rho0 - (y - p.FalseNorthing)
+	sgn := math.Copysign(1, p.n)
+	r := sgn * math.Hypot(dx, dy)
+	t := math.Pow(r/p.af, 1/p.n)
+	theta := math.Atan2(sgn*dx, sgn*dy)
+	lat := conformalTInverse(t, p.Ell.Eccentricity) * 180 / math.Pi
+	lon := math.Remainder(theta/p.n*180/math.Pi+p.LonOrigin, 360)
+	return Geodetic{Latitude: lat, Longitude: lon, Ell: p.Ell}, nil
+}
+
+// ---------------- 极球面 ----------------
+
+// PolarStereographic 椭球极球面投影
+type PolarStereographic struct {
+	Ell           *Ellipsoid
+	North         bool    // 投影中心为北极
+	LonOrigin     float64 // 中央经线 (°)，北极时指向 -y，南极时指向 +y
+	Scale         float64 // 极点比例因子 k₀
+	FalseEasting  float64 // 东伪偏移 (m)
+	FalseNorthing float64 // 北伪偏移 (m)
+}
+
+// NewPolarStereographicA 变体 A (EPSG 9810)，以极点比例因子 k0 定义
+func NewPolarStereographicA(ell *Ellipsoid, north bool, lon0, k0, fe, fn float64) *PolarStereographic {
+	return &PolarStereographic{Ell: ell, North: north, LonOrigin: lon0, Scale: k0,
+		FalseEasting: fe, FalseNorthing: fn}
+}
+
+// NewPolarStereographicB 变体 B (EPSG 9829)，以标准纬线 latC (°) 定义，其符号决定投影极点
+func NewPolarStereographicB(ell *Ellipsoid, latC, lon0, fe, fn float64) (*PolarStereographic, error) {
+	if latC == 0 || math.Abs(latC) > 90 {
+		return nil, fmt.Errorf("polar stereographic: invalid standard parallel %g", latC)
+	}
+	k0 := 1.0
+	if math.Abs(latC) < 90 {
+		e := ell.Eccentricity
+		phi := math.Abs(latC) * math.Pi / 180
+		k0 = lccM(phi, e) * polarStereoC(e) / (2 * conformalT(phi, e))
+	}
+	return NewPolarStereographicA(ell, latC > 0, lon0, k0, fe, fn), nil
+}
+
+// Forward 实现 Projection
+func (p *PolarStereographic) Forward(geo Geodetic) (x, y float64, err error) {
+	if (p.North && geo.Latitude <= -90) || (!p.North && geo.Latitude >= 90) {
+		return 0, 0, fmt.Errorf("polar stereographic: latitude %g is the opposite pole", geo.Latitude)
+	}
+	x, y = polarStereoForward(geo.Latitude, geo.Longitude-p.LonOrigin, p.North, p.Scale, p.Ell)
+	return p.FalseEasting + x, p.FalseNorthing + y, nil
+}
+
+// Inverse 实现 Projection
+func (p *PolarStereographic) Inverse(x, y float64) (Geodetic, error) {
+	lat, lon := polarStereoInverse(x-p.FalseEasting, y-p.FalseNorthing, p.North, p.Scale, p.Ell)
+	return Geodetic{Latitude: lat, Longitude: math.Remainder(lon+p.LonOrigin, 360), Ell: p.Ell}, nil
+}
+
+// ---------------- 方位等距 ----------------
+
+// AzimuthalEquidistant 以测站为中心的椭球方位等距投影
+// 投影点到中心的平面距离与方位等于两点间大地线长度与起始方位，适用于雷达距离圈绘制。
+type AzimuthalEquidistant struct {
+	Center        Geodetic
+	FalseEasting  float64 // 东伪偏移 (m)
+	FalseNorthing float64 // 北伪偏移 (m)
+}
+
+// NewAzimuthalEquidistant 创建以 center 为中心的方位等距投影，椭球取 center.Ell
+func NewAzimuthalEquidistant(center Geodetic, fe, fn float64) *AzimuthalEquidistant {
+	return &AzimuthalEquidistant{Center: center, FalseEasting: fe, FalseNorthing: fn}
+}
+
+// Forward 实现 Projection
+func (p *AzimuthalEquidistant) Forward(geo Geodetic) (x, y float64, err error) {
+	s, azi, _ := NewGeodesic(p.Center.Ell).Inverse(p.Center.Latitude, p.Center.Longitude, geo.Latitude, geo.Longitude)
+	sa, ca := math.Sincos(azi * math.Pi / 180)
+	return p.FalseEasting + s*sa, p.FalseNorthing + s*ca, nil
+}
+
+// Inverse 实现 Projection
+func (p *AzimuthalEquidistant) Inverse(x, y float64) (Geodetic, error) {
+	dx, dy := x-p.FalseEasting, y-p.FalseNorthing
+	azi := math.Atan2(dx, dy) * 180 / math.Pi
+	lat, lon, _ := NewGeodesic(p.Center.Ell).Direct(p.Center.Latitude, p.Center.Longitude, azi, math.Hypot(dx, dy))
+	return Geodetic{Latitude: lat, Longitude: lon, Ell: p.Center.Ell}, nil
+}
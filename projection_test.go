@@ -0,0 +1,159 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+)
+
+// dms 度分秒转十进制度
+func dms(d, m, s float64) float64 {
+	return math.Copysign(math.Abs(d)+m/60+s/3600, d)
+}
+
+func TestProjectionEPSGExamples(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	clrk, _ := NewEllipsoid("clrk66")
+	const usft = 1200.0 / 3937
+
+	lcc1, err := NewLambertConformalConic1SP(clrk, 18, -77, 1, 250000, 150000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lcc2, err := NewLambertConformalConic2SP(clrk, dms(27, 50, 0), -99, dms(28, 23, 0), dms(30, 17, 0),
+		2000000*usft, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	psb, err := NewPolarStereographicB(wgs, -71, 70, 6000000, 6000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// IOGP Guidance Note 7-2 算例
+	cases := []struct {
+		name     string
+		p        Projection
+		lat, lon float64
+		x, y     float64
+	}{
+		{"Pseudo-Mercator", WebMercator{}, dms(24, 22, 54.433), -dms(100, 20, 0), -11169055.58, 2800000.00},
+		{"LCC 1SP (JAD69)", lcc1, dms(17, 55, 55.80), -dms(76, 56, 37.26), 255966.58, 142493.51},
+		{"LCC 2SP (Texas South Central)", lcc2, 28.5, -96, 2963503.91 * usft, 254759.80 * usft},
+		{"Polar stereographic A (UPS North)", NewPolarStereographicA(wgs, true, 0, 0.994, 2e6, 2e6),
+			73, 44, 3320416.75, 632668.43},
+		{"Polar stereographic B (Australian Antarctic)", psb, -75, 120, 7255380.79, 7053389.56},
+	}
+	for _, c := range cases {
+		x, y, err := c.p.Forward(Geodetic{Latitude: c.lat, Longitude: c.lon})
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if math.Abs(x-c.x) > 0.01 || math.Abs(y-c.y) > 0.01 {
+			t.Errorf("%s: forward (%.3f, %.3f), want (%.2f, %.2f)", c.name, x, y, c.x, c.y)
+		}
+		back, err := c.p.Inverse(c.x, c.y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(back.Latitude-c.lat) > 1e-7 || math.Abs(back.Longitude-c.lon) > 1e-7 {
+			t.Errorf("%s: inverse (%.9f, %.9f), want (%.9f, %.9f)", c.name, back.Latitude, back.Longitude, c.lat, c.lon)
+		}
+	}
+}
+
+func TestProjectionRoundTrip(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	lccS, _ := NewLambertConformalConic2SP(wgs, -30, 135, -18, -36, 0, 0)
+	lcc1, _ := NewLambertConformalConic1SP(wgs, 35, 105, 0.9996, 0, 0)
+	psb, _ := NewPolarStereographicB(wgs, 70, -45, 0, 0)
+	station := Geodetic{Latitude: 39.9, Longitude: 116.4, Ell: wgs}
+	projs := map[string]Projection{
+		"tmerc":   NewTransverseMercator(wgs, 117, 0, 1, 500000, 0),
+		"webmerc": WebMercator{},
+		"lcc2s":   lccS,
+		"lcc1":    lcc1,
+		"psA-s":   NewPolarStereographicA(wgs, false, 0, 0.994, 2e6, 2e6),
+		"psB":     psb,
+		"aeqd":    NewAzimuthalEquidistant(station, 0, 0),
+	}
+	pts := map[string][][2]float64{
+		"tmerc":   {{39.9, 116.4}, {0, 120}, {-60, 114}},
+		"webmerc": {{85, 179}, {-45, -30}, {0, 0}},
+		"lcc2s":   {{-25, 130}, {-40, 150}, {-10, 115}},
+		"lcc1":    {{35, 105}, {50, 80}, {20, 130}},
+		"psA-s":   {{-90, 0}, {-70, 135}, {-60, -100}},
+		"psB":     {{90, 0}, {75, -45}, {60, 30}},
+		"aeqd":    {{39.9, 116.4}, {40.5, 117}, {30, 100}, {-20, -60}},
+	}
+	for name, p := range projs {
+		for _, pt := range pts[name] {
+			x, y, err := p.Forward(Geodetic{Latitude: pt[0], Longitude: pt[1], Ell: wgs})
+			if err != nil {
+				t.Fatalf("%s %v: %v", name, pt, err)
+			}
+			g, err := p.Inverse(x, y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g.Ell == nil || g.Ell.Model != "wgs84" || math.Abs(g.Latitude-pt[0]) > 1e-9 ||
+				(math.Abs(pt[0]) < 90 && math.Abs(math.Remainder(g.Longitude-pt[1], 360)) > 1e-9) {
+				t.Errorf("%s %v: round trip (%.12f, %.12f)", name, pt, g.Latitude, g.Longitude)
+			}
+		}
+	}
+
+	if _, _, err := (WebMercator{}).Forward(Geodetic{Latitude: 90}); err == nil {
+		t.Error("expected webmercator error at the pole")
+	}
+	if _, _, err := lcc1.Forward(Geodetic{Latitude: -90}); err == nil {
+		t.Error("expected lcc error at the opposite pole")
+	}
+	if _, _, err := psb.Forward(Geodetic{Latitude: -90}); err == nil {
+		t.Error("expected polar stereographic error at the opposite pole")
+	}
+	if _, err := NewLambertConformalConic2SP(wgs, 0, 0, 30, -30, 0, 0); err == nil {
+		t.Error("expected error for symmetric standard parallels")
+	}
+	if _, err := NewPolarStereographicB(wgs, 0, 0, 0, 0); err == nil {
+		t.Error("expected error for equatorial standard parallel")
+	}
+	// 变体 B 标准纬线处比例为 1: 相邻纬度差的投影距离等于子午线弧长
+	ps, _ := NewPolarStereographicB(wgs, 71, 0, 0, 0)
+	_, y1, _ := ps.Forward(Geodetic{Latitude: 71 - 1e-5})
+	_, y2, _ := ps.Forward(Geodetic{Latitude: 71 + 1e-5})
+	arc := 2e-5 * math.Pi / 180 * wgs.SemimajorAxis * (1 - wgs.Eccentricity*wgs.Eccentricity) /
+		math.Pow(1-math.Pow(wgs.Eccentricity*math.Sin(71*math.Pi/180), 2), 1.5)
+	if math.Abs(math.Abs(y2-y1)/arc-1) > 1e-8 {
+		t.Errorf("polar stereographic B scale at standard parallel: %.12f", math.Abs(y2-y1)/arc)
+	}
+}
+
+func TestAzimuthalEquidistant(t *testing.T) {
+	grs, _ := NewEllipsoid("grs80")
+	// Vincenty 算例 (Geoscience Australia): Flinders Peak → Buninyong
+	flinders := Geodetic{Latitude: -dms(37, 57, 3.72030), Longitude: dms(144, 25, 29.52440), Ell: grs}
+	p := NewAzimuthalEquidistant(flinders, 0, 0)
+	x, y, err := p.Forward(Geodetic{Latitude: -dms(37, 39, 10.15610), Longitude: dms(143, 55, 35.38390)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := math.Hypot(x, y); math.Abs(s-54972.271) > 1e-3 {
+		t.Errorf("distance %.4f", s)
+	}
+	if azi := wrapDeg(math.Atan2(x, y) * 180 / math.Pi); math.Abs(azi-dms(306, 52, 5.37)) > 0.01/3600 {
+		t.Errorf("azimuth %.8f", azi)
+	}
+
+	// 距离圈: 反算点到中心的距离恒等于半径
+	for az := 0.0; az < 360; az += 45 {
+		sa, ca := math.Sincos(az * math.Pi / 180)
+		g, _ := p.Inverse(200e3*sa, 200e3*ca)
+		s, azi, _ := flinders.GeodesicInverse(g)
+		if math.Abs(s-200e3) > 1e-8 || math.Abs(math.Remainder(azi-az, 360)) > 1e-9 {
+			t.Errorf("ring az %g: s = %.9f, azi = %.12f", az, s, azi)
+		}
+	}
+	if g, _ := p.Inverse(0, 0); g.Latitude != flinders.Latitude || g.Longitude != flinders.Longitude {
+		t.Errorf("center: %+v", g)
+	}
+}
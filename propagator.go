@@ -0,0 +1,439 @@
+package gomap3d
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ============================================================
+// 数值轨道传播
+//
+// 在转换器给出的地心惯性系 (ModelGMST 为 GMST 伪惯性系，完整模型为 GCRF) 中
+// 积分 r̈ = a_grav + a_drag + a_sun + a_moon：
+//   a_grav  二体或球谐引力场（带谐项 J2–J6 或 EGM 系数文件），在 ECEF 中计算
+//   a_drag  -½·Cd·A/m·ρ·|v_rel|·v_rel，v_rel 为相对随地球旋转大气的速度
+//   a_sun、a_moon  解析日月星历的第三体摄动
+// 积分器：RK4 定步长；Dormand-Prince 5(4) 与 Fehlberg 7(8) 自适应步长。
+// ============================================================
+
+// Integrator 数值积分方法
+type Integrator int
+
+const (
+	RK4             Integrator = iota // 经典四阶 Runge-Kutta，定步长
+	DormandPrince54                   // Dormand-Prince 5(4)，自适应步长
+	RKF78                             // Runge-Kutta-Fehlberg 7(8)，自适应步长
+)
+
+func (in Integrator) String() string {
+	switch in {
+	case RK4:
+		return "RK4"
+	case DormandPrince54:
+		return "DormandPrince54"
+	case RKF78:
+		return "RKF78"
+	}
+	return fmt.Sprintf("Integrator(%d)", int(in))
+}
+
+// ErrStepSize 自适应积分步长过小，通常表示目标已再入或力模型发散
+var ErrStepSize = errors.New("propagator: step size underflow")
+
+// ECIState ECI 位置与速度 (m/s)
+type ECIState struct {
+	ECI
+	VX, VY, VZ float64
+}
+
+// ForceModel 摄动力模型，零值为二体问题
+type ForceModel struct {
+	Gravity         *GravityField // 引力场，nil 为二体 (GM 取自初始状态的椭球)
+	Degree, Order   int           // 引力场截断阶次，0 为使用全部系数
+	Atmosphere      Atmosphere    // 大气模型，nil 不计阻力
+	DragCoefficient float64       // 阻力系数 Cd
+	Area            float64       // 迎风面积 (m²)
+	Mass            float64       // 质量 (kg)
+	Sun, Moon       bool          // 是否计入日、月第三体摄动
+}
+
+// PropagatorOptions 积分选项
+type PropagatorOptions struct {
+	Integrator  Integrator
+	Step        float64      // RK4 步长或自适应初始步长 (s)，默认 60
+	MaxStep     float64      // 自适应最大步长 (s)，默认 3600
+	Tolerance   float64      // 自适应积分相对误差容限，默认 1e-12
+	Transformer *Transformer // 惯性系定义，nil 为默认转换器
+}
+
+// Propagator 数值轨道传播器，创建后不可修改，可并发使用
+type Propagator struct {
+	epoch  ECIState
+	forces ForceModel
+	opts   PropagatorOptions
+	tr     *Transformer
+	gm     float64
+	jdTT0  float64 // 初始历元 TT 儒略日
+}
+
+// NewPropagator 由初始 ECI 状态创建传播器
+func NewPropagator(state ECIState, forces ForceModel, opts PropagatorOptions) (*Propagator, error) {
+	p := &Propagator{epoch: state, forces: forces, opts: opts, tr: opts.Transformer}
+	if p.tr == nil {
+		p.tr = DefaultTransformer()
+	}
+	switch {
+	case forces.Gravity != nil:
+		p.gm = forces.Gravity.GM
+	case state.Ell != nil:
+		p.gm = state.Ell.GM
+	}
+	if p.gm <= 0 {
+		return nil, fmt.Errorf("propagator: no gravitational parameter (set Gravity or ECI.Ell)")
+	}
+	if forces.Atmosphere != nil {
+		if state.Ell == nil {
+			return nil, fmt.Errorf("propagator: drag requires ECI.Ell for geodetic altitude")
+		}
+		if forces.Mass <= 0 || forces.Area < 0 {
+			return nil, fmt.Errorf("propagator: invalid drag area %g m² / mass %g kg", forces.Area, forces.Mass)
+		}
+	}
+	if opts.Integrator < RK4 || opts.Integrator > RKF78 {
+		return nil, fmt.Errorf("propagator: unknown integrator %v", opts.Integrator)
+	}
+	if p.opts.Step <= 0 {
+		p.opts.Step = 60
+	}
+	if p.opts.MaxStep <= 0 {
+		p.opts.MaxStep = 3600
+	}
+	if p.opts.Tolerance <= 0 {
+		p.opts.Tolerance = 1e-12
+	}
+	p.jdTT0 = NewEpoch(p.tr.utc(state.T), UTC).to(TT, p.tr.opts.EOP).JD()
+	return p, nil
+}
+
+// Epoch 初始状态
+func (p *Propagator) Epoch() ECIState { return p.epoch }
+
+// Propagate 传播到时刻 t（与初始状态 T 同一时间尺度）
+func (p *Propagator) Propagate(t time.Time) (ECIState, error) {
+	s, err := p.PropagateEpochs([]time.Time{t})
+	if err != nil {
+		return ECIState{}, err
+	}
+	return s[0], nil
+}
+
+// PropagateEpochs 传播到一组时刻，结果与 ts 顺序一致
+// 历元前后的时刻分别向后、向前连续积分，不要求 ts 有序。
+func (p *Propagator) PropagateEpochs(ts []time.Time) ([]ECIState, error) {
+	out := make([]ECIState, len(ts))
+	idx := make([]int, len(ts))
+	for i := range idx {
+		idx[i] = i
+	}
+	dt := func(i int) float64 { return ts[i].Sub(p.epoch.T).Seconds() }
+	sort.Slice(idx, func(a, b int) bool { return dt(idx[a]) < dt(idx[b]) })
+	split := sort.Search(len(idx), func(k int) bool { return dt(idx[k]) >= 0 })
+
+	// 向前：split..end 升序；向后：split-1..0 降序
+	fwd := idx[split:]
+	bwd := make([]int, split)
+	for k := range bwd {
+		bwd[k] = idx[split-1-k]
+	}
+	for _, seq := range [][]int{fwd, bwd} {
+		run := p.newRun()
+		y := [6]float64{p.epoch.X, p.epoch.Y, p.epoch.Z, p.epoch.VX, p.epoch.VY, p.epoch.VZ}
+		t, h := 0.0, p.opts.Step
+		for _, i := range seq {
+			var err error
+			if y, h, err = run.integrate(y, t, dt(i), h); err != nil {
+				return nil, fmt.Errorf("%w (at %v)", err, ts[i])
+			}
+			t = dt(i)
+			out[i] = ECIState{
+				ECI: ECI{X: y[0], Y: y[1], Z: y[2], T: ts[i], Ell: p.epoch.Ell},
+				VX:  y[3], VY: y[4], VZ: y[5],
+			}
+		}
+	}
+	return out, nil
+}
+
+// ============================================================
+// 力模型
+// ============================================================
+
+// propRun 单次积分过程，缓存地球指向矩阵
+type propRun struct {
+	p *Propagator
+
+	// 地球指向：M(t) ≈ R3(ω·(t - tRef))·M(tRef)，每 1 h 重新计算一次完整矩阵
+	tRef   float64
+	mRef   [3][3]float64
+	omega  float64
+	hasRef bool
+}
+
+func (p *Propagator) newRun() *propRun {
+	return &propRun{p: p}
+}
+
+// earthMatrix 相对历元 t 秒时的 ECI → ECEF 矩阵
+func (run *propRun) earthMatrix(t float64) [3][3]float64 {
+	if !run.hasRef || math.Abs(t-run.tRef) > 3600 {
+		tt := run.p.epoch.T.Add(time.Duration(t * float64(time.Second)))
+		run.tRef = t
+		run.mRef = run.p.tr.Matrix(tt)
+		run.omega = run.p.tr.rotationRate(tt)
+		run.hasRef = true
+	}
+	return mul33(R3(run.omega*(t-run.tRef)), run.mRef)
+}
+
+// accel 相对历元 t 秒时的加速度 (m/s²)
+func (run *propRun) accel(t float64, r, v [3]float64) [3]float64 {
+	p := run.p
+	f := &p.forces
+	var a [3]float64
+
+	needM := f.Gravity != nil || f.Atmosphere != nil
+	var m [3][3]float64
+	if needM {
+		m = run.earthMatrix(t)
+	}
+
+	if f.Gravity != nil {
+		rb := multiplyMatrixVector(m, r)
+		ax, ay, az := f.Gravity.Acceleration(rb[0], rb[1], rb[2], f.Degree, f.Order)
+		a = multiplyMatrixVector(transpose(m), [3]float64{ax, ay, az})
+	} else {
+		rn := norm3(r)
+		k := -p.gm / (rn * rn * rn)
+		a = [3]float64{k * r[0], k * r[1], k * r[2]}
+	}
+
+	var sun [3]float64
+	T := (p.jdTT0 + t/86400 - 2451545.0) / 36525.0
+	if f.Sun || f.Atmosphere != nil {
+		sun = sunPosition(T)
+	}
+	if f.Sun {
+		as := thirdBodyAccel(r, sun, GMSun)
+		a = [3]float64{a[0] + as[0], a[1] + as[1], a[2] + as[2]}
+	}
+	if f.Moon {
+		am := thirdBodyAccel(r, moonPosition(T), GMMoon)
+		a = [3]float64{a[0] + am[0], a[1] + am[1], a[2] + am[2]}
+	}
+
+	if f.Atmosphere != nil {
+		rb := multiplyMatrixVector(m, r)
+		_, _, h := ECEF2Geodetic(rb[0], rb[1], rb[2], p.epoch.Ell)
+		rho := f.Atmosphere.Density(h, r, sun)
+		if rho > 0 {
+			// 大气随地球旋转，ω 沿地固系 z 轴
+			w := m[2]
+			wr := cross3(w, r)
+			var vr [3]float64
+			for i := range vr {
+				vr[i] = v[i] - run.omega*wr[i]
+			}
+			k := -0.5 * f.DragCoefficient * f.Area / f.Mass * rho * norm3(vr)
+			for i := range a {
+				a[i] += k * vr[i]
+			}
+		}
+	}
+	return a
+}
+
+// deriv 状态导数 ẏ = (v, a)
+func (run *propRun) deriv(t float64, y [6]float64) [6]float64 {
+	r := [3]float64{y[0], y[1], y[2]}
+	v := [3]float64{y[3], y[4], y[5]}
+	a := run.accel(t, r, v)
+	return [6]float64{v[0], v[1], v[2], a[0], a[1], a[2]}
+}
+
+// ============================================================
+// 积分器
+// ============================================================
+
+// rkTableau 显式 Runge-Kutta 系数；b 为推进解，e = b - b̂ 为误差估计系数
+type rkTableau struct {
+	c     []float64
+	a     [][]float64
+	b, e  []float64
+	order int // 误差估计中较低的阶数
+}
+
+// dopri54 Dormand-Prince 5(4)
+var dopri54 = func() *rkTableau {
+	b := []float64{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84, 0}
+	bh := []float64{5179.0 / 57600, 0, 7571.0 / 16695, 393.0 / 640, -92097.0 / 339200, 187.0 / 2100, 1.0 / 40}
+	return &rkTableau{
+		c: []float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1, 1},
+		a: [][]float64{
+			{},
+			{1.0 / 5},
+			{3.0 / 40, 9.0 / 40},
+			{44.0 / 45, -56.0 / 15, 32.0 / 9},
+			{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+			{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+			{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+		},
+		b:     b,
+		e:     rkDiff(b, bh),
+		order: 4,
+	}
+}()
+
+// rkf78 Fehlberg 7(8)，以 8 阶解推进
+var rkf78 = func() *rkTableau {
+	b7 := []float64{41.0 / 840, 0, 0, 0, 0, 34.0 / 105, 9.0 / 35, 9.0 / 35, 9.0 / 280, 9.0 / 280, 41.0 / 840, 0, 0}
+	b8 := []float64{0, 0, 0, 0, 0, 34.0 / 105, 9.0 / 35, 9.0 / 35, 9.0 / 280, 9.0 / 280, 0, 41.0 / 840, 41.0 / 840}
+	return &rkTableau{
+		c: []float64{0, 2.0 / 27, 1.0 / 9, 1.0 / 6, 5.0 / 12, 1.0 / 2, 5.0 / 6, 1.0 / 6, 2.0 / 3, 1.0 / 3, 1, 0, 1},
+		a: [][]float64{
+			{},
+			{2.0 / 27},
+			{1.0 / 36, 1.0 / 12},
+			{1.0 / 24, 0, 1.0 / 8},
+			{5.0 / 12, 0, -25.0 / 16, 25.0 / 16},
+			{1.0 / 20, 0, 0, 1.0 / 4, 1.0 / 5},
+			{-25.0 / 108, 0, 0, 125.0 / 108, -65.0 / 27, 125.0 / 54},
+			{31.0 / 300, 0, 0, 0, 61.0 / 225, -2.0 / 9, 13.0 / 900},
+			{2, 0, 0, -53.0 / 6, 704.0 / 45, -107.0 / 9, 67.0 / 90, 3},
+			{-91.0 / 108, 0, 0, 23.0 / 108, -976.0 / 135, 311.0 / 54, -19.0 / 60, 17.0 / 6, -1.0 / 12},
+			{2383.0 / 4100, 0, 0, -341.0 / 164, 4496.0 / 1025, -301.0 / 82, 2133.0 / 4100, 45.0 / 82, 45.0 / 164, 18.0 / 41},
+			{3.0 / 205, 0, 0, 0, 0, -6.0 / 41, -3.0 / 205, -3.0 / 41, 3.0 / 41, 6.0 / 41, 0},
+			{-1777.0 / 4100, 0, 0, -341.0 / 164, 4496.0 / 1025, -289.0 / 82, 2193.0 / 4100, 51.0 / 82, 33.0 / 164, 12.0 / 41, 0, 1},
+		},
+		b:     b8,
+		e:     rkDiff(b8, b7),
+		order: 7,
+	}
+}()
+
+// rk4Tableau 经典 RK4
+var rk4Tableau = &rkTableau{
+	c: []float64{0, 0.5, 0.5, 1},
+	a: [][]float64{{}, {0.5}, {0, 0.5}, {0, 0, 1}},
+	b: []float64{1.0 / 6, 1.0 / 3, 1.0 / 3, 1.0 / 6},
+}
+
+func rkDiff(a, b []float64) []float64 {
+	d := make([]float64, len(a))
+	for i := range a {
+		d[i] = a[i] - b[i]
+	}
+	return d
+}
+
+// step 单步积分，返回新状态与误差估计（无误差系数时为零）
+func (tab *rkTableau) step(f func(float64, [6]float64) [6]float64, t float64, y [6]float64, h float64) (yn, yerr [6]float64) {
+	k := make([][6]float64, len(tab.c))
+	for s := range tab.c {
+		ys := y
+		for j, aj := range tab.a[s] {
+			if aj == 0 {
+				continue
+			}
+			for i := range ys {
+				ys[i] += h * aj * k[j][i]
+			}
+		}
+		k[s] = f(t+tab.c[s]*h, ys)
+	}
+	yn = y
+	for s, bs := range tab.b {
+		if bs == 0 {
+			continue
+		}
+		for i := range yn {
+			yn[i] += h * bs * k[s][i]
+		}
+	}
+	for s := range tab.e {
+		if tab.e[s] == 0 {
+			continue
+		}
+		for i := range yerr {
+			yerr[i] += h * tab.e[s] * k[s][i]
+		}
+	}
+	return yn, yerr
+}
+
+// integrate 由 t0 积分到 t1，h 为建议步长，返回新状态与下一步建议步长
+func (run *propRun) integrate(y [6]float64, t0, t1, h float64) ([6]float64, float64, error) {
+	opts := &run.p.opts
+	dir := 1.0
+	if t1 < t0 {
+		dir = -1
+	}
+	h = math.Abs(h)
+
+	if opts.Integrator == RK4 {
+		for t := t0; t != t1; {
+			hs := math.Min(opts.Step, math.Abs(t1-t))
+			y, _ = rk4Tableau.step(run.deriv, t, y, dir*hs)
+			if hs == math.Abs(t1-t) {
+				t = t1
+			} else {
+				t += dir * hs
+			}
+		}
+		return y, opts.Step, nil
+	}
+
+	tab := dopri54
+	if opts.Integrator == RKF78 {
+		tab = rkf78
+	}
+	tol := opts.Tolerance
+	t := t0
+	hNext := h
+	for dir*(t1-t) > 0 {
+		h = math.Min(h, opts.MaxStep)
+		hNext = h
+		last := false
+		if h >= dir*(t1-t) {
+			h = dir * (t1 - t)
+			last = true
+		}
+		yn, yerr := tab.step(run.deriv, t, y, dir*h)
+
+		// 误差范数：位置、速度分量按各自量级归一化
+		var en float64
+		for i := range yerr {
+			sc := tol * (1 + math.Max(math.Abs(y[i]), math.Abs(yn[i])))
+			en = math.Max(en, math.Abs(yerr[i])/sc)
+		}
+		fac := 0.9 * math.Pow(math.Max(en, 1e-10), -1/float64(tab.order+1))
+		fac = math.Min(5, math.Max(0.2, fac))
+		if en <= 1 {
+			y = yn
+			if last {
+				// 为到达 t1 截短的步长不作为下一段的建议步长
+				return y, math.Max(hNext, h*fac), nil
+			}
+			t += dir * h
+			h *= fac
+			continue
+		}
+		h *= fac
+		if h < 1e-6 {
+			return y, h, ErrStepSize
+		}
+	}
+	return y, hNext, nil
+}
@@ -0,0 +1,249 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var propEpoch = time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+
+// keplerState 由轨道根数构造初始状态，并给出 dt 秒后的二体解
+func keplerState(t *testing.T, el OrbitalElements, dt float64) (s0 ECIState, want [3]float64) {
+	t.Helper()
+	ell, _ := NewEllipsoid("wgs84")
+	x, y, z, vx, vy, vz, err := el.ToState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s0 = ECIState{ECI: ECI{X: x, Y: y, Z: z, T: propEpoch, Ell: ell}, VX: vx, VY: vy, VZ: vz}
+
+	n, _ := el.MeanMotion()
+	m := el.MeanAnomaly() + n*dt*180/math.Pi
+	el2 := ElementsFromMeanAnomaly(el.SemimajorAxis, el.Eccentricity, el.Inclination, el.RAAN, el.ArgPerigee, m, el.GM)
+	x, y, z, _, _, _, _ = el2.ToState()
+	return s0, [3]float64{x, y, z}
+}
+
+func propAfter(dt float64) time.Time {
+	return propEpoch.Add(time.Duration(dt * float64(time.Second)))
+}
+
+func TestPropagatorTwoBody(t *testing.T) {
+	leo := ElementsFromMeanAnomaly(6778e3, 0.001, 51.6, 30, 40, 10, 3.986004418e14)
+	molniya := ElementsFromMeanAnomaly(26600e3, 0.74, 63.4, 80, 270, 0, 3.986004418e14)
+	cases := []struct {
+		name string
+		el   OrbitalElements
+		opts PropagatorOptions
+		dt   float64
+		tol  float64 // m
+	}{
+		{"leo rkf78", leo, PropagatorOptions{Integrator: RKF78}, 86400, 1e-2},
+		{"leo dopri", leo, PropagatorOptions{Integrator: DormandPrince54}, 86400, 2e-2},
+		{"leo rk4", leo, PropagatorOptions{Integrator: RK4, Step: 10}, 5554, 1},
+		{"molniya rkf78", molniya, PropagatorOptions{Integrator: RKF78}, 86400, 1e-2},
+		{"molniya dopri", molniya, PropagatorOptions{Integrator: DormandPrince54}, 86400, 1e-1},
+		{"molniya back", molniya, PropagatorOptions{Integrator: RKF78}, -43200, 1e-2},
+	}
+	for _, c := range cases {
+		s0, want := keplerState(t, c.el, c.dt)
+		p, err := NewPropagator(s0, ForceModel{}, c.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := p.Propagate(propAfter(c.dt))
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if d := vecDist([3]float64{s.X, s.Y, s.Z}, want); d > c.tol {
+			t.Errorf("%s: position error %.3e m", c.name, d)
+		}
+		if !s.T.Equal(propAfter(c.dt)) || s.Ell != s0.Ell {
+			t.Errorf("%s: state metadata %v %v", c.name, s.T, s.Ell)
+		}
+	}
+}
+
+func TestPropagatorEpochs(t *testing.T) {
+	el := ElementsFromMeanAnomaly(7000e3, 0.01, 98, 10, 20, 30, 3.986004418e14)
+	s0, _ := keplerState(t, el, 0)
+	g, _ := ZonalField(4)
+	p, err := NewPropagator(s0, ForceModel{Gravity: g}, PropagatorOptions{Integrator: RKF78})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 乱序、跨历元的一组时刻与逐个传播结果一致
+	dts := []float64{3600, -1800, 0, 600, -7200, 12000}
+	ts := make([]time.Time, len(dts))
+	for i, dt := range dts {
+		ts[i] = propAfter(dt)
+	}
+	all, err := p.PropagateEpochs(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, dt := range dts {
+		one, err := p.Propagate(ts[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := vecDist([3]float64{all[i].X, all[i].Y, all[i].Z}, [3]float64{one.X, one.Y, one.Z}); d > 1e-3 {
+			t.Errorf("dt=%.0f: batch differs from single by %.3e m", dt, d)
+		}
+	}
+	if d := vecDist([3]float64{all[2].X, all[2].Y, all[2].Z}, [3]float64{s0.X, s0.Y, s0.Z}); d != 0 {
+		t.Errorf("dt=0 moved by %g m", d)
+	}
+
+	// 正向再反向回到初始状态
+	back, err := NewPropagator(all[5], ForceModel{Gravity: g}, PropagatorOptions{Integrator: RKF78})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := back.Propagate(propEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := vecDist([3]float64{s.X, s.Y, s.Z}, [3]float64{s0.X, s0.Y, s0.Z}); d > 1e-3 {
+		t.Errorf("round trip error %.3e m", d)
+	}
+}
+
+func TestPropagatorJ2Precession(t *testing.T) {
+	// 升交点赤经长期漂移 Ω̇ = -1.5·n·J2·(R/p)²·cos i
+	el := ElementsFromMeanAnomaly(6878e3, 0.001, 51.6, 0, 0, 0, egm96GM)
+	s0, _ := keplerState(t, el, 0)
+	g, _ := ZonalField(2)
+	p, err := NewPropagator(s0, ForceModel{Gravity: g}, PropagatorOptions{Integrator: RKF78, Transformer: &Transformer{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const days = 5
+	s, err := p.Propagate(propAfter(days * 86400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	el2, err := s.ToOrbitalElements(s.VX, s.VY, s.VZ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := el.MeanMotion()
+	pp := el.SemimajorAxis * (1 - el.Eccentricity*el.Eccentricity)
+	rate := -1.5 * n * EarthJ2 * math.Pow(g.Radius/pp, 2) * math.Cos(el.Inclination*math.Pi/180)
+	want := rate * days * 86400 * 180 / math.Pi
+	got := math.Remainder(el2.RAAN, 360)
+	if math.Abs(got-want) > 0.01*math.Abs(want) {
+		t.Errorf("RAAN drift %.4f°, want %.4f°", got, want)
+	}
+
+	// 带谐项 J2–J4 与 EGM96 4×4 场：田谐项带来小而非零的差异
+	egm, _ := LoadGravityField("test_data/egm96_n4.gfc", 0)
+	z4, _ := ZonalField(4)
+	pe, _ := NewPropagator(s0, ForceModel{Gravity: egm}, PropagatorOptions{Integrator: RKF78})
+	pz, _ := NewPropagator(s0, ForceModel{Gravity: z4}, PropagatorOptions{Integrator: RKF78})
+	se, _ := pe.Propagate(propAfter(6 * 3600))
+	sz, _ := pz.Propagate(propAfter(6 * 3600))
+	if d := vecDist([3]float64{se.X, se.Y, se.Z}, [3]float64{sz.X, sz.Y, sz.Z}); d < 10 || d > 20e3 {
+		t.Errorf("tesseral effect after 6 h = %.1f m", d)
+	}
+}
+
+func TestPropagatorDragAndThirdBody(t *testing.T) {
+	// 300 km 赤道圆轨道，Cd = 2.2，A/m = 0.01 m²/kg
+	el := ElementsFromMeanAnomaly(6678137, 0, 0, 0, 0, 0, 3.986004418e14)
+	s0, _ := keplerState(t, el, 0)
+	drag := func(atm Atmosphere) float64 {
+		p, err := NewPropagator(s0, ForceModel{Atmosphere: atm, DragCoefficient: 2.2, Area: 1, Mass: 100},
+			PropagatorOptions{Integrator: DormandPrince54, Tolerance: 1e-10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := p.Propagate(propAfter(86400))
+		if err != nil {
+			t.Fatal(err)
+		}
+		el2, _ := s.ToOrbitalElements(s.VX, s.VY, s.VZ)
+		return el2.SemimajorAxis - el.SemimajorAxis
+	}
+	// 每圈 Δa ≈ -2π·Cd·A/m·ρ·a²，约 16 圈/天
+	if da := drag(ExponentialAtmosphere{}); da > -1000 || da < -5000 {
+		t.Errorf("exponential drag Δa = %.1f m/day", da)
+	}
+	if da := drag(HarrisPriester{}); da > -500 || da < -5000 {
+		t.Errorf("Harris-Priester drag Δa = %.1f m/day", da)
+	}
+	if _, err := NewPropagator(s0, ForceModel{Atmosphere: ExponentialAtmosphere{}}, PropagatorOptions{}); err == nil {
+		t.Error("expected error for drag without mass")
+	}
+
+	// 地球同步轨道：日月摄动一天内造成公里量级偏差
+	geo := ElementsFromMeanAnomaly(42164e3, 0, 0.1, 0, 0, 0, 3.986004418e14)
+	g0, want := keplerState(t, geo, 86400)
+	p, _ := NewPropagator(g0, ForceModel{Sun: true, Moon: true}, PropagatorOptions{Integrator: RKF78})
+	s, err := p.Propagate(propAfter(86400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := vecDist([3]float64{s.X, s.Y, s.Z}, want); d < 500 || d > 50e3 {
+		t.Errorf("third-body effect on GEO after 1 day = %.1f m", d)
+	}
+}
+
+func TestAtmosphereDensity(t *testing.T) {
+	var exp ExponentialAtmosphere
+	var zero [3]float64
+	if rho := exp.Density(0, zero, zero); rho != 1.225 {
+		t.Errorf("sea level density = %g", rho)
+	}
+	if rho := exp.Density(400e3, zero, zero); math.Abs(rho-3.725e-12) > 1e-15 {
+		t.Errorf("400 km density = %g", rho)
+	}
+	// 分段连续
+	if a, b := exp.Density(99999.999, zero, zero), exp.Density(100000, zero, zero); math.Abs(a-b) > 1e-3*b {
+		t.Errorf("discontinuity at 100 km: %g vs %g", a, b)
+	}
+
+	// Harris-Priester：隆起方向 (太阳以东 30°) 取最大值，反方向取最小值
+	hp := HarrisPriester{N: 6}
+	sun := [3]float64{1.5e11, 0, 0}
+	r := 6778e3
+	bulge := [3]float64{r * math.Cos(hpLag), r * math.Sin(hpLag), 0}
+	anti := [3]float64{-bulge[0], -bulge[1], 0}
+	if rho := hp.Density(400e3, bulge, sun); math.Abs(rho-7.492e-12) > 1e-16 {
+		t.Errorf("bulge density = %g", rho)
+	}
+	if rho := hp.Density(400e3, anti, sun); math.Abs(rho-2.249e-12) > 1e-16 {
+		t.Errorf("anti-bulge density = %g", rho)
+	}
+	if rho := hp.Density(50e3, bulge, sun); rho != 0 {
+		t.Errorf("density below model range = %g", rho)
+	}
+}
+
+func TestSunMoonEphemeris(t *testing.T) {
+	angle := func(a, b [3]float64) float64 {
+		return math.Acos(dot3(a, b)/(norm3(a)*norm3(b))) * 180 / math.Pi
+	}
+	// Vallado Example 5-1：2006-04-02 00:00 UTC，太阳 (km，MOD)
+	x, y, z := SunECI(time.Date(2006, 4, 2, 0, 0, 0, 0, time.UTC))
+	sun := [3]float64{x / 1e3, y / 1e3, z / 1e3}
+	ref := [3]float64{146186178, 28789122, 12481127}
+	if a := angle(sun, ref); a > 0.15 {
+		t.Errorf("Sun direction error %.4f°", a)
+	}
+	if d := math.Abs(norm3(sun)/norm3(ref) - 1); d > 1e-3 {
+		t.Errorf("Sun distance error %.2e", d)
+	}
+
+	// Vallado Example 5-3：1994-04-28 00:00 UT，月球 (km)
+	x, y, z = MoonECI(time.Date(1994, 4, 28, 0, 0, 0, 0, time.UTC))
+	moon := [3]float64{x / 1e3, y / 1e3, z / 1e3}
+	ref = [3]float64{-134240.626, -311571.590, -126693.785}
+	if a := angle(moon, ref); a > 0.5 {
+		t.Errorf("Moon direction error %.4f°", a)
+	}
+	if d := math.Abs(norm3(moon)/norm3(ref) - 1); d > 5e-3 {
+		t.Errorf("Moon distance error %.2e", d)
+	}
+}
@@ -0,0 +1,378 @@
+package gomap3d
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================================
+// 大气折射修正
+//
+// 对流层折射率随高度递减，射线向地面弯曲：测得的视在俯仰高于目标的几何俯仰，
+// 雷达测得的视在距离 (电磁路径长度 ∫n ds) 长于直线距离。各模型均假设大气
+// 球面分层，方位角不受影响；测站所在竖直面内以测站处沿方位的法截线曲率半径
+// R 作为局部地球半径。
+//   - EffectiveEarthRefraction: 等效地球半径 kR 上射线为直线 (k = 4/3)
+//   - OpticalRefraction: Bennett (视在 → 几何) / Saemundsson (几何 → 视在) 天文
+//     蒙气差公式，适用于无穷远目标，不改变距离
+//   - RayTracedRefraction: 对折射指数剖面 N(h) (CRPL 指数大气或分层剖面) 数值
+//     求解射线方程 d(n·t)/ds = ∇n，给出俯仰修正与距离偏差
+// ============================================================
+
+// refractionTop 射线追踪的大气顶高度 (m)，其上视为真空直线传播
+const refractionTop = 100e3
+
+// RefractionModel 大气折射模型
+type RefractionModel interface {
+	// Geometric 由测站处沿方位 az 的视在俯仰 el (°) 与视在距离 srange (m) 求几何俯仰与直线距离
+	Geometric(station Geodetic, az, el, srange float64) (gel, grange float64)
+	// Apparent 由几何俯仰 el (°) 与直线距离 srange (m) 求视在俯仰与视在距离
+	// 没有射线能从测站到达目标 (如位于地平以下) 时返回错误。
+	Apparent(station Geodetic, az, el, srange float64) (ael, arange float64, err error)
+}
+
+// planeTarget 测站竖直面内 (地球半径 R、测站高 h0) 俯仰 el、直线距离 srange 处目标的高度与地心角 (rad)
+func planeTarget(R, h0, el, srange float64) (h, theta float64) {
+	se, ce := sincosd(el)
+	x, y := srange*ce, R+h0+srange*se
+	return math.Hypot(x, y) - R, math.Atan2(x, y)
+}
+
+// planeLook planeTarget 的逆: 由目标高度与地心角求俯仰与直线距离
+func planeLook(R, h0, h, theta float64) (el, srange float64) {
+	s, c := math.Sincos(theta)
+	dx, dy := (R+h)*s, (R+h)*c-(R+h0)
+	return atan2d(dy, dx), math.Hypot(dx, dy)
+}
+
+// EffectiveEarthRefraction 等效地球半径 (k 因子) 折射模型
+// 目标高度与沿地面弧长不变，地心角按 1/k 缩放后在等效地球上以直线连接；
+// 视在距离取等效地球上的直线长度。
+type EffectiveEarthRefraction struct {
+	K float64 // 等效地球半径系数，0 为 DefaultKFactor
+}
+
+func (m EffectiveEarthRefraction) k() float64 {
+	if m.K == 0 {
+		return DefaultKFactor
+	}
+	return m.K
+}
+
+// Geometric 实现 RefractionModel
+func (m EffectiveEarthRefraction) Geometric(station Geodetic, az, el, srange float64) (gel, grange float64) {
+	k := m.k()
+	R := RadiusOfCurvature(station.Latitude, az, station.Ell)
+	h, theta := planeTarget(k*R, station.Altitude, el, srange)
+	return planeLook(R, station.Altitude, h, theta*k)
+}
+
+// Apparent 实现 RefractionModel
+func (m EffectiveEarthRefraction) Apparent(station Geodetic, az, el, srange float64) (ael, arange float64, err error) {
+	k := m.k()
+	R := RadiusOfCurvature(station.Latitude, az, station.Ell)
+	h, theta := planeTarget(R, station.Altitude, el, srange)
+	ael, arange = planeLook(k*R, station.Altitude, h, theta/k)
+	return ael, arange, nil
+}
+
+// OpticalRefraction 光学蒙气差 (Bennett 1982 / Saemundsson 1986，Meeus《天文算法》第 16 章)
+// 按气压与气温缩放，零值为无折射；俯仰低于 -1° 时按 -1° 计算蒙气差。
+type OpticalRefraction struct {
+	Pressure    float64 // 气压 (hPa)
+	Temperature float64 // 气温 (°C)
+}
+
+// StandardOpticalRefraction 标准条件 1010 hPa、10 °C
+var StandardOpticalRefraction = OpticalRefraction{Pressure: 1010, Temperature: 10}
+
+func (m OpticalRefraction) scale() float64 {
+	return m.Pressure / 1010 * 283 / (273 + m.Temperature)
+}
+
+// Geometric 实现 RefractionModel，Bennett 公式 R = cot(h + 7.31/(h + 4.4)) (′)，h 为视在高度角
+func (m OpticalRefraction) Geometric(station Geodetic, az, el, srange float64) (gel, grange float64) {
+	h := math.Max(el, -1)
+	r := 1 / math.Tan((h+7.31/(h+4.4))*math.Pi/180) / 60
+	return el - math.Max(r, 0)*m.scale(), srange
+}
+
+// Apparent 实现 RefractionModel，Saemundsson 公式 R = 1.02·cot(h + 10.3/(h + 5.11)) (′)，h 为几何高度角
+func (m OpticalRefraction) Apparent(station Geodetic, az, el, srange float64) (ael, arange float64, err error) {
+	h := math.Max(el, -1)
+	r := 1.02 / math.Tan((h+10.3/(h+5.11))*math.Pi/180) / 60
+	return el + math.Max(r, 0)*m.scale(), srange, nil
+}
+
+// RefractivityProfile 球面分层大气的折射指数剖面
+type RefractivityProfile interface {
+	// Refractivity 大地高 h (m) 处的折射指数 N = (n - 1)·10⁶ 及其垂直梯度 dN/dh (1/m)
+	Refractivity(h float64) (n, dndh float64)
+}
+
+// CRPLExponential CRPL 指数参考大气 (Bean & Thayer 1959)
+// N(h) = Ns·exp(-ce·(h - hs))，ce = ln(Ns / (Ns - 7.32·exp(0.005577·Ns))) (1/km)
+type CRPLExponential struct {
+	Ns            float64 // 地面折射指数，典型值 313
+	SurfaceHeight float64 // 地面高度 hs (m)
+}
+
+// Refractivity 实现 RefractivityProfile
+func (p CRPLExponential) Refractivity(h float64) (n, dndh float64) {
+	ce := math.Log(p.Ns/(p.Ns-7.32*math.Exp(0.005577*p.Ns))) / 1000
+	n = p.Ns * math.Exp(-ce*(h-p.SurfaceHeight))
+	return n, -ce * n
+}
+
+// layeredScaleHeight 分层剖面最高层以上指数衰减的标高 (m)
+const layeredScaleHeight = 7000
+
+// LayeredRefractivity 分层折射指数剖面 (如探空资料)，层间线性插值
+// 最低层以下按最低一段的梯度外推，最高层以上按 7 km 标高指数衰减。
+type LayeredRefractivity struct {
+	Height []float64 // 层高 (m)，严格升序
+	N      []float64 // 各层折射指数
+}
+
+// NewLayeredRefractivity 由层高与折射指数表创建剖面，至少 2 层
+func NewLayeredRefractivity(height, n []float64) (*LayeredRefractivity, error) {
+	if len(height) < 2 || len(height) != len(n) {
+		return nil, fmt.Errorf("refraction: need at least 2 equal-length layers, got %d/%d", len(height), len(n))
+	}
+	for i := 1; i < len(height); i++ {
+		if height[i] <= height[i-1] {
+			return nil, fmt.Errorf("refraction: layer heights not strictly increasing at %d", i)
+		}
+	}
+	return &LayeredRefractivity{Height: append([]float64(nil), height...), N: append([]float64(nil), n...)}, nil
+}
+
+// Refractivity 实现 RefractivityProfile
+func (p *LayeredRefractivity) Refractivity(h float64) (n, dndh float64) {
+	k := len(p.Height) - 1
+	if h > p.Height[k] {
+		n = p.N[k] * math.Exp(-(h-p.Height[k])/layeredScaleHeight)
+		return n, -n / layeredScaleHeight
+	}
+	i := 1
+	for i < k && h > p.Height[i] {
+		i++
+	}
+	g := (p.N[i] - p.N[i-1]) / (p.Height[i] - p.Height[i-1])
+	return p.N[i-1] + g*(h-p.Height[i-1]), g
+}
+
+// RayTracedRefraction 按折射指数剖面数值追踪射线的折射模型
+// 以 RK4 沿射线积分，视在距离为电磁路径长度 ∫n ds；大气顶 (100 km) 以上直线外推。
+type RayTracedRefraction struct {
+	Profile RefractivityProfile
+	Step    float64 // 积分步长 (m)，默认 100
+}
+
+// rayState 射线在测站竖直面内的状态: 位置 (x 沿方位水平, y 沿测站天顶，原点为地心)、单位切向量与已积累的电磁路径长度
+type rayState struct{ x, y, tx, ty, l float64 }
+
+func (m *RayTracedRefraction) step() float64 {
+	if m.Step <= 0 {
+		return 100
+	}
+	return m.Step
+}
+
+// index 地心距 r 处的折射率 n 与梯度 dn/dr
+func (m *RayTracedRefraction) index(R, r float64) (n, dn float64) {
+	N, dN := m.Profile.Refractivity(r - R)
+	return 1 + N*1e-6, dN * 1e-6
+}
+
+// deriv 射线方程 dp/ds = t, dt/ds = (∇n - (∇n·t)t)/n, dl/ds = n
+func (m *RayTracedRefraction) deriv(R float64, s rayState) rayState {
+	r := math.Hypot(s.x, s.y)
+	n, dn := m.index(R, r)
+	gx, gy := dn*s.x/r, dn*s.y/r
+	gt := gx*s.tx + gy*s.ty
+	return rayState{s.tx, s.ty, (gx - gt*s.tx) / n, (gy - gt*s.ty) / n, n}
+}
+
+func (m *RayTracedRefraction) rk4(R float64, s rayState, h float64) rayState {
+	add := func(a, b rayState, f float64) rayState {
+		return rayState{a.x + f*b.x, a.y + f*b.y, a.tx + f*b.tx, a.ty + f*b.ty, a.l + f*b.l}
+	}
+	k1 := m.deriv(R, s)
+	k2 := m.deriv(R, add(s, k1, h/2))
+	k3 := m.deriv(R, add(s, k2, h/2))
+	k4 := m.deriv(R, add(s, k3, h))
+	out := add(add(add(add(s, k1, h/6), k2, h/3), k3, h/3), k4, h/6)
+	tn := math.Hypot(out.tx, out.ty)
+	out.tx, out.ty = out.tx/tn, out.ty/tn
+	return out
+}
+
+// trace 自测站 (0, R+h0) 以俯仰 el 发出射线并积分，srange > 0 时至视在距离达 srange 为止，
+// 否则至地心角达 theta 为止；射线无法到达该地心角时返回位于无穷远的状态。
+func (m *RayTracedRefraction) trace(R, h0, el, srange, theta float64) rayState {
+	se, ce := sincosd(el)
+	s := rayState{y: R + h0, tx: ce, ty: se}
+	ds := m.step()
+	byRange := srange > 0
+	for i := 0; i < 10000000; i++ {
+		r := math.Hypot(s.x, s.y)
+		if r < R/2 {
+			break
+		}
+		// 大气顶以上上行: 直线外推
+		if r-R > refractionTop && s.x*s.tx+s.y*s.ty > 0 {
+			lam := srange - s.l
+			if !byRange {
+				ux, uy := math.Sin(theta), math.Cos(theta)
+				den := s.tx*uy - s.ty*ux
+				lam = -(s.x*uy - s.y*ux) / den
+				if den <= 0 || lam < 0 {
+					return rayState{x: math.Inf(1), y: math.Inf(1), l: math.Inf(1)}
+				}
+			}
+			s.x, s.y, s.l = s.x+lam*s.tx, s.y+lam*s.ty, s.l+lam
+			return s
+		}
+		if byRange {
+			n, _ := m.index(R, r)
+			if rest := (srange - s.l) / n; rest <= ds {
+				return m.rk4(R, s, rest)
+			}
+		}
+		next := m.rk4(R, s, ds)
+		if !byRange {
+			a0, a1 := math.Atan2(s.x, s.y), math.Atan2(next.x, next.y)
+			if a1 >= theta {
+				f := (theta - a0) / (a1 - a0)
+				return rayState{s.x + f*(next.x-s.x), s.y + f*(next.y-s.y),
+					next.tx, next.ty, s.l + f*(next.l-s.l)}
+			}
+		}
+		s = next
+	}
+	return s
+}
+
+// Geometric 实现 RefractionModel
+func (m *RayTracedRefraction) Geometric(station Geodetic, az, el, srange float64) (gel, grange float64) {
+	if srange <= 0 {
+		return el, srange
+	}
+	R := RadiusOfCurvature(station.Latitude, az, station.Ell)
+	s := m.trace(R, station.Altitude, el, srange, 0)
+	return planeLook(R, station.Altitude, math.Hypot(s.x, s.y)-R, math.Atan2(s.x, s.y))
+}
+
+// Apparent 实现 RefractionModel，以 Illinois 试位法求解到达目标的视在俯仰
+func (m *RayTracedRefraction) Apparent(station Geodetic, az, el, srange float64) (ael, arange float64, err error) {
+	if srange <= 0 {
+		return el, srange, nil
+	}
+	R := RadiusOfCurvature(station.Latitude, az, station.Ell)
+	h0 := station.Altitude
+	h, theta := planeTarget(R, h0, el, srange)
+	if theta < 1e-12 {
+		// 天顶/天底方向无弯曲，仅积分超出的电磁路径
+		return el, srange + m.excess(h0, h, srange), nil
+	}
+	miss := func(e float64) (float64, rayState) {
+		s := m.trace(R, h0, e, 0, theta)
+		return math.Hypot(s.x, s.y) - (R + h), s
+	}
+	lo, hi := el-1, math.Min(el+5, 90)
+	flo, _ := miss(lo)
+	fhi, _ := miss(hi)
+	if flo > 0 || fhi < 0 {
+		return math.NaN(), math.NaN(), fmt.Errorf("refraction: no ray reaches target at elevation %g°, range %g m", el, srange)
+	}
+	side := 0
+	e, s := el, rayState{}
+	for i := 0; i < 100; i++ {
+		e = (lo + hi) / 2
+		if !math.IsInf(fhi, 0) {
+			e = (lo*fhi - hi*flo) / (fhi - flo)
+		}
+		var f float64
+		f, s = miss(e)
+		if math.Abs(f) < 1e-7 || hi-lo < 1e-12 {
+			break
+		}
+		if f > 0 {
+			hi, fhi = e, f
+			if side == 1 {
+				flo /= 2
+			}
+			side = 1
+		} else {
+			lo, flo = e, f
+			if side == -1 {
+				fhi /= 2
+			}
+			side = -1
+		}
+	}
+	return e, s.l, nil
+}
+
+// excess 竖直路径 h0 → h (直线长 srange) 上的电磁路径超出量 ∫(n - 1) ds，Simpson 积分
+func (m *RayTracedRefraction) excess(h0, h, srange float64) float64 {
+	lo, hi := math.Min(h0, h), math.Min(math.Max(h0, h), refractionTop)
+	if hi <= lo {
+		return 0
+	}
+	k := 2 * int(math.Ceil((hi-lo)/m.step()/2))
+	dh := (hi - lo) / float64(k)
+	sum := 0.0
+	for i := 0; i <= k; i++ {
+		N, _ := m.Profile.Refractivity(lo + float64(i)*dh)
+		w := 2.0
+		switch {
+		case i == 0 || i == k:
+			w = 1
+		case i%2 == 1:
+			w = 4
+		}
+		sum += w * N
+	}
+	return sum * dh / 3 * 1e-6
+}
+
+// ApparentToGeometric 将测站 station 处测得的视在 AER 修正为几何 AER，m 为 nil 时原样返回
+func ApparentToGeometric(m RefractionModel, station Geodetic, aer AER) AER {
+	if m != nil {
+		aer.Elevation, aer.SRange = m.Geometric(station, aer.Azimuth, aer.Elevation, aer.SRange)
+	}
+	return aer
+}
+
+// GeometricToApparent 由几何 AER 求测站 station 处应测得的视在 AER，m 为 nil 时原样返回
+// 视在距离与几何距离之差即折射距离偏差。
+func GeometricToApparent(m RefractionModel, station Geodetic, aer AER) (AER, error) {
+	if m == nil {
+		return aer, nil
+	}
+	el, r, err := m.Apparent(station, aer.Azimuth, aer.Elevation, aer.SRange)
+	if err != nil {
+		return AER{}, err
+	}
+	aer.Elevation, aer.SRange = el, r
+	return aer, nil
+}
+
+// ToECEFRefracted 将测站 ref 处测得的视在 AER 经折射模型 m 修正后转地心地固坐标系
+func (aer *AER) ToECEFRefracted(ref Geodetic, m RefractionModel) ECEF {
+	g := ApparentToGeometric(m, ref, *aer)
+	return g.ToECEF(ref)
+}
+
+// ToGeodeticRefracted 将测站 ref 处测得的视在 AER 经折射模型 m 修正后转大地坐标系
+func (aer *AER) ToGeodeticRefracted(ref Geodetic, m RefractionModel) Geodetic {
+	g := ApparentToGeometric(m, ref, *aer)
+	return g.ToGeodetic(ref)
+}
+
+// ToAERRefracted 目标在测站 ref 处的视在 (经折射) 站心坐标
+func (geo *Geodetic) ToAERRefracted(ref Geodetic, m RefractionModel) (AER, error) {
+	return GeometricToApparent(m, ref, geo.ToAER(ref))
+}
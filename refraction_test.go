@@ -0,0 +1,178 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+)
+
+// linearProfile 常梯度剖面，用于与等效地球模型比对
+type linearProfile float64
+
+func (g linearProfile) Refractivity(h float64) (float64, float64) { return float64(g) * h, float64(g) }
+
+func TestEffectiveEarthRefraction(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	radar := Geodetic{Latitude: 30, Longitude: 120, Altitude: 50, Ell: wgs}
+
+	// k = 1 为恒等变换
+	geo := EffectiveEarthRefraction{K: 1}
+	if el, r := geo.Geometric(radar, 40, 0.5, 150e3); math.Abs(el-0.5) > 1e-12 || math.Abs(r-150e3) > 1e-8 {
+		t.Errorf("k = 1: %g %g", el, r)
+	}
+
+	m := EffectiveEarthRefraction{}
+	for _, c := range [][2]float64{{0, 200e3}, {-0.2, 80e3}, {3, 300e3}, {45, 1000e3}, {90, 50e3}} {
+		gel, gr := m.Geometric(radar, 40, c[0], c[1])
+		if c[0] < 90 && gel >= c[0] {
+			t.Errorf("el %g: geometric %g not below apparent", c[0], gel)
+		}
+		ael, ar, err := m.Apparent(radar, 40, gel, gr)
+		if err != nil || math.Abs(ael-c[0]) > 1e-10 || math.Abs(ar-c[1]) > 1e-6 {
+			t.Errorf("round trip %v: %g %g %v", c, ael, ar, err)
+		}
+	}
+
+	// 常梯度 dN/dh = -(1 - 1/k)/R·10⁶ 剖面的射线追踪应与等效地球模型一致
+	// (等效地球按地心角缩放，目标较高时有约 10⁻⁴° 量级的模型差)
+	R := RadiusOfCurvature(radar.Latitude, 40, wgs)
+	rt := &RayTracedRefraction{Profile: linearProfile(-(1 - 1/DefaultKFactor) / R * 1e6)}
+	for _, c := range [][2]float64{{0, 150e3}, {0.5, 200e3}, {2, 100e3}} {
+		g1, r1 := m.Geometric(radar, 40, c[0], c[1])
+		g2, r2 := rt.Geometric(radar, 40, c[0], c[1])
+		if math.Abs(g1-g2) > 3e-4 || math.Abs(r1-r2) > 0.5 {
+			t.Errorf("%v: effective earth %.6f° %.3f m, ray traced %.6f° %.3f m", c, g1, r1, g2, r2)
+		}
+	}
+}
+
+func TestOpticalRefraction(t *testing.T) {
+	m := StandardOpticalRefraction
+	// 地平处视在蒙气差约 34′，天顶为 0
+	if el, _ := m.Geometric(Geodetic{}, 0, 0, 0); math.Abs(-el*60-34.5) > 0.2 {
+		t.Errorf("horizon: %.3f′", -el*60)
+	}
+	if el, _ := m.Geometric(Geodetic{}, 0, 90, 0); el != 90 {
+		t.Errorf("zenith: %g", el)
+	}
+	// Bennett 与 Saemundsson 互逆约 0.1′
+	for _, el := range []float64{0, 1, 5, 15, 45, 80} {
+		gel, _ := m.Geometric(Geodetic{}, 0, el, 1e9)
+		ael, r, _ := m.Apparent(Geodetic{}, 0, gel, 1e9)
+		if math.Abs(ael-el)*60 > 0.15 || r != 1e9 {
+			t.Errorf("el %g: %.4f′", el, (ael-el)*60)
+		}
+	}
+	// 气压为零时无折射，低温高压折射增大
+	if el, _ := (OpticalRefraction{}).Geometric(Geodetic{}, 0, 2, 0); el != 2 {
+		t.Errorf("vacuum: %g", el)
+	}
+	cold := OpticalRefraction{Pressure: 1040, Temperature: -20}
+	e1, _ := cold.Geometric(Geodetic{}, 0, 2, 0)
+	e2, _ := m.Geometric(Geodetic{}, 0, 2, 0)
+	if e1 >= e2 {
+		t.Errorf("cold %g vs standard %g", e1, e2)
+	}
+}
+
+func TestRayTracedRefraction(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	radar := Geodetic{Latitude: 40, Longitude: -105, Altitude: 0, Ell: wgs}
+	crpl := CRPLExponential{Ns: 313}
+	m := &RayTracedRefraction{Profile: crpl}
+
+	// CRPL 313: ce ≈ 0.1439 /km
+	if n, g := crpl.Refractivity(1000); math.Abs(n-313*math.Exp(-0.14386)) > 0.05 || math.Abs(g/n*1000+0.14386) > 1e-4 {
+		t.Errorf("CRPL at 1 km: %g %g", n, g)
+	}
+
+	// 天顶距离偏差 ∫N dh·10⁻⁶ = Ns/ce·10⁻⁶ ≈ 2.18 m
+	ce := math.Log(313/(313-7.32*math.Exp(0.005577*313))) / 1000
+	zenith := 313 / ce * 1e-6
+	el, r, err := m.Apparent(radar, 0, 90, 500e3)
+	if err != nil || el != 90 || math.Abs(r-500e3-zenith) > 1e-4 {
+		t.Errorf("zenith: %g %.6f (bias %.6f, want %.6f) %v", el, r, r-500e3, zenith, err)
+	}
+	if gel, gr := m.Geometric(radar, 0, 90, 500e3+zenith); math.Abs(gel-90) > 1e-9 || math.Abs(gr-500e3) > 1e-3 {
+		t.Errorf("zenith geometric: %g %.6f", gel, gr)
+	}
+
+	// 低仰角: 俯仰修正为正，距离偏差远大于天顶，随仰角升高而减小
+	prevDel, prevBias := math.Inf(1), math.Inf(1)
+	for _, ael := range []float64{0, 1, 3, 10, 30} {
+		gel, gr := m.Geometric(radar, 120, ael, 300e3)
+		del, bias := ael-gel, 300e3-gr
+		if del <= 0 || del >= prevDel || bias <= zenith || bias >= prevBias {
+			t.Errorf("el %g: Δel %.5f°, bias %.3f m", ael, del, bias)
+		}
+		prevDel, prevBias = del, bias
+		back, br, err := m.Apparent(radar, 120, gel, gr)
+		if err != nil || math.Abs(back-ael) > 1e-8 || math.Abs(br-300e3) > 1e-3 {
+			t.Errorf("round trip el %g: %.10f %.6f %v", ael, back, br, err)
+		}
+	}
+	// 地平处对大气外目标的弯曲量约 0.5°–0.8°
+	if gel, _ := m.Geometric(radar, 0, 0, 2000e3); -gel < 0.3 || -gel > 0.9 {
+		t.Errorf("horizon elevation error %.4f°", -gel)
+	}
+
+	// 地球遮挡的目标无视在方向
+	if _, _, err := m.Apparent(radar, 0, -10, 500e3); err == nil {
+		t.Error("expected error for target below the horizon")
+	}
+
+	// 由 CRPL 采样的分层剖面与 CRPL 一致
+	var hs, ns []float64
+	for h := 0.0; h <= 30e3; h += 200 {
+		n, _ := crpl.Refractivity(h)
+		hs, ns = append(hs, h), append(ns, n)
+	}
+	layered, err := NewLayeredRefractivity(hs, ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ml := &RayTracedRefraction{Profile: layered}
+	g1, r1 := m.Geometric(radar, 0, 1, 200e3)
+	g2, r2 := ml.Geometric(radar, 0, 1, 200e3)
+	if math.Abs(g1-g2) > 2e-4 || math.Abs(r1-r2) > 0.05 {
+		t.Errorf("layered vs CRPL: %.6f %.4f / %.6f %.4f", g1, r1, g2, r2)
+	}
+	if _, err := NewLayeredRefractivity([]float64{0, 0}, []float64{300, 200}); err == nil {
+		t.Error("expected error for non-increasing heights")
+	}
+	if _, err := NewLayeredRefractivity([]float64{0}, []float64{300}); err == nil {
+		t.Error("expected error for single layer")
+	}
+}
+
+func TestAERRefracted(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	radar := Geodetic{Latitude: 22.3, Longitude: 114.2, Altitude: 120, Ell: wgs}
+	target := Geodetic{Latitude: 23.5, Longitude: 115.1, Altitude: 9000, Ell: wgs}
+	m := &RayTracedRefraction{Profile: CRPLExponential{Ns: 340}}
+
+	app, err := target.ToAERRefracted(radar, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	geo := target.ToAER(radar)
+	if app.Elevation <= geo.Elevation || app.SRange <= geo.SRange || app.Azimuth != geo.Azimuth {
+		t.Errorf("apparent %+v vs geometric %+v", app, geo)
+	}
+	// 测量值经修正还原目标位置；未修正时高度偏高
+	back := app.ToGeodeticRefracted(radar, m)
+	if math.Abs(back.Latitude-target.Latitude) > 1e-8 || math.Abs(back.Longitude-target.Longitude) > 1e-8 ||
+		math.Abs(back.Altitude-target.Altitude) > 1e-2 {
+		t.Errorf("refracted round trip: %+v", back)
+	}
+	if raw := app.ToGeodetic(radar); raw.Altitude-target.Altitude < 100 {
+		t.Errorf("uncorrected altitude error only %.1f m", raw.Altitude-target.Altitude)
+	}
+	x, y, z := Geodetic2ECEF(target.Latitude, target.Longitude, target.Altitude, wgs)
+	if e := app.ToECEFRefracted(radar, m); math.Abs(e.X-x) > 1e-2 || math.Abs(e.Y-y) > 1e-2 || math.Abs(e.Z-z) > 1e-2 {
+		t.Errorf("ToECEFRefracted: %+v", e)
+	}
+	// nil 模型等价于直线传播
+	if e, plain := app.ToECEFRefracted(radar, nil), app.ToECEF(radar); e != plain {
+		t.Errorf("nil model: %+v vs %+v", e, plain)
+	}
+}
@@ -0,0 +1,163 @@
+package gomap3d
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================================
+// 恒向线 (斜航线)
+//
+// 恒向线与各子午线夹角恒定，在墨卡托投影上为直线。设等量纬度
+//   ψ(φ) = asinh(tanφ) - e·atanh(e·sinφ)
+// 子午线弧长 μ(φ)，则方位 α = atan2(Δλ, Δψ)，航程 s = Δμ / cos α。
+// Δψ 与 Δμ 均以和差化积形式直接计算差值，沿纬圈航行 (Δφ → 0) 时无相消误差。
+// 子午线弧长及其反算采用关于第三扁率 n 的 6 阶级数，反算再以牛顿迭代精化。
+// ============================================================
+
+// Rhumb 椭球恒向线解算器
+type Rhumb struct {
+	Ell *Ellipsoid
+
+	e, e2   float64
+	a1      float64    // 子午线弧长系数 A，1/4 子午线长 Aπ/2
+	c, d    [7]float64 // 弧长正、反算级数系数 (下标 1..6)
+	quarter float64
+}
+
+// NewRhumb 创建椭球 ell 上的恒向线解算器
+func NewRhumb(ell *Ellipsoid) *Rhumb {
+	n := ell.ThirdFlattening
+	n2 := n * n
+	n3, n4, n5, n6 := n2*n, n2*n2, n2*n2*n, n2*n2*n2
+	r := &Rhumb{Ell: ell, e: ell.Eccentricity}
+	r.e2 = r.e * r.e
+	r.a1 = ell.SemimajorAxis / (1 + n) * (1 + n2/4 + n4/64 + n6/256)
+	r.c = [7]float64{0,
+		-3*n/2 + 9*n3/16 - 3*n5/32,
+		15*n2/16 - 15*n4/32 + 135*n6/2048,
+		-35*n3/48 + 105*n5/256,
+		315*n4/512 - 189*n6/2048,
+		-693 * n5 / 1280,
+		1001 * n6 / 2048,
+	}
+	r.d = [7]float64{0,
+		3*n/2 - 27*n3/32 + 269*n5/512,
+		21*n2/16 - 55*n4/32 + 6759*n6/4096,
+		151*n3/96 - 417*n5/128,
+		1097*n4/512 - 15543*n6/2560,
+		8011 * n5 / 2560,
+		293393 * n6 / 61440,
+	}
+	r.quarter = r.a1 * math.Pi / 2
+	return r
+}
+
+// meridian 赤道至纬度 phi (弧度) 的子午线弧长 (m)
+func (r *Rhumb) meridian(phi float64) float64 {
+	m := phi
+	for k := 1; k <= 6; k++ {
+		m += r.c[k] * math.Sin(2*float64(k)*phi)
+	}
+	return r.a1 * m
+}
+
+// dMeridian μ(φ2) - μ(φ1)，sin 2kφ2 - sin 2kφ1 = 2cos k(φ1+φ2)·sin k(φ2-φ1)
+func (r *Rhumb) dMeridian(phi1, phi2 float64) float64 {
+	m := phi2 - phi1
+	for k := 1; k <= 6; k++ {
+		fk := float64(k)
+		m += r.c[k] * 2 * math.Cos(fk*(phi1+phi2)) * math.Sin(fk*(phi2-phi1))
+	}
+	return r.a1 * m
+}
+
+// latitude 由子午线弧长 (m) 反算纬度 (弧度)
+func (r *Rhumb) latitude(mu float64) float64 {
+	m := mu / r.a1
+	phi := m
+	for k := 1; k <= 6; k++ {
+		phi += r.d[k] * math.Sin(2*float64(k)*m)
+	}
+	for i := 0; i < 3; i++ {
+		s := math.Sin(phi)
+		w := 1 - r.e2*s*s
+		dm := r.Ell.SemimajorAxis * (1 - r.e2) / (w * math.Sqrt(w)) // 子午圈曲率半径
+		phi -= (r.meridian(phi) - mu) / dm
+	}
+	return phi
+}
+
+// dIsometric ψ(φ2) - ψ(φ1)，φ 为弧度；任一端为极点时为 ±Inf
+func (r *Rhumb) dIsometric(phi1, phi2 float64) float64 {
+	s1, c1 := math.Sincos(phi1)
+	s2, c2 := math.Sincos(phi2)
+	if math.Abs(phi1) == math.Pi/2 || math.Abs(phi2) == math.Pi/2 {
+		return math.Copysign(math.Inf(1), phi2-phi1)
+	}
+	// sinφ2 - sinφ1 = 2cos((φ1+φ2)/2)·sin((φ2-φ1)/2)
+	ds := 2 * math.Cos((phi1+phi2)/2) * math.Sin((phi2-phi1)/2)
+	// asinh x - asinh y = asinh(x√(1+y²) - y√(1+x²))，x = tanφ2, y = tanφ1
+	// atanh u - atanh v = atanh((u - v)/(1 - uv))
+	return math.Asinh(ds/(c1*c2)) - r.e*math.Atanh(r.e*ds/(1-r.e2*s1*s2))
+}
+
+// ratio Δμ/Δψ，Δφ → 0 时极限为纬圈半径 N cosφ
+func (r *Rhumb) ratio(phi1, phi2 float64) float64 {
+	if phi1 == phi2 {
+		s, c := math.Sincos(phi1)
+		return r.Ell.SemimajorAxis * c / math.Sqrt(1-r.e2*s*s)
+	}
+	return r.dMeridian(phi1, phi2) / r.dIsometric(phi1, phi2)
+}
+
+// Inverse 恒向线反算，返回航程 s12 (m) 与方位 azi12 (°)，经差取 (-180°, 180°] 内较短方向
+func (r *Rhumb) Inverse(lat1, lon1, lat2, lon2 float64) (s12, azi12 float64) {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dlon, _ := angDiff(lon1, lon2)
+	lam := dlon * math.Pi / 180
+	psi := r.dIsometric(phi1, phi2)
+	if math.IsInf(psi, 0) {
+		// 至极点: 沿子午线
+		return math.Abs(r.dMeridian(phi1, phi2)), atan2d(0, psi)
+	}
+	azi12 = atan2d(lam, psi)
+	return math.Hypot(lam, psi) * math.Abs(r.ratio(phi1, phi2)), azi12
+}
+
+// Direct 恒向线正算，由起点、方位 azi12 (°) 与航程 s12 (m) 求终点
+// 航线越过极点时返回错误。
+func (r *Rhumb) Direct(lat1, lon1, azi12, s12 float64) (lat2, lon2 float64, err error) {
+	phi1 := lat1 * math.Pi / 180
+	sa, ca := sincosd(azi12)
+	mu2 := r.meridian(phi1) + s12*ca
+	if math.Abs(mu2) > r.quarter*(1+1e-15) || (math.Abs(mu2) >= r.quarter && sa != 0) {
+		return 0, 0, fmt.Errorf("rhumb: course from (%g, %g) on azimuth %g passes the pole within %g m", lat1, lon1, azi12, s12)
+	}
+	var phi2 float64
+	if math.Abs(mu2) >= r.quarter {
+		phi2 = math.Copysign(math.Pi/2, mu2)
+	} else {
+		phi2 = r.latitude(mu2)
+	}
+	lat2 = phi2 * 180 / math.Pi
+	lon2 = lon1
+	if sa != 0 {
+		lon2 = angNormalize(lon1 + s12*sa/r.ratio(phi1, phi2)*180/math.Pi)
+	}
+	return lat2, lon2, nil
+}
+
+// RhumbInverse 到 other 的恒向线航程 s12 (m) 与方位 azi12 (°)，两点均视为 geo.Ell 上的坐标
+func (geo *Geodetic) RhumbInverse(other Geodetic) (s12, azi12 float64) {
+	return NewRhumb(geo.Ell).Inverse(geo.Latitude, geo.Longitude, other.Latitude, other.Longitude)
+}
+
+// RhumbDirect 沿方位 azi12 (°) 恒向航行 s12 (m) 后到达的点，高度不变
+func (geo *Geodetic) RhumbDirect(azi12, s12 float64) (Geodetic, error) {
+	lat, lon, err := NewRhumb(geo.Ell).Direct(geo.Latitude, geo.Longitude, azi12, s12)
+	if err != nil {
+		return Geodetic{}, err
+	}
+	return Geodetic{Latitude: lat, Longitude: lon, Altitude: geo.Altitude, Ell: geo.Ell}, nil
+}
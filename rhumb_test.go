@@ -0,0 +1,78 @@
+package gomap3d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRhumb(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	r := NewRhumb(wgs)
+	// GeographicLib Rhumb 文档算例
+	s12, azi12 := r.Inverse(40.6, -73.8, 51.6, -0.5)
+	if math.Abs(s12-5771083.38332803) > 1e-6 || math.Abs(azi12-77.7683897102557) > 1e-11 {
+		t.Errorf("JFK-LHR: s12 = %.8f, azi12 = %.13f", s12, azi12)
+	}
+	lat2, lon2, err := r.Direct(40.6, -73.8, 51, 5.5e6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(lat2-71.688899882813) > 1e-11 || math.Abs(lon2-0.2555198244234) > 1e-11 {
+		t.Errorf("direct: %.12f %.13f", lat2, lon2)
+	}
+
+	// 沿赤道与纬圈
+	if s12, azi12 := r.Inverse(0, 10, 0, 40); math.Abs(s12-wgs.SemimajorAxis*math.Pi/6) > 1e-8 || azi12 != 90 {
+		t.Errorf("equator: %.9f %g", s12, azi12)
+	}
+	e2 := wgs.Eccentricity * wgs.Eccentricity
+	par := wgs.SemimajorAxis * 0.5 / math.Sqrt(1-e2*0.75) * math.Pi / 2
+	if s12, azi12 := r.Inverse(60, 170, 60, -100); math.Abs(s12-par) > 1e-8 || azi12 != 90 {
+		t.Errorf("parallel across antimeridian: %.9f (want %.9f) %g", s12, par, azi12)
+	}
+	// 至极点: 1/4 子午线
+	if s12, azi12 := r.Inverse(0, 0, 90, 50); math.Abs(s12-10001965.729313) > 1e-5 || azi12 != 0 {
+		t.Errorf("to pole: %.6f %g", s12, azi12)
+	}
+	if lat, _, err := r.Direct(0, 0, 0, r.quarter); err != nil || math.Abs(lat-90) > 1e-9 {
+		t.Errorf("direct to pole: %g %v", lat, err)
+	}
+	if _, _, err := r.Direct(80, 0, 30, 2e6); err == nil {
+		t.Error("expected error for course through the pole")
+	}
+
+	// 往返，含近纬圈航向
+	rnd := rand.New(rand.NewSource(7))
+	for i := 0; i < 1000; i++ {
+		lat1 := rnd.Float64()*170 - 85
+		lon1 := rnd.Float64()*360 - 180
+		lat2 := rnd.Float64()*170 - 85
+		if i%4 == 0 {
+			lat2 = lat1 + (rnd.Float64()-0.5)*1e-9
+		}
+		lon2 := rnd.Float64()*360 - 180
+		s12, azi12 := r.Inverse(lat1, lon1, lat2, lon2)
+		la, lo, err := r.Direct(lat1, lon1, azi12, s12)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(la-lat2) > 1e-11 || math.Abs(angNormalize(lo-lon2)) > 1e-9 {
+			t.Fatalf("(%g, %g) → (%g, %g): s12 = %.6f azi = %.12f → (%.12f, %.12f)", lat1, lon1, lat2, lon2, s12, azi12, la, lo)
+		}
+	}
+
+	// 恒向线不短于大地线
+	a := Geodetic{Latitude: 40.6, Longitude: -73.8, Altitude: 5, Ell: wgs}
+	b := Geodetic{Latitude: 51.6, Longitude: -0.5, Ell: wgs}
+	rs, razi := a.RhumbInverse(b)
+	gs, _, _ := a.GeodesicInverse(b)
+	if rs <= gs {
+		t.Errorf("rhumb %.3f shorter than geodesic %.3f", rs, gs)
+	}
+	end, err := a.RhumbDirect(razi, rs)
+	if err != nil || math.Abs(end.Latitude-b.Latitude) > 1e-12 || math.Abs(end.Longitude-b.Longitude) > 1e-12 ||
+		end.Altitude != 5 || end.Ell != wgs {
+		t.Errorf("RhumbDirect: %+v %v", end, err)
+	}
+}
@@ -0,0 +1,941 @@
+package gomap3d
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ============================================================
+// SGP4 / SDP4 解析传播器
+//
+// 按 Vallado et al. (AIAA 2006-6753) 修订版实现，"improved" 运行模式，
+// 近地轨道 (周期 < 225 min) 用 SGP4，深空轨道用 SDP4（日月长期/长周期项、
+// 12 h 与 24 h 共振）。输出 TEME 系位置/速度，可经 TransformFrameVel
+// 或 TEME2ECEF 接入 ECEF / AER 链。内部单位为地球半径与分钟，接口单位为 m、m/s。
+// ============================================================
+
+// sgp4Gravity SGP4 重力常数
+type sgp4Gravity struct {
+	mu, radius, xke, tumin, j2, j3, j4, j3oj2 float64
+}
+
+// newSGP4Gravity 由 μ (km³/s²)、赤道半径 (km) 与 J2–J4 构造常数
+func newSGP4Gravity(mu, radius, j2, j3, j4 float64) sgp4Gravity {
+	xke := 60.0 / math.Sqrt(radius*radius*radius/mu)
+	return sgp4Gravity{
+		mu: mu, radius: radius, xke: xke, tumin: 1.0 / xke,
+		j2: j2, j3: j3, j4: j4, j3oj2: j3 / j2,
+	}
+}
+
+// wgs72 TLE 生成所用的 WGS-72 常数
+var wgs72 = newSGP4Gravity(398600.8, 6378.135, 0.001082616, -0.00000253881, -0.00000165597)
+
+// SGP4 传播错误
+var (
+	ErrSGP4Eccentricity     = errors.New("sgp4: mean eccentricity out of range")
+	ErrSGP4MeanMotion       = errors.New("sgp4: mean motion is not positive")
+	ErrSGP4PertEccentricity = errors.New("sgp4: perturbed eccentricity out of range")
+	ErrSGP4SemiLatusRectum  = errors.New("sgp4: semi-latus rectum is negative")
+	ErrSGP4Decayed          = errors.New("sgp4: satellite has decayed")
+)
+
+// SGP4 由一组 TLE 平根数初始化的传播器
+// 初始化后只读，Propagate 可并发调用。
+type SGP4 struct {
+	tle   TLE
+	grav  sgp4Gravity
+	epoch float64 // 历元，自 1949-12-31 0h 起的天数
+	deep  bool    // 深空 (SDP4)
+	isimp bool    // 简化阻力模型
+
+	// 平根数 (rad, rad/min)
+	ecco, inclo, nodeo, argpo, mo, noKozai, noUnkozai, bstar float64
+
+	// 近地项
+	aycof, con41, cc1, cc4, cc5, d2, d3, d4, delmo, eta       float64
+	argpdot, omgcof, sinmao, t2cof, t3cof, t4cof, t5cof       float64
+	x1mth2, x7thm1, mdot, nodedot, xlcof, xmcof, nodecf, gsto float64
+
+	// 深空项
+	irez                                                           int
+	d2201, d2211, d3210, d3222, d4410, d4422, d5220, d5232         float64
+	d5421, d5433, dedt, del1, del2, del3, didt, dmdt, dnodt, domdt float64
+	e3, ee2, peo, pgho, pho, pinco, plo, se2, se3                  float64
+	sgh2, sgh3, sgh4, sh2, sh3, si2, si3, sl2, sl3, sl4            float64
+	xfact, xgh2, xgh3, xgh4, xh2, xh3, xi2, xi3, xl2, xl3, xl4     float64
+	xlamo, zmol, zmos                                              float64
+}
+
+// NewSGP4 由 TLE 初始化 SGP4/SDP4 传播器（WGS-72 常数）
+func NewSGP4(tle *TLE) (*SGP4, error) {
+	const xpdotp = 1440.0 / (2.0 * math.Pi) // 圈/天 → rad/min
+
+	s := &SGP4{tle: *tle, grav: wgs72}
+	s.bstar = tle.BStar
+	s.ecco = tle.Eccentricity
+	s.inclo = tle.Inclination * math.Pi / 180
+	s.nodeo = tle.RAAN * math.Pi / 180
+	s.argpo = tle.ArgPerigee * math.Pi / 180
+	s.mo = tle.MeanAnomaly * math.Pi / 180
+	s.noKozai = tle.MeanMotion / xpdotp
+	s.epoch = juliandate(tle.Epoch) - 2433281.5
+
+	if err := s.init(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// TLE 返回传播器使用的根数
+func (s *SGP4) TLE() TLE { return s.tle }
+
+// Epoch 根数历元 (UTC)
+func (s *SGP4) Epoch() time.Time { return s.tle.Epoch }
+
+// DeepSpace 是否使用 SDP4 深空模型
+func (s *SGP4) DeepSpace() bool { return s.deep }
+
+// init 对应 Vallado sgp4init / initl
+func (s *SGP4) init() error {
+	const x2o3 = 2.0 / 3.0
+	const temp4 = 1.5e-12
+	g := &s.grav
+
+	if s.ecco < 0 || s.ecco >= 1 {
+		return ErrSGP4Eccentricity
+	}
+	if s.noKozai <= 0 {
+		return ErrSGP4MeanMotion
+	}
+
+	ss := 78.0/g.radius + 1.0
+	qzms2ttemp := (120.0 - 78.0) / g.radius
+	qzms2t := qzms2ttemp * qzms2ttemp * qzms2ttemp * qzms2ttemp
+
+	// initl: 由 Kozai 平均运动恢复 Brouwer 平均运动
+	eccsq := s.ecco * s.ecco
+	omeosq := 1.0 - eccsq
+	rteosq := math.Sqrt(omeosq)
+	cosio := math.Cos(s.inclo)
+	cosio2 := cosio * cosio
+	ak := math.Pow(g.xke/s.noKozai, x2o3)
+	d1 := 0.75 * g.j2 * (3.0*cosio2 - 1.0) / (rteosq * omeosq)
+	del := d1 / (ak * ak)
+	adel := ak * (1.0 - del*del - del*(1.0/3.0+134.0*del*del/81.0))
+	del = d1 / (adel * adel)
+	s.noUnkozai = s.noKozai / (1.0 + del)
+	ao := math.Pow(g.xke/s.noUnkozai, x2o3)
+	sinio := math.Sin(s.inclo)
+	po := ao * omeosq
+	con42 := 1.0 - 5.0*cosio2
+	s.con41 = -con42 - cosio2 - cosio2
+	posq := po * po
+	rp := ao * (1.0 - s.ecco)
+	s.gsto = greenwichsrt(s.epoch + 2433281.5)
+	if s.gsto < 0 {
+		s.gsto += tau
+	}
+
+	// 近地点低于 220 km 时使用简化阻力模型
+	s.isimp = rp < 220.0/g.radius+1.0
+	sfour := ss
+	qzms24 := qzms2t
+	perige := (rp - 1.0) * g.radius
+	if perige < 156.0 {
+		sfour = perige - 78.0
+		if perige < 98.0 {
+			sfour = 20.0
+		}
+		qzms24temp := (120.0 - sfour) / g.radius
+		qzms24 = qzms24temp * qzms24temp * qzms24temp * qzms24temp
+		sfour = sfour/g.radius + 1.0
+	}
+	pinvsq := 1.0 / posq
+
+	tsi := 1.0 / (ao - sfour)
+	s.eta = ao * s.ecco * tsi
+	etasq := s.eta * s.eta
+	eeta := s.ecco * s.eta
+	psisq := math.Abs(1.0 - etasq)
+	coef := qzms24 * math.Pow(tsi, 4.0)
+	coef1 := coef / math.Pow(psisq, 3.5)
+	cc2 := coef1 * s.noUnkozai * (ao*(1.0+1.5*etasq+eeta*(4.0+etasq)) +
+		0.375*g.j2*tsi/psisq*s.con41*(8.0+3.0*etasq*(8.0+etasq)))
+	s.cc1 = s.bstar * cc2
+	cc3 := 0.0
+	if s.ecco > 1.0e-4 {
+		cc3 = -2.0 * coef * tsi * g.j3oj2 * s.noUnkozai * sinio / s.ecco
+	}
+	s.x1mth2 = 1.0 - cosio2
+	s.cc4 = 2.0 * s.noUnkozai * coef1 * ao * omeosq *
+		(s.eta*(2.0+0.5*etasq) + s.ecco*(0.5+2.0*etasq) -
+			g.j2*tsi/(ao*psisq)*(-3.0*s.con41*(1.0-2.0*eeta+etasq*(1.5-0.5*eeta))+
+				0.75*s.x1mth2*(2.0*etasq-eeta*(1.0+etasq))*math.Cos(2.0*s.argpo)))
+	s.cc5 = 2.0 * coef1 * ao * omeosq * (1.0 + 2.75*(etasq+eeta) + eeta*etasq)
+	cosio4 := cosio2 * cosio2
+	temp1 := 1.5 * g.j2 * pinvsq * s.noUnkozai
+	temp2 := 0.5 * temp1 * g.j2 * pinvsq
+	temp3 := -0.46875 * g.j4 * pinvsq * pinvsq * s.noUnkozai
+	s.mdot = s.noUnkozai + 0.5*temp1*rteosq*s.con41 +
+		0.0625*temp2*rteosq*(13.0-78.0*cosio2+137.0*cosio4)
+	s.argpdot = -0.5*temp1*con42 + 0.0625*temp2*(7.0-114.0*cosio2+395.0*cosio4) +
+		temp3*(3.0-36.0*cosio2+49.0*cosio4)
+	xhdot1 := -temp1 * cosio
+	s.nodedot = xhdot1 + (0.5*temp2*(4.0-19.0*cosio2)+2.0*temp3*(3.0-7.0*cosio2))*cosio
+	xpidot := s.argpdot + s.nodedot
+	s.omgcof = s.bstar * cc3 * math.Cos(s.argpo)
+	if s.ecco > 1.0e-4 {
+		s.xmcof = -x2o3 * coef * s.bstar / eeta
+	}
+	s.nodecf = 3.5 * omeosq * xhdot1 * s.cc1
+	s.t2cof = 1.5 * s.cc1
+	// 倾角 180° 时避免除零
+	if math.Abs(cosio+1.0) > 1.5e-12 {
+		s.xlcof = -0.25 * g.j3oj2 * sinio * (3.0 + 5.0*cosio) / (1.0 + cosio)
+	} else {
+		s.xlcof = -0.25 * g.j3oj2 * sinio * (3.0 + 5.0*cosio) / temp4
+	}
+	s.aycof = -0.5 * g.j3oj2 * sinio
+	delmotemp := 1.0 + s.eta*math.Cos(s.mo)
+	s.delmo = delmotemp * delmotemp * delmotemp
+	s.sinmao = math.Sin(s.mo)
+	s.x7thm1 = 7.0*cosio2 - 1.0
+
+	// 深空初始化
+	if 2*math.Pi/s.noUnkozai >= 225.0 {
+		s.deep = true
+		s.isimp = true
+		dc := s.dscom(0.0, s.ecco, s.argpo, s.inclo, s.nodeo, s.noUnkozai)
+		s.dsinit(dc, xpidot, eccsq)
+	}
+
+	// 非简化模型的高阶阻力系数
+	if !s.isimp {
+		cc1sq := s.cc1 * s.cc1
+		s.d2 = 4.0 * ao * tsi * cc1sq
+		temp := s.d2 * tsi * s.cc1 / 3.0
+		s.d3 = (17.0*ao + sfour) * temp
+		s.d4 = 0.5 * temp * ao * tsi * (221.0*ao + 31.0*sfour) * s.cc1
+		s.t3cof = s.d2 + 2.0*cc1sq
+		s.t4cof = 0.25 * (3.0*s.d3 + s.cc1*(12.0*s.d2+10.0*cc1sq))
+		s.t5cof = 0.2 * (3.0*s.d4 + 12.0*s.cc1*s.d3 + 6.0*s.d2*s.d2 + 15.0*cc1sq*(2.0*s.d2+cc1sq))
+	}
+
+	_, _, err := s.propagate(0.0)
+	return err
+}
+
+// dscomTerms dscom 输出中供 dsinit 使用的中间量
+type dscomTerms struct {
+	sinim, cosim, emsq                   float64
+	s1, s2, s3, s4, s5                   float64
+	ss1, ss2, ss3, ss4, ss5              float64
+	sz1, sz3, sz11, sz13, sz21, sz23     float64
+	sz31, sz33                           float64
+	z1, z3, z11, z13, z21, z23, z31, z33 float64
+}
+
+// dscom 深空公共项：日月引力长周期系数 (Vallado dscom)
+func (s *SGP4) dscom(tc, ep, argpp, inclp, nodep, np float64) dscomTerms {
+	const (
+		zes    = 0.01675
+		zel    = 0.05490
+		c1ss   = 2.9864797e-6
+		c1l    = 4.7968065e-7
+		zsinis = 0.39785416
+		zcosis = 0.91744867
+		zcosgs = 0.1945905
+		zsings = -0.98088458
+	)
+	var o dscomTerms
+
+	nm := np
+	em := ep
+	snodm := math.Sin(nodep)
+	cnodm := math.Cos(nodep)
+	sinomm := math.Sin(argpp)
+	cosomm := math.Cos(argpp)
+	o.sinim = math.Sin(inclp)
+	o.cosim = math.Cos(inclp)
+	o.emsq = em * em
+	betasq := 1.0 - o.emsq
+	rtemsq := math.Sqrt(betasq)
+
+	s.peo, s.pinco, s.plo, s.pgho, s.pho = 0, 0, 0, 0, 0
+	day := s.epoch + 18261.5 + tc/1440.0
+	xnodce := math.Mod(4.5236020-9.2422029e-4*day, tau)
+	stem := math.Sin(xnodce)
+	ctem := math.Cos(xnodce)
+	zcosil := 0.91375164 - 0.03568096*ctem
+	zsinil := math.Sqrt(1.0 - zcosil*zcosil)
+	zsinhl := 0.089683511 * stem / zsinil
+	zcoshl := math.Sqrt(1.0 - zsinhl*zsinhl)
+	gam := 5.8351514 + 0.0019443680*day
+	zx := 0.39785416 * stem / zsinil
+	zy := zcoshl*ctem + 0.91744867*zsinhl*stem
+	zx = math.Atan2(zx, zy)
+	zx = gam + zx - xnodce
+	zcosgl := math.Cos(zx)
+	zsingl := math.Sin(zx)
+
+	// 先太阳项，再月球项
+	zcosg, zsing := zcosgs, zsings
+	zcosi, zsini := zcosis, zsinis
+	zcosh, zsinh := cnodm, snodm
+	cc := c1ss
+	xnoi := 1.0 / nm
+
+	var z2, z12, z22, z32, s6, s7 float64
+	var sz2, sz12, sz22, sz32, ss6, ss7 float64
+	for lsflg := 1; lsflg <= 2; lsflg++ {
+		a1 := zcosg*zcosh + zsing*zcosi*zsinh
+		a3 := -zsing*zcosh + zcosg*zcosi*zsinh
+		a7 := -zcosg*zsinh + zsing*zcosi*zcosh
+		a8 := zsing * zsini
+		a9 := zsing*zsinh + zcosg*zcosi*zcosh
+		a10 := zcosg * zsini
+		a2 := o.cosim*a7 + o.sinim*a8
+		a4 := o.cosim*a9 + o.sinim*a10
+		a5 := -o.sinim*a7 + o.cosim*a8
+		a6 := -o.sinim*a9 + o.cosim*a10
+
+		x1 := a1*cosomm + a2*sinomm
+		x2 := a3*cosomm + a4*sinomm
+		x3 := -a1*sinomm + a2*cosomm
+		x4 := -a3*sinomm + a4*cosomm
+		x5 := a5 * sinomm
+		x6 := a6 * sinomm
+		x7 := a5 * cosomm
+		x8 := a6 * cosomm
+
+		o.z31 = 12.0*x1*x1 - 3.0*x3*x3
+		z32 = 24.0*x1*x2 - 6.0*x3*x4
+		o.z33 = 12.0*x2*x2 - 3.0*x4*x4
+		o.z1 = 3.0*(a1*a1+a2*a2) + o.z31*o.emsq
+		z2 = 6.0*(a1*a3+a2*a4) + z32*o.emsq
+		o.z3 = 3.0*(a3*a3+a4*a4) + o.z33*o.emsq
+		o.z11 = -6.0*a1*a5 + o.emsq*(-24.0*x1*x7-6.0*x3*x5)
+		z12 = -6.0*(a1*a6+a3*a5) + o.emsq*(-24.0*(x2*x7+x1*x8)-6.0*(x3*x6+x4*x5))
+		o.z13 = -6.0*a3*a6 + o.emsq*(-24.0*x2*x8-6.0*x4*x6)
+		o.z21 = 6.0*a2*a5 + o.emsq*(24.0*x1*x5-6.0*x3*x7)
+		z22 = 6.0*(a4*a5+a2*a6) + o.emsq*(24.0*(x2*x5+x1*x6)-6.0*(x4*x7+x3*x8))
+		o.z23 = 6.0*a4*a6 + o.emsq*(24.0*x2*x6-6.0*x4*x8)
+		o.z1 = o.z1 + o.z1 + betasq*o.z31
+		z2 = z2 + z2 + betasq*z32
+		o.z3 = o.z3 + o.z3 + betasq*o.z33
+		o.s3 = cc * xnoi
+		o.s2 = -0.5 * o.s3 / rtemsq
+		o.s4 = o.s3 * rtemsq
+		o.s1 = -15.0 * em * o.s4
+		o.s5 = x1*x3 + x2*x4
+		s6 = x2*x3 + x1*x4
+		s7 = x2*x4 - x1*x3
+
+		if lsflg == 1 {
+			o.ss1, o.ss2, o.ss3, o.ss4, o.ss5, ss6, ss7 = o.s1, o.s2, o.s3, o.s4, o.s5, s6, s7
+			o.sz1, sz2, o.sz3 = o.z1, z2, o.z3
+			o.sz11, sz12, o.sz13 = o.z11, z12, o.z13
+			o.sz21, sz22, o.sz23 = o.z21, z22, o.z23
+			o.sz31, sz32, o.sz33 = o.z31, z32, o.z33
+			zcosg, zsing = zcosgl, zsingl
+			zcosi, zsini = zcosil, zsinil
+			zcosh = zcoshl*cnodm + zsinhl*snodm
+			zsinh = snodm*zcoshl - cnodm*zsinhl
+			cc = c1l
+		}
+	}
+
+	s.zmol = math.Mod(4.7199672+0.22997150*day-gam, tau)
+	s.zmos = math.Mod(6.2565837+0.017201977*day, tau)
+
+	// 太阳项
+	s.se2 = 2.0 * o.ss1 * ss6
+	s.se3 = 2.0 * o.ss1 * ss7
+	s.si2 = 2.0 * o.ss2 * sz12
+	s.si3 = 2.0 * o.ss2 * (o.sz13 - o.sz11)
+	s.sl2 = -2.0 * o.ss3 * sz2
+	s.sl3 = -2.0 * o.ss3 * (o.sz3 - o.sz1)
+	s.sl4 = -2.0 * o.ss3 * (-21.0 - 9.0*o.emsq) * zes
+	s.sgh2 = 2.0 * o.ss4 * sz32
+	s.sgh3 = 2.0 * o.ss4 * (o.sz33 - o.sz31)
+	s.sgh4 = -18.0 * o.ss4 * zes
+	s.sh2 = -2.0 * o.ss2 * sz22
+	s.sh3 = -2.0 * o.ss2 * (o.sz23 - o.sz21)
+
+	// 月球项
+	s.ee2 = 2.0 * o.s1 * s6
+	s.e3 = 2.0 * o.s1 * s7
+	s.xi2 = 2.0 * o.s2 * z12
+	s.xi3 = 2.0 * o.s2 * (o.z13 - o.z11)
+	s.xl2 = -2.0 * o.s3 * z2
+	s.xl3 = -2.0 * o.s3 * (o.z3 - o.z1)
+	s.xl4 = -2.0 * o.s3 * (-21.0 - 9.0*o.emsq) * zel
+	s.xgh2 = 2.0 * o.s4 * z32
+	s.xgh3 = 2.0 * o.s4 * (o.z33 - o.z31)
+	s.xgh4 = -18.0 * o.s4 * zel
+	s.xh2 = -2.0 * o.s2 * z22
+	s.xh3 = -2.0 * o.s2 * (o.z23 - o.z21)
+	return o
+}
+
+// dsinit 深空长期项与共振项初始化 (Vallado dsinit)
+func (s *SGP4) dsinit(o dscomTerms, xpidot, eccsq float64) {
+	const (
+		q22    = 1.7891679e-6
+		q31    = 2.1460748e-6
+		q33    = 2.2123015e-7
+		root22 = 1.7891679e-6
+		root44 = 7.3636953e-9
+		root54 = 2.1765803e-9
+		rptim  = 4.37526908801129966e-3 // 地球自转角速度 (rad/min)
+		root32 = 3.7393792e-7
+		root52 = 1.1428639e-7
+		x2o3   = 2.0 / 3.0
+		znl    = 1.5835218e-4
+		zns    = 1.19459e-5
+	)
+	nm := s.noUnkozai
+	em := s.ecco
+	inclm := s.inclo
+	cosim, sinim, emsq := o.cosim, o.sinim, o.emsq
+
+	// 共振类型: 1 为 24 h 同步，2 为 12 h 半同步
+	s.irez = 0
+	if nm < 0.0052359877 && nm > 0.0034906585 {
+		s.irez = 1
+	}
+	if nm >= 8.26e-3 && nm <= 9.24e-3 && em >= 0.5 {
+		s.irez = 2
+	}
+
+	// 太阳长期项
+	ses := o.ss1 * zns * o.ss5
+	sis := o.ss2 * zns * (o.sz11 + o.sz13)
+	sls := -zns * o.ss3 * (o.sz1 + o.sz3 - 14.0 - 6.0*emsq)
+	sghs := o.ss4 * zns * (o.sz31 + o.sz33 - 6.0)
+	shs := -zns * o.ss2 * (o.sz21 + o.sz23)
+	if inclm < 5.2359877e-2 || inclm > math.Pi-5.2359877e-2 {
+		shs = 0.0
+	}
+	if sinim != 0.0 {
+		shs = shs / sinim
+	}
+	sgs := sghs - cosim*shs
+
+	// 月球长期项
+	s.dedt = ses + o.s1*znl*o.s5
+	s.didt = sis + o.s2*znl*(o.z11+o.z13)
+	s.dmdt = sls - znl*o.s3*(o.z1+o.z3-14.0-6.0*emsq)
+	sghl := o.s4 * znl * (o.z31 + o.z33 - 6.0)
+	shll := -znl * o.s2 * (o.z21 + o.z23)
+	if inclm < 5.2359877e-2 || inclm > math.Pi-5.2359877e-2 {
+		shll = 0.0
+	}
+	s.domdt = sgs + sghl
+	s.dnodt = shs
+	if sinim != 0.0 {
+		s.domdt = s.domdt - cosim/sinim*shll
+		s.dnodt = s.dnodt + shll/sinim
+	}
+
+	if s.irez == 0 {
+		return
+	}
+	theta := s.gsto
+	aonv := math.Pow(nm/s.grav.xke, x2o3)
+
+	// 12 h 轨道地球引力共振
+	if s.irez == 2 {
+		cosisq := cosim * cosim
+		em = s.ecco
+		emsq = eccsq
+		eoc := em * emsq
+		g201 := -0.306 - (em-0.64)*0.440
+		var g211, g310, g322, g410, g422, g520, g521, g532, g533 float64
+		if em <= 0.65 {
+			g211 = 3.616 - 13.2470*em + 16.2900*emsq
+			g310 = -19.302 + 117.3900*em - 228.4190*emsq + 156.5910*eoc
+			g322 = -18.9068 + 109.7927*em - 214.6334*emsq + 146.5816*eoc
+			g410 = -41.122 + 242.6940*em - 471.0940*emsq + 313.9530*eoc
+			g422 = -146.407 + 841.8800*em - 1629.014*emsq + 1083.4350*eoc
+			g520 = -532.114 + 3017.977*em - 5740.032*emsq + 3708.2760*eoc
+		} else {
+			g211 = -72.099 + 331.819*em - 508.738*emsq + 266.724*eoc
+			g310 = -346.844 + 1582.851*em - 2415.925*emsq + 1246.113*eoc
+			g322 = -342.585 + 1554.908*em - 2366.899*emsq + 1215.972*eoc
+			g410 = -1052.797 + 4758.686*em - 7193.992*emsq + 3651.957*eoc
+			g422 = -3581.690 + 16178.110*em - 24462.770*emsq + 12422.520*eoc
+			if em > 0.715 {
+				g520 = -5149.66 + 29936.92*em - 54087.36*emsq + 31324.56*eoc
+			} else {
+				g520 = 1464.74 - 4664.75*em + 3763.64*emsq
+			}
+		}
+		if em < 0.7 {
+			g533 = -919.22770 + 4988.6100*em - 9064.7700*emsq + 5542.21*eoc
+			g521 = -822.71072 + 4568.6173*em - 8491.4146*emsq + 5337.524*eoc
+			g532 = -853.66600 + 4690.2500*em - 8624.7700*emsq + 5341.4*eoc
+		} else {
+			g533 = -37995.780 + 161616.52*em - 229838.20*emsq + 109377.94*eoc
+			g521 = -51752.104 + 218913.95*em - 309468.16*emsq + 146349.42*eoc
+			g532 = -40023.880 + 170470.89*em - 242699.48*emsq + 115605.82*eoc
+		}
+
+		sini2 := sinim * sinim
+		f220 := 0.75 * (1.0 + 2.0*cosim + cosisq)
+		f221 := 1.5 * sini2
+		f321 := 1.875 * sinim * (1.0 - 2.0*cosim - 3.0*cosisq)
+		f322 := -1.875 * sinim * (1.0 + 2.0*cosim - 3.0*cosisq)
+		f441 := 35.0 * sini2 * f220
+		f442 := 39.3750 * sini2 * sini2
+		f522 := 9.84375 * sinim * (sini2*(1.0-2.0*cosim-5.0*cosisq) +
+			0.33333333*(-2.0+4.0*cosim+6.0*cosisq))
+		f523 := sinim * (4.92187512*sini2*(-2.0-4.0*cosim+10.0*cosisq) +
+			6.56250012*(1.0+2.0*cosim-3.0*cosisq))
+		f542 := 29.53125 * sinim * (2.0 - 8.0*cosim + cosisq*(-12.0+8.0*cosim+10.0*cosisq))
+		f543 := 29.53125 * sinim * (-2.0 - 8.0*cosim + cosisq*(12.0+8.0*cosim-10.0*cosisq))
+
+		xno2 := nm * nm
+		ainv2 := aonv * aonv
+		temp1 := 3.0 * xno2 * ainv2
+		temp := temp1 * root22
+		s.d2201 = temp * f220 * g201
+		s.d2211 = temp * f221 * g211
+		temp1 = temp1 * aonv
+		temp = temp1 * root32
+		s.d3210 = temp * f321 * g310
+		s.d3222 = temp * f322 * g322
+		temp1 = temp1 * aonv
+		temp = 2.0 * temp1 * root44
+		s.d4410 = temp * f441 * g410
+		s.d4422 = temp * f442 * g422
+		temp1 = temp1 * aonv
+		temp = temp1 * root52
+		s.d5220 = temp * f522 * g520
+		s.d5232 = temp * f523 * g532
+		temp = 2.0 * temp1 * root54
+		s.d5421 = temp * f542 * g521
+		s.d5433 = temp * f543 * g533
+		s.xlamo = math.Mod(s.mo+s.nodeo+s.nodeo-theta-theta, tau)
+		s.xfact = s.mdot + s.dmdt + 2.0*(s.nodedot+s.dnodt-rptim) - s.noUnkozai
+	}
+
+	// 24 h 同步共振
+	if s.irez == 1 {
+		g200 := 1.0 + emsq*(-2.5+0.8125*emsq)
+		g310 := 1.0 + 2.0*emsq
+		g300 := 1.0 + emsq*(-6.0+6.60937*emsq)
+		f220 := 0.75 * (1.0 + cosim) * (1.0 + cosim)
+		f311 := 0.9375*sinim*sinim*(1.0+3.0*cosim) - 0.75*(1.0+cosim)
+		f330 := 1.0 + cosim
+		f330 = 1.875 * f330 * f330 * f330
+		s.del1 = 3.0 * nm * nm * aonv * aonv
+		s.del2 = 2.0 * s.del1 * f220 * g200 * q22
+		s.del3 = 3.0 * s.del1 * f330 * g300 * q33 * aonv
+		s.del1 = s.del1 * f311 * g310 * q31 * aonv
+		s.xlamo = math.Mod(s.mo+s.nodeo+s.argpo-theta, tau)
+		s.xfact = s.mdot + xpidot - rptim + s.dmdt + s.domdt + s.dnodt - s.noUnkozai
+	}
+}
+
+// dpper 日月长周期摄动 (Vallado dpper, Lyddane 修正)
+// init 为 true 时只计算初值，不修改根数。
+func (s *SGP4) dpper(t float64, init bool, ep, inclp, nodep, argpp, mp float64) (float64, float64, float64, float64, float64) {
+	const (
+		zns = 1.19459e-5
+		zes = 0.01675
+		znl = 1.5835218e-4
+		zel = 0.05490
+	)
+
+	zm := s.zmos + zns*t
+	if init {
+		zm = s.zmos
+	}
+	zf := zm + 2.0*zes*math.Sin(zm)
+	sinzf := math.Sin(zf)
+	f2 := 0.5*sinzf*sinzf - 0.25
+	f3 := -0.5 * sinzf * math.Cos(zf)
+	ses := s.se2*f2 + s.se3*f3
+	sis := s.si2*f2 + s.si3*f3
+	sls := s.sl2*f2 + s.sl3*f3 + s.sl4*sinzf
+	sghs := s.sgh2*f2 + s.sgh3*f3 + s.sgh4*sinzf
+	shs := s.sh2*f2 + s.sh3*f3
+
+	zm = s.zmol + znl*t
+	if init {
+		zm = s.zmol
+	}
+	zf = zm + 2.0*zel*math.Sin(zm)
+	sinzf = math.Sin(zf)
+	f2 = 0.5*sinzf*sinzf - 0.25
+	f3 = -0.5 * sinzf * math.Cos(zf)
+	sel := s.ee2*f2 + s.e3*f3
+	sil := s.xi2*f2 + s.xi3*f3
+	sll := s.xl2*f2 + s.xl3*f3 + s.xl4*sinzf
+	sghl := s.xgh2*f2 + s.xgh3*f3 + s.xgh4*sinzf
+	shll := s.xh2*f2 + s.xh3*f3
+
+	if init {
+		return ep, inclp, nodep, argpp, mp
+	}
+
+	pe := ses + sel - s.peo
+	pinc := sis + sil - s.pinco
+	pl := sls + sll - s.plo
+	pgh := sghs + sghl - s.pgho
+	ph := shs + shll - s.pho
+
+	inclp = inclp + pinc
+	ep = ep + pe
+	sinip := math.Sin(inclp)
+	cosip := math.Cos(inclp)
+
+	if inclp >= 0.2 {
+		ph = ph / sinip
+		pgh = pgh - cosip*ph
+		argpp = argpp + pgh
+		nodep = nodep + ph
+		mp = mp + pl
+	} else {
+		// 低倾角时用 Lyddane 变换避免奇异
+		sinop := math.Sin(nodep)
+		cosop := math.Cos(nodep)
+		alfdp := sinip * sinop
+		betdp := sinip * cosop
+		dalf := ph*cosop + pinc*cosip*sinop
+		dbet := -ph*sinop + pinc*cosip*cosop
+		alfdp = alfdp + dalf
+		betdp = betdp + dbet
+		nodep = math.Mod(nodep, tau)
+		xls := mp + argpp + cosip*nodep
+		dls := pl + pgh - pinc*nodep*sinip
+		xls = xls + dls
+		xnoh := nodep
+		nodep = math.Atan2(alfdp, betdp)
+		if math.Abs(xnoh-nodep) > math.Pi {
+			if nodep < xnoh {
+				nodep = nodep + tau
+			} else {
+				nodep = nodep - tau
+			}
+		}
+		mp = mp + pl
+		argpp = xls - mp - cosip*nodep
+	}
+	return ep, inclp, nodep, argpp, mp
+}
+
+// dspace 深空长期项与共振积分 (Vallado dspace)
+// 积分器每次从历元起按 720 min 步长积分，结果与调用顺序无关。
+func (s *SGP4) dspace(t float64, em, argpm, inclm, mm, nodem float64) (float64, float64, float64, float64, float64, float64) {
+	const (
+		fasx2 = 0.13130908
+		fasx4 = 2.8843198
+		fasx6 = 0.37448087
+		g22   = 5.7686396
+		g32   = 0.95240898
+		g44   = 1.8014998
+		g52   = 1.0508330
+		g54   = 4.4108898
+		rptim = 4.37526908801129966e-3
+		stepp = 720.0
+		stepn = -720.0
+		step2 = 259200.0
+	)
+
+	theta := math.Mod(s.gsto+t*rptim, tau)
+	em = em + s.dedt*t
+	inclm = inclm + s.didt*t
+	argpm = argpm + s.domdt*t
+	nodem = nodem + s.dnodt*t
+	mm = mm + s.dmdt*t
+	nm := s.noUnkozai
+
+	if s.irez == 0 {
+		return em, argpm, inclm, mm, nodem, nm
+	}
+
+	atime := 0.0
+	xni := s.noUnkozai
+	xli := s.xlamo
+	delt := stepn
+	if t > 0.0 {
+		delt = stepp
+	}
+
+	var xndt, xldot, xnddt, ft float64
+	for {
+		if s.irez != 2 {
+			// 近同步共振
+			xndt = s.del1*math.Sin(xli-fasx2) + s.del2*math.Sin(2.0*(xli-fasx4)) +
+				s.del3*math.Sin(3.0*(xli-fasx6))
+			xldot = xni + s.xfact
+			xnddt = s.del1*math.Cos(xli-fasx2) + 2.0*s.del2*math.Cos(2.0*(xli-fasx4)) +
+				3.0*s.del3*math.Cos(3.0*(xli-fasx6))
+			xnddt = xnddt * xldot
+		} else {
+			// 半日共振
+			xomi := s.argpo + s.argpdot*atime
+			x2omi := xomi + xomi
+			x2li := xli + xli
+			xndt = s.d2201*math.Sin(x2omi+xli-g22) + s.d2211*math.Sin(xli-g22) +
+				s.d3210*math.Sin(xomi+xli-g32) + s.d3222*math.Sin(-xomi+xli-g32) +
+				s.d4410*math.Sin(x2omi+x2li-g44) + s.d4422*math.Sin(x2li-g44) +
+				s.d5220*math.Sin(xomi+xli-g52) + s.d5232*math.Sin(-xomi+xli-g52) +
+				s.d5421*math.Sin(xomi+x2li-g54) + s.d5433*math.Sin(-xomi+x2li-g54)
+			xldot = xni + s.xfact
+			xnddt = s.d2201*math.Cos(x2omi+xli-g22) + s.d2211*math.Cos(xli-g22) +
+				s.d3210*math.Cos(xomi+xli-g32) + s.d3222*math.Cos(-xomi+xli-g32) +
+				s.d5220*math.Cos(xomi+xli-g52) + s.d5232*math.Cos(-xomi+xli-g52) +
+				2.0*(s.d4410*math.Cos(x2omi+x2li-g44)+s.d4422*math.Cos(x2li-g44)+
+					s.d5421*math.Cos(xomi+x2li-g54)+s.d5433*math.Cos(-xomi+x2li-g54))
+			xnddt = xnddt * xldot
+		}
+
+		if math.Abs(t-atime) < stepp {
+			ft = t - atime
+			break
+		}
+		xli = xli + xldot*delt + xndt*step2
+		xni = xni + xndt*delt + xnddt*step2
+		atime = atime + delt
+	}
+
+	nm = xni + xndt*ft + xnddt*ft*ft*0.5
+	xl := xli + xldot*ft + xndt*ft*ft*0.5
+	if s.irez != 1 {
+		mm = xl - 2.0*nodem + 2.0*theta
+	} else {
+		mm = xl - nodem - argpm + theta
+	}
+	return em, argpm, inclm, mm, nodem, nm
+}
+
+// propagate 自历元起 tsince 分钟的 TEME 状态 (km, km/s)
+func (s *SGP4) propagate(tsince float64) (r, v [3]float64, err error) {
+	const x2o3 = 2.0 / 3.0
+	const temp4 = 1.5e-12
+	g := &s.grav
+	vkmpersec := g.radius * g.xke / 60.0
+	t := tsince
+
+	// 长期重力与阻力项
+	xmdf := s.mo + s.mdot*t
+	argpdf := s.argpo + s.argpdot*t
+	nodedf := s.nodeo + s.nodedot*t
+	argpm := argpdf
+	mm := xmdf
+	t2 := t * t
+	nodem := nodedf + s.nodecf*t2
+	tempa := 1.0 - s.cc1*t
+	tempe := s.bstar * s.cc4 * t
+	templ := s.t2cof * t2
+
+	if !s.isimp {
+		delomg := s.omgcof * t
+		delmtemp := 1.0 + s.eta*math.Cos(xmdf)
+		delm := s.xmcof * (delmtemp*delmtemp*delmtemp - s.delmo)
+		temp := delomg + delm
+		mm = xmdf + temp
+		argpm = argpdf - temp
+		t3 := t2 * t
+		t4 := t3 * t
+		tempa = tempa - s.d2*t2 - s.d3*t3 - s.d4*t4
+		tempe = tempe + s.bstar*s.cc5*(math.Sin(mm)-s.sinmao)
+		templ = templ + s.t3cof*t3 + t4*(s.t4cof+t*s.t5cof)
+	}
+
+	nm := s.noUnkozai
+	em := s.ecco
+	inclm := s.inclo
+	if s.deep {
+		em, argpm, inclm, mm, nodem, nm = s.dspace(t, em, argpm, inclm, mm, nodem)
+	}
+
+	if nm <= 0.0 {
+		return r, v, ErrSGP4MeanMotion
+	}
+	am := math.Pow(g.xke/nm, x2o3) * tempa * tempa
+	nm = g.xke / math.Pow(am, 1.5)
+	em = em - tempe
+
+	if em >= 1.0 || em < -0.001 {
+		return r, v, ErrSGP4Eccentricity
+	}
+	if em < 1.0e-6 {
+		em = 1.0e-6
+	}
+	mm = mm + s.noUnkozai*templ
+	xlm := mm + argpm + nodem
+	nodem = math.Mod(nodem, tau)
+	argpm = math.Mod(argpm, tau)
+	xlm = math.Mod(xlm, tau)
+	mm = math.Mod(xlm-argpm-nodem, tau)
+
+	sinim := math.Sin(inclm)
+	cosim := math.Cos(inclm)
+
+	// 日月长周期项
+	ep := em
+	xincp := inclm
+	argpp := argpm
+	nodep := nodem
+	mp := mm
+	sinip := sinim
+	cosip := cosim
+	aycof, xlcof := s.aycof, s.xlcof
+	con41, x1mth2, x7thm1 := s.con41, s.x1mth2, s.x7thm1
+	if s.deep {
+		ep, xincp, nodep, argpp, mp = s.dpper(t, false, ep, xincp, nodep, argpp, mp)
+		if xincp < 0.0 {
+			xincp = -xincp
+			nodep = nodep + math.Pi
+			argpp = argpp - math.Pi
+		}
+		if ep < 0.0 || ep > 1.0 {
+			return r, v, ErrSGP4PertEccentricity
+		}
+
+		sinip = math.Sin(xincp)
+		cosip = math.Cos(xincp)
+		aycof = -0.5 * g.j3oj2 * sinip
+		if math.Abs(cosip+1.0) > 1.5e-12 {
+			xlcof = -0.25 * g.j3oj2 * sinip * (3.0 + 5.0*cosip) / (1.0 + cosip)
+		} else {
+			xlcof = -0.25 * g.j3oj2 * sinip * (3.0 + 5.0*cosip) / temp4
+		}
+	}
+
+	// 长周期项
+	axnl := ep * math.Cos(argpp)
+	temp := 1.0 / (am * (1.0 - ep*ep))
+	aynl := ep*math.Sin(argpp) + temp*aycof
+	xl := mp + argpp + nodep + temp*xlcof*axnl
+
+	// 解开普勒方程
+	u := math.Mod(xl-nodep, tau)
+	eo1 := u
+	tem5 := 9999.9
+	var sineo1, coseo1 float64
+	for ktr := 1; math.Abs(tem5) >= 1.0e-12 && ktr <= 10; ktr++ {
+		sineo1 = math.Sin(eo1)
+		coseo1 = math.Cos(eo1)
+		tem5 = 1.0 - coseo1*axnl - sineo1*aynl
+		tem5 = (u - aynl*coseo1 + axnl*sineo1 - eo1) / tem5
+		if math.Abs(tem5) >= 0.95 {
+			if tem5 > 0.0 {
+				tem5 = 0.95
+			} else {
+				tem5 = -0.95
+			}
+		}
+		eo1 = eo1 + tem5
+	}
+
+	// 短周期项
+	ecose := axnl*coseo1 + aynl*sineo1
+	esine := axnl*sineo1 - aynl*coseo1
+	el2 := axnl*axnl + aynl*aynl
+	pl := am * (1.0 - el2)
+	if pl < 0.0 {
+		return r, v, ErrSGP4SemiLatusRectum
+	}
+	rl := am * (1.0 - ecose)
+	rdotl := math.Sqrt(am) * esine / rl
+	rvdotl := math.Sqrt(pl) / rl
+	betal := math.Sqrt(1.0 - el2)
+	temp = esine / (1.0 + betal)
+	sinu := am / rl * (sineo1 - aynl - axnl*temp)
+	cosu := am / rl * (coseo1 - axnl + aynl*temp)
+	su := math.Atan2(sinu, cosu)
+	sin2u := (cosu + cosu) * sinu
+	cos2u := 1.0 - 2.0*sinu*sinu
+	temp = 1.0 / pl
+	temp1 := 0.5 * g.j2 * temp
+	temp2 := temp1 * temp
+
+	if s.deep {
+		cosisq := cosip * cosip
+		con41 = 3.0*cosisq - 1.0
+		x1mth2 = 1.0 - cosisq
+		x7thm1 = 7.0*cosisq - 1.0
+	}
+	mrt := rl*(1.0-1.5*temp2*betal*con41) + 0.5*temp1*x1mth2*cos2u
+	su = su - 0.25*temp2*x7thm1*sin2u
+	xnode := nodep + 1.5*temp2*cosip*sin2u
+	xinc := xincp + 1.5*temp2*cosip*sinip*cos2u
+	mvt := rdotl - nm*temp1*x1mth2*sin2u/g.xke
+	rvdot := rvdotl + nm*temp1*(x1mth2*cos2u+1.5*con41)/g.xke
+
+	// 方向向量
+	sinsu := math.Sin(su)
+	cossu := math.Cos(su)
+	snod := math.Sin(xnode)
+	cnod := math.Cos(xnode)
+	sini := math.Sin(xinc)
+	cosi := math.Cos(xinc)
+	xmx := -snod * cosi
+	xmy := cnod * cosi
+	ux := xmx*sinsu + cnod*cossu
+	uy := xmy*sinsu + snod*cossu
+	uz := sini * sinsu
+	vx := xmx*cossu - cnod*sinsu
+	vy := xmy*cossu - snod*sinsu
+	vz := sini * cossu
+
+	r = [3]float64{mrt * ux * g.radius, mrt * uy * g.radius, mrt * uz * g.radius}
+	v = [3]float64{
+		(mvt*ux + rvdot*vx) * vkmpersec,
+		(mvt*uy + rvdot*vy) * vkmpersec,
+		(mvt*uz + rvdot*vz) * vkmpersec,
+	}
+
+	if mrt < 1.0 {
+		return r, v, ErrSGP4Decayed
+	}
+	return r, v, nil
+}
+
+// PropagateMinutes 自历元起 tsince 分钟的 TEME 位置 (m) 与速度 (m/s)
+func (s *SGP4) PropagateMinutes(tsince float64) (x, y, z, vx, vy, vz float64, err error) {
+	r, v, err := s.propagate(tsince)
+	return r[0] * 1000, r[1] * 1000, r[2] * 1000, v[0] * 1000, v[1] * 1000, v[2] * 1000, err
+}
+
+// Propagate 时刻 t (UTC) 的 TEME 位置 (m) 与速度 (m/s)
+func (s *SGP4) Propagate(t time.Time) (x, y, z, vx, vy, vz float64, err error) {
+	return s.PropagateMinutes(t.Sub(s.tle.Epoch).Minutes())
+}
+
+// PropagateFrame 时刻 t 在参考系 to 中的位置 (m) 与速度 (m/s)，使用默认转换器
+func (s *SGP4) PropagateFrame(t time.Time, to Frame) (x, y, z, vx, vy, vz float64, err error) {
+	return s.PropagateFrameWith(t, to, DefaultTransformer())
+}
+
+// PropagateFrameWith 使用转换器 tr 将 TEME 状态转换到参考系 to
+func (s *SGP4) PropagateFrameWith(t time.Time, to Frame, tr *Transformer) (x, y, z, vx, vy, vz float64, err error) {
+	x, y, z, vx, vy, vz, err = s.Propagate(t)
+	if err != nil {
+		return
+	}
+	return tr.TransformFrameVel(x, y, z, vx, vy, vz, TEME, to, tr.fromUTC(t))
+}
+
+// ECEF 时刻 t 的地固坐标，可继续转大地坐标或站心 AER
+func (s *SGP4) ECEF(t time.Time, ell *Ellipsoid) (ECEF, error) {
+	x, y, z, _, _, _, err := s.PropagateFrame(t, ITRF)
+	return ECEF{X: x, Y: y, Z: z, Ell: ell}, err
+}
+
+// ECI 时刻 t 的地心惯性坐标
+// 经 ITRF 由默认转换器转回 ECI，与 ECI.ToECEF 互逆。
+func (s *SGP4) ECI(t time.Time, ell *Ellipsoid) (ECI, error) {
+	ecef, err := s.ECEF(t, ell)
+	if err != nil {
+		return ECI{}, err
+	}
+	return ecef.ToECI(t), nil
+}
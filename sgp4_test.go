@@ -0,0 +1,300 @@
+package gomap3d
+
+import (
+	"bufio"
+	"errors"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sgp4Ref Vallado tcppver.out 中的一行: tsince (min)、r (km)、v (km/s)
+type sgp4Ref struct {
+	tsince float64
+	r, v   [3]float64
+}
+
+// loadSGP4Refs 读取 tcppver.out 格式的参考结果，按卫星编号分组
+func loadSGP4Refs(t *testing.T, path string) map[int][]sgp4Ref {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	refs := map[int][]sgp4Ref{}
+	sat := 0
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 2 && fields[1] == "xx" {
+			sat, _ = strconv.Atoi(fields[0])
+			continue
+		}
+		if len(fields) < 7 {
+			continue
+		}
+		var v [7]float64
+		for i := range v {
+			if v[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+				t.Fatal(err)
+			}
+		}
+		refs[sat] = append(refs[sat], sgp4Ref{v[0], [3]float64{v[1], v[2], v[3]}, [3]float64{v[4], v[5], v[6]}})
+	}
+	return refs
+}
+
+// loadSGP4Ranges 读取 SGP4-VER.TLE 第 2 行 69 列之后的起止时刻与步长 (min)，按卫星编号分组
+func loadSGP4Ranges(t *testing.T, path string) map[int][3]float64 {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ranges := map[int][3]float64{}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "2 ") || len(line) <= 69 {
+			continue
+		}
+		fields := strings.Fields(line[69:])
+		if len(fields) < 3 {
+			continue
+		}
+		sat, err := strconv.Atoi(strings.TrimSpace(line[2:7]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r [3]float64
+		for i := range r {
+			if r[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ranges[sat] = r
+	}
+	return ranges
+}
+
+// sgp4VerEpochs 按 Vallado testcpp 驱动程序生成输出时刻: 先输出 t = 0，再从 start 按 step 至 stop
+func sgp4VerEpochs(r [3]float64) []float64 {
+	start, stop, step := r[0], r[1], r[2]
+	epochs := []float64{0}
+	ts := start
+	if math.Abs(ts) > 1e-8 {
+		ts -= step
+	}
+	for ts < stop {
+		ts = math.Min(ts+step, stop)
+		epochs = append(epochs, ts)
+	}
+	return epochs
+}
+
+func TestSGP4VerificationSet(t *testing.T) {
+	tles, err := LoadTLEs("test_data/SGP4-VER.TLE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranges := loadSGP4Ranges(t, "test_data/SGP4-VER.TLE")
+	refs := loadSGP4Refs(t, "test_data/tcppver.out")
+
+	checked, failed := 0, 0
+	check := func(s *SGP4, sat int, ref sgp4Ref) {
+		r, v, err := s.propagate(ref.tsince)
+		if err != nil {
+			t.Errorf("%05d t=%.1f: %v", sat, ref.tsince, err)
+			return
+		}
+		// 参考值保留 8 位小数 (km) 与 9 位小数 (km/s)
+		if d := vecDist(r, ref.r); d > 1e-7 {
+			t.Errorf("%05d t=%.1f position error = %.3e km", sat, ref.tsince, d)
+		}
+		if d := vecDist(v, ref.v); d > 1e-9 {
+			t.Errorf("%05d t=%.1f velocity error = %.3e km/s", sat, ref.tsince, d)
+		}
+		checked++
+	}
+	for _, tle := range tles {
+		sat, ref := tle.SatNum, refs[tle.SatNum]
+		s, err := NewSGP4(tle)
+		if err != nil {
+			// 初始化即失败的错误码算例，参考输出至多只有 t = 0
+			if len(ref) > 1 {
+				t.Errorf("%05d: %v, but %d reference states", sat, err, len(ref))
+			}
+			failed += len(ref)
+			continue
+		}
+		r, ok := ranges[sat]
+		if !ok {
+			// 子集中仅有部分参考输出，逐条核对
+			for _, rf := range ref {
+				check(s, sat, rf)
+			}
+			continue
+		}
+		// 驱动程序遇到错误即停止输出: 参考输出之后的下一时刻必须返回错误码
+		epochs := sgp4VerEpochs(r)
+		if len(ref) > len(epochs) {
+			t.Errorf("%05d: %d reference states for %d epochs", sat, len(ref), len(epochs))
+			continue
+		}
+		for i, ts := range epochs {
+			if i == len(ref) {
+				if _, _, err := s.propagate(ts); err == nil {
+					t.Errorf("%05d t=%.1f: reference output ends but propagation succeeds", sat, ts)
+				}
+				break
+			}
+			if math.Abs(ref[i].tsince-ts) > 1e-6 {
+				t.Errorf("%05d: reference epoch %.4f, want %.4f", sat, ref[i].tsince, ts)
+				break
+			}
+			check(s, sat, ref[i])
+		}
+	}
+	total := 0
+	for _, ref := range refs {
+		total += len(ref)
+	}
+	if checked+failed != total || total == 0 {
+		t.Errorf("checked %d of %d reference states", checked, total)
+	}
+}
+
+func TestSGP4DeepSpace(t *testing.T) {
+	tles, err := LoadTLEs("test_data/SGP4-VER.TLE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]struct {
+		deep bool
+		irez int
+	}{
+		5:     {false, 0},
+		6251:  {false, 0},
+		8195:  {true, 2},
+		9880:  {true, 2},
+		14128: {true, 1},
+		11801: {true, 0},
+	}
+	for _, tle := range tles {
+		s, err := NewSGP4(tle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := want[tle.SatNum]
+		if s.DeepSpace() != w.deep || s.irez != w.irez {
+			t.Errorf("%05d: deep=%v irez=%d, want %v %d", tle.SatNum, s.DeepSpace(), s.irez, w.deep, w.irez)
+		}
+	}
+
+	// 24 h 共振：积分器从历元重新积分，调用顺序不影响结果
+	s, _ := NewSGP4(tles[4])
+	r1, _, _ := s.propagate(2880)
+	s.propagate(-1440)
+	s.propagate(5000)
+	r2, _, _ := s.propagate(2880)
+	if r1 != r2 {
+		t.Errorf("resonance integration depends on call history: %v vs %v", r1, r2)
+	}
+	// 地球同步轨道半径约 42164 km
+	if rn := math.Sqrt(r1[0]*r1[0] + r1[1]*r1[1] + r1[2]*r1[2]); math.Abs(rn-42164) > 500 {
+		t.Errorf("14128 radius = %.1f km", rn)
+	}
+}
+
+func TestSGP4Errors(t *testing.T) {
+	tle, err := ParseTLE(
+		"1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985",
+		"2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bad := *tle
+	bad.Eccentricity = 1.2
+	if _, err := NewSGP4(&bad); !errors.Is(err, ErrSGP4Eccentricity) {
+		t.Errorf("eccentricity 1.2: err = %v", err)
+	}
+
+	// 大阻力目标最终再入
+	heavy := *tle
+	heavy.BStar = 0.05
+	s, err := NewSGP4(&heavy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, _, _, err := s.PropagateMinutes(30 * 1440); err == nil {
+		t.Error("expected propagation error for decayed satellite")
+	}
+}
+
+func TestSGP4ToECEFAndAER(t *testing.T) {
+	tle, err := ParseTLE(
+		"1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753",
+		"2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSGP4(tle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ell, _ := NewEllipsoid("wgs84")
+	tUTC := s.Epoch().Add(360 * time.Minute)
+
+	// Propagate(t) 与 PropagateMinutes 一致，单位为 m
+	x, y, z, vx, vy, vz, err := s.Propagate(tUTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [3]float64{-7154031.20202, -3783176.82504, -3536194.12294}
+	if d := vecDist([3]float64{x, y, z}, want); d > 1e-3 {
+		t.Errorf("TEME position error = %.3e m", d)
+	}
+
+	// TEME → ITRF 与帧转换一致
+	xe, ye, ze, vxe, vye, vze, err := s.PropagateFrame(tUTC, ITRF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x2, y2, z2, vx2, vy2, vz2, _ := TransformFrameVel(x, y, z, vx, vy, vz, TEME, ITRF, tUTC)
+	if vecDist([3]float64{xe, ye, ze}, [3]float64{x2, y2, z2}) > 1e-6 ||
+		vecDist([3]float64{vxe, vye, vze}, [3]float64{vx2, vy2, vz2}) > 1e-9 {
+		t.Error("PropagateFrame differs from TransformFrameVel")
+	}
+	// 旋转不改变半径
+	if d := math.Abs(math.Hypot(math.Hypot(x, y), z) - math.Hypot(math.Hypot(xe, ye), ze)); d > 1e-6 {
+		t.Errorf("radius changed by %.3e m", d)
+	}
+
+	// 星下点正下方的测站看到目标在天顶附近
+	ecef, err := s.ECEF(tUTC, ell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	geo := ecef.ToGeodetic()
+	station := Geodetic{Latitude: geo.Latitude, Longitude: geo.Longitude, Altitude: 0, Ell: ell}
+	aer := ecef.ToAER(station)
+	if aer.Elevation < 89.9 || math.Abs(aer.SRange-geo.Altitude) > 1 {
+		t.Errorf("sub-satellite AER = %+v, altitude %.1f", aer, geo.Altitude)
+	}
+
+	// ECI 位置再转 ECEF 与 ITRF 结果一致
+	eci, err := s.ECI(tUTC, ell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back := eci.ToECEF()
+	if d := vecDist([3]float64{back.X, back.Y, back.Z}, [3]float64{ecef.X, ecef.Y, ecef.Z}); d > 1e-3 {
+		t.Errorf("ECI → ECEF differs from TEME → ITRF by %.3e m", d)
+	}
+}
@@ -0,0 +1,290 @@
+package gomap3d
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================
+// 时间尺度: UTC, TAI, TT, UT1, GPS, TDB
+//
+// 换算关系 (以 TAI 为中转):
+//   TAI = UTC + ΔAT           (闰秒表)
+//   TT  = TAI + 32.184 s
+//   GPS = TAI - 19 s
+//   UT1 = UTC + (UT1-UTC)     (EOP，未设置时取 0)
+//   TDB ≈ TT + 0.001657 sin(g) + 0.000014 sin(2g)
+// 参考: IERS Conventions 2010, Chapter 10; USNO Circular 179
+// ============================================================
+
+// TimeScale 时间尺度
+type TimeScale int
+
+const (
+	UTC TimeScale = iota // 协调世界时
+	TAI                  // 国际原子时
+	TT                   // 地球时
+	UT1                  // 世界时
+	GPS                  // GPS 时
+	TDB                  // 质心力学时
+)
+
+func (s TimeScale) String() string {
+	switch s {
+	case UTC:
+		return "UTC"
+	case TAI:
+		return "TAI"
+	case TT:
+		return "TT"
+	case UT1:
+		return "UT1"
+	case GPS:
+		return "GPS"
+	case TDB:
+		return "TDB"
+	}
+	return fmt.Sprintf("TimeScale(%d)", int(s))
+}
+
+const (
+	// ttMinusTAI TT-TAI (s)
+	ttMinusTAI = 32.184
+	// taiMinusGPS TAI-GPS (s)
+	taiMinusGPS = 19.0
+)
+
+// Epoch 带时间尺度标记的时刻
+// T 为该时间尺度下的钟面读数（按 UTC 时区的日历字段解释）。
+type Epoch struct {
+	T     time.Time
+	Scale TimeScale
+}
+
+// NewEpoch 创建指定时间尺度的时刻
+func NewEpoch(t time.Time, scale TimeScale) Epoch {
+	return Epoch{T: t.UTC(), Scale: scale}
+}
+
+// JD 钟面读数对应的儒略日（同一时间尺度）
+func (ep Epoch) JD() float64 {
+	return juliandate(ep.T)
+}
+
+// UTC 换算为 UTC 时刻，可直接用于 ECI2ECEF 等以 UTC 为输入的函数
+func (ep Epoch) UTC() time.Time {
+	return ep.To(UTC).T
+}
+
+// To 换算到另一时间尺度，UT1 使用默认转换器的 EOP
+func (ep Epoch) To(scale TimeScale) Epoch {
+	return ep.to(scale, DefaultTransformer().opts.EOP)
+}
+
+// to 换算到另一时间尺度，UT1 使用 EOP 表 tab
+func (ep Epoch) to(scale TimeScale, tab *EOPTable) Epoch {
+	if ep.Scale == scale {
+		return ep
+	}
+	tai := ep.toTAI(tab)
+	return Epoch{T: fromTAI(tai, scale, tab), Scale: scale}
+}
+
+// toTAI 换算为 TAI 钟面读数
+func (ep Epoch) toTAI(tab *EOPTable) time.Time {
+	t := ep.T
+	switch ep.Scale {
+	case TAI:
+		return t
+	case UTC:
+		return t.Add(seconds(TAIMinusUTC(t)))
+	case TT:
+		return t.Add(-seconds(ttMinusTAI))
+	case GPS:
+		return t.Add(seconds(taiMinusGPS))
+	case UT1:
+		// UTC = UT1 - (UT1-UTC)，EOP 随时间变化缓慢，以 UT1 时刻查表即可
+		utc := t.Add(-seconds(eopFrom(tab, t).UT1UTC))
+		return utc.Add(seconds(TAIMinusUTC(utc)))
+	case TDB:
+		// TT = TDB - (TDB-TT)，周期项变化缓慢，以 TDB 代替 TT 求值
+		tt := t.Add(-seconds(tdbMinusTT(juliandate(t))))
+		return tt.Add(-seconds(ttMinusTAI))
+	}
+	return t
+}
+
+// fromTAI 由 TAI 钟面读数换算到指定时间尺度
+func fromTAI(tai time.Time, scale TimeScale, tab *EOPTable) time.Time {
+	switch scale {
+	case TAI:
+		return tai
+	case UTC:
+		return taiToUTC(tai)
+	case TT:
+		return tai.Add(seconds(ttMinusTAI))
+	case GPS:
+		return tai.Add(-seconds(taiMinusGPS))
+	case UT1:
+		utc := taiToUTC(tai)
+		return utc.Add(seconds(eopFrom(tab, utc).UT1UTC))
+	case TDB:
+		tt := tai.Add(seconds(ttMinusTAI))
+		return tt.Add(seconds(tdbMinusTT(juliandate(tt))))
+	}
+	return tai
+}
+
+// taiToUTC TAI → UTC，闰秒表按 UTC 索引，迭代一次即可收敛
+func taiToUTC(tai time.Time) time.Time {
+	utc := tai.Add(-seconds(TAIMinusUTC(tai)))
+	return tai.Add(-seconds(TAIMinusUTC(utc)))
+}
+
+// tdbMinusTT TDB-TT (s)，jdTT 为 TT 儒略日
+// 精度约 10 µs，参考 USNO Circular 179 式 (2.6)
+func tdbMinusTT(jdTT float64) float64 {
+	g := (357.53 + 0.98560028*(jdTT-2451545.0)) * math.Pi / 180
+	return 0.001657*math.Sin(g) + 0.000014*math.Sin(2*g)
+}
+
+// seconds 浮点秒转 time.Duration
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Round(s * 1e9))
+}
+
+// ---- 闰秒表 ----
+
+// LeapSecond 闰秒表项：自 Effective (UTC) 起 TAI-UTC = TAIUTC 秒
+type LeapSecond struct {
+	Effective time.Time
+	TAIUTC    float64
+}
+
+func leap(y int, m time.Month, d int, taiutc float64) LeapSecond {
+	return LeapSecond{time.Date(y, m, d, 0, 0, 0, 0, time.UTC), taiutc}
+}
+
+// builtinLeapSeconds 内置闰秒表 (IERS Bulletin C，截至 2017-01-01)
+var builtinLeapSeconds = []LeapSecond{
+	leap(1972, 1, 1, 10),
+	leap(1972, 7, 1, 11),
+	leap(1973, 1, 1, 12),
+	leap(1974, 1, 1, 13),
+	leap(1975, 1, 1, 14),
+	leap(1976, 1, 1, 15),
+	leap(1977, 1, 1, 16),
+	leap(1978, 1, 1, 17),
+	leap(1979, 1, 1, 18),
+	leap(1980, 1, 1, 19),
+	leap(1981, 7, 1, 20),
+	leap(1982, 7, 1, 21),
+	leap(1983, 7, 1, 22),
+	leap(1985, 7, 1, 23),
+	leap(1988, 1, 1, 24),
+	leap(1990, 1, 1, 25),
+	leap(1991, 1, 1, 26),
+	leap(1992, 7, 1, 27),
+	leap(1993, 7, 1, 28),
+	leap(1994, 7, 1, 29),
+	leap(1996, 1, 1, 30),
+	leap(1997, 7, 1, 31),
+	leap(1999, 1, 1, 32),
+	leap(2006, 1, 1, 33),
+	leap(2009, 1, 1, 34),
+	leap(2012, 7, 1, 35),
+	leap(2015, 7, 1, 36),
+	leap(2017, 1, 1, 37),
+}
+
+// leapSeconds 当前使用的闰秒表，由 SetLeapSeconds 原子替换，可与换算并发
+var leapSeconds atomic.Pointer[[]LeapSecond]
+
+func init() {
+	leapSeconds.Store(&builtinLeapSeconds)
+}
+
+// TAIMinusUTC 返回 UTC 时刻 t 的 TAI-UTC (s)
+// 1972 年之前不支持（UTC 采用变速秒），按 10 s 处理。
+func TAIMinusUTC(t time.Time) float64 {
+	table := *leapSeconds.Load()
+	i := sort.Search(len(table), func(i int) bool {
+		return table[i].Effective.After(t)
+	})
+	if i == 0 {
+		return table[0].TAIUTC
+	}
+	return table[i-1].TAIUTC
+}
+
+// LoadLeapSeconds 从本地 leap-seconds.list 文件读取闰秒表
+func LoadLeapSeconds(path string) ([]LeapSecond, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseLeapSeconds(f)
+}
+
+// ntpEpoch NTP 时间戳起点
+var ntpEpoch = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ParseLeapSeconds 解析 IETF/IERS leap-seconds.list 格式
+// 数据行: NTP 秒 (自 1900-01-01)  TAI-UTC  # 注释；以 # 开头的行为注释。
+func ParseLeapSeconds(r io.Reader) ([]LeapSecond, error) {
+	var out []LeapSecond
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		s := sc.Text()
+		if i := strings.IndexByte(s, '#'); i >= 0 {
+			s = s[:i]
+		}
+		fields := strings.Fields(s)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("leap-seconds line %d: expected 2 fields", line)
+		}
+		ntp, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("leap-seconds line %d: %v", line, err)
+		}
+		dat, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("leap-seconds line %d: %v", line, err)
+		}
+		out = append(out, LeapSecond{ntpEpoch.Add(time.Duration(ntp) * time.Second), dat})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no leap seconds found")
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Effective.Before(out[j].Effective) })
+	return out, nil
+}
+
+// SetLeapSeconds 替换当前使用的闰秒表，传入 nil 恢复内置表
+// 表按生效时刻排序后保存副本，之后修改 table 不影响换算；可与换算并发调用。
+func SetLeapSeconds(table []LeapSecond) {
+	if len(table) == 0 {
+		leapSeconds.Store(&builtinLeapSeconds)
+		return
+	}
+	t := append([]LeapSecond(nil), table...)
+	sort.Slice(t, func(i, j int) bool { return t[i].Effective.Before(t[j].Effective) })
+	leapSeconds.Store(&t)
+}
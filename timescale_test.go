@@ -0,0 +1,167 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTAIMinusUTC(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want float64
+	}{
+		{time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), 10},
+		{time.Date(1999, 6, 1, 0, 0, 0, 0, time.UTC), 32},
+		{time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC), 36},
+		{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 37},
+		{time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC), 37},
+	}
+	for _, tc := range tests {
+		if got := TAIMinusUTC(tc.t); got != tc.want {
+			t.Errorf("TAI-UTC(%v) = %v, want %v", tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestEpochOffsets(t *testing.T) {
+	utc := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	ep := NewEpoch(utc, UTC)
+
+	tests := []struct {
+		scale TimeScale
+		want  float64 // 相对 UTC 的偏移 (s)
+	}{
+		{TAI, 37},
+		{TT, 69.184},
+		{GPS, 18},
+		{UT1, 0}, // 未设置 EOP
+	}
+	for _, tc := range tests {
+		got := ep.To(tc.scale).T.Sub(utc).Seconds()
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%v - UTC = %.6f s, want %.6f s", tc.scale, got, tc.want)
+		}
+	}
+
+	// TDB-TT 周期项振幅约 1.7 ms
+	d := ep.To(TDB).T.Sub(ep.To(TT).T).Seconds()
+	if math.Abs(d) > 0.0017 {
+		t.Errorf("TDB-TT = %.6f s, exceeds 1.7 ms", d)
+	}
+}
+
+func TestEpochJ2000(t *testing.T) {
+	// J2000.0 = 2000-01-01 12:00:00 TT = 11:58:55.816 UTC
+	ep := NewEpoch(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC), TT)
+	want := time.Date(2000, 1, 1, 11, 58, 55, 816e6, time.UTC)
+	if got := ep.UTC(); !got.Equal(want) {
+		t.Errorf("J2000.0 UTC = %v, want %v", got, want)
+	}
+	if jd := ep.JD(); jd != 2451545.0 {
+		t.Errorf("J2000.0 JD(TT) = %.9f", jd)
+	}
+}
+
+func TestEpochRoundtrip(t *testing.T) {
+	scales := []TimeScale{UTC, TAI, TT, UT1, GPS, TDB}
+	times := []time.Time{
+		time.Date(1995, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2016, 12, 31, 23, 59, 0, 0, time.UTC),
+		time.Date(2017, 1, 1, 0, 0, 30, 0, time.UTC),
+		time.Date(2026, 6, 3, 7, 42, 16, 0, time.UTC),
+	}
+	for _, tt := range times {
+		for _, from := range scales {
+			for _, to := range scales {
+				ep := NewEpoch(tt, from)
+				back := ep.To(to).To(from)
+				if d := back.T.Sub(tt); d < -time.Microsecond || d > time.Microsecond {
+					t.Errorf("%v → %v → %v at %v: error %v", from, to, from, tt, d)
+				}
+			}
+		}
+	}
+}
+
+func TestEpochUT1WithEOP(t *testing.T) {
+	tab, err := LoadFinals2000A("test_data/finals2000A_sample.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetEOP(tab)
+	defer SetEOP(nil)
+
+	utc := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	got := NewEpoch(utc, UTC).To(UT1).T.Sub(utc).Seconds()
+	if math.Abs(got-0.0055024) > 1e-9 {
+		t.Errorf("UT1-UTC = %.9f, want 0.0055024", got)
+	}
+}
+
+func TestParseLeapSeconds(t *testing.T) {
+	table, err := LoadLeapSeconds("test_data/leap-seconds.list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table) != 6 {
+		t.Fatalf("entries = %d, want 6", len(table))
+	}
+	first := table[0]
+	if !first.Effective.Equal(time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC)) || first.TAIUTC != 10 {
+		t.Errorf("first entry = %+v", first)
+	}
+
+	SetLeapSeconds(table)
+	defer SetLeapSeconds(nil)
+	// 样例表缺少 1973-2012 的闰秒
+	if got := TAIMinusUTC(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)); got != 12 {
+		t.Errorf("TAI-UTC with sample table = %v, want 12", got)
+	}
+	SetLeapSeconds(nil)
+	if got := TAIMinusUTC(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)); got != 32 {
+		t.Errorf("TAI-UTC with builtin table = %v, want 32", got)
+	}
+}
+
+func TestSetLeapSecondsConcurrent(t *testing.T) {
+	table, err := LoadLeapSeconds("test_data/leap-seconds.list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetLeapSeconds(nil)
+	// 换算与替换并发 (配合 go test -race)，结果只能来自两张表之一
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			if i%2 == 0 {
+				SetLeapSeconds(table)
+			} else {
+				SetLeapSeconds(nil)
+			}
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		if got := TAIMinusUTC(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)); got != 12 && got != 32 {
+			t.Fatalf("TAI-UTC = %v", got)
+		}
+	}
+	<-done
+}
+
+func TestGASTTimeScales(t *testing.T) {
+	// 岁差章动参数改用 TT 后，GAST 与全 UTC 输入仅相差约 69 s 的章动变化 (< 1 mas)
+	utc := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	jd := juliandate(utc)
+	jdTT := NewEpoch(utc, UTC).To(TT).JD()
+	d := math.Abs(GAST(jd, jdTT)-GAST(jd, jd)) / as2r
+	if d > 1e-3 {
+		t.Errorf("GAST TT/UTC difference = %.6f arcsec", d)
+	}
+	// 恒星时随 UT1 变化: 1 s UT1 ≈ 15.04"
+	d = (GAST(jd+1.0/86400, jdTT) - GAST(jd, jdTT)) / as2r
+	if math.Abs(d-15.041) > 0.01 {
+		t.Errorf("GAST rate = %.4f arcsec/s", d)
+	}
+}
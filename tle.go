@@ -0,0 +1,267 @@
+package gomap3d
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================
+// 两行/三行根数 (TLE / 3LE)
+//
+// 行 1: 编号、分类、国际编号、历元、n'/2、n''/6、B*、星历类型、根数组号、校验和
+// 行 2: 编号、倾角、升交点赤经、偏心率、近地点幅角、平近点角、平均运动、圈数、校验和
+// 参考: Vallado et al., Revisiting Spacetrack Report #3, AIAA 2006-6753
+// ============================================================
+
+// TLE SGP4 平根数
+// 角度单位为度，平均运动单位为 圈/天，历元为 UTC。
+type TLE struct {
+	Name           string    // 目标名称（3LE 第 0 行或 OMM OBJECT_NAME）
+	SatNum         int       // NORAD 编号
+	Classification byte      // 分类: U / C / S
+	IntlDesignator string    // 国际编号，如 "58002B"
+	Epoch          time.Time // 历元 (UTC)
+	MeanMotionDot  float64   // 平均运动一阶导数的一半 n'/2 (圈/天²)
+	MeanMotionDDot float64   // 平均运动二阶导数的六分之一 n''/6 (圈/天³)
+	BStar          float64   // 弹道阻力项 B* (1/地球半径)
+	EphemerisType  int       // 星历类型
+	ElementSetNo   int       // 根数组号
+	Inclination    float64   // 倾角 (°)
+	RAAN           float64   // 升交点赤经 (°)
+	Eccentricity   float64   // 偏心率
+	ArgPerigee     float64   // 近地点幅角 (°)
+	MeanAnomaly    float64   // 平近点角 (°)
+	MeanMotion     float64   // 平均运动 (圈/天)
+	RevNumber      int       // 历元时刻圈数
+}
+
+// tleChecksum 计算 TLE 行前 68 列的校验和：数字之和加 '-' 的个数，对 10 取模
+func tleChecksum(line string) int {
+	sum := 0
+	for i := 0; i < 68 && i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c >= '0' && c <= '9':
+			sum += int(c - '0')
+		case c == '-':
+			sum++
+		}
+	}
+	return sum % 10
+}
+
+// checkTLELine 检查行号、长度与校验和
+func checkTLELine(line string, n byte) error {
+	if len(line) < 69 {
+		return fmt.Errorf("TLE line %c: too short (%d columns)", n, len(line))
+	}
+	if line[0] != n || line[1] != ' ' {
+		return fmt.Errorf("TLE line %c: invalid line number", n)
+	}
+	want := int(line[68] - '0')
+	if line[68] < '0' || line[68] > '9' {
+		return fmt.Errorf("TLE line %c: invalid checksum character %q", n, line[68])
+	}
+	if got := tleChecksum(line); got != want {
+		return fmt.Errorf("TLE line %c: checksum mismatch (got %d, want %d)", n, got, want)
+	}
+	return nil
+}
+
+// ParseTLE 解析两行根数并校验校验和
+func ParseTLE(line1, line2 string) (*TLE, error) {
+	line1 = strings.TrimRight(line1, "\r\n")
+	line2 = strings.TrimRight(line2, "\r\n")
+	if err := checkTLELine(line1, '1'); err != nil {
+		return nil, err
+	}
+	if err := checkTLELine(line2, '2'); err != nil {
+		return nil, err
+	}
+
+	p := tleFieldParser{}
+	tle := &TLE{}
+	tle.SatNum = p.int(line1, 2, 7, "satellite number")
+	tle.Classification = line1[7]
+	tle.IntlDesignator = strings.TrimSpace(line1[9:17])
+	year := p.int(line1, 18, 20, "epoch year")
+	day := p.float(line1, 20, 32, "epoch day")
+	tle.MeanMotionDot = p.float(line1, 33, 43, "mean motion derivative")
+	tle.MeanMotionDDot = p.exp(line1, 44, 52, "mean motion second derivative")
+	tle.BStar = p.exp(line1, 53, 61, "B*")
+	tle.EphemerisType = p.intOpt(line1, 62, 63)
+	tle.ElementSetNo = p.intOpt(line1, 64, 68)
+
+	if n2 := p.int(line2, 2, 7, "satellite number"); p.err == nil && n2 != tle.SatNum {
+		return nil, fmt.Errorf("TLE: satellite number mismatch (%d vs %d)", tle.SatNum, n2)
+	}
+	tle.Inclination = p.float(line2, 8, 16, "inclination")
+	tle.RAAN = p.float(line2, 17, 25, "RAAN")
+	tle.Eccentricity = p.decimal(line2, 26, 33, "eccentricity")
+	tle.ArgPerigee = p.float(line2, 34, 42, "argument of perigee")
+	tle.MeanAnomaly = p.float(line2, 43, 51, "mean anomaly")
+	tle.MeanMotion = p.float(line2, 52, 63, "mean motion")
+	tle.RevNumber = p.intOpt(line2, 63, 68)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	// 两位年份: 57-99 → 19xx，00-56 → 20xx
+	if year < 57 {
+		year += 2000
+	} else {
+		year += 1900
+	}
+	tle.Epoch = tleEpoch(year, day)
+	return tle, nil
+}
+
+// tleEpoch 由年份与年积日（1.0 为 1 月 1 日 0 时）计算 UTC 历元，精确到微秒
+func tleEpoch(year int, day float64) time.Time {
+	t := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	us := math.Round((day - 1) * 86400e6)
+	return t.Add(time.Duration(us) * time.Microsecond)
+}
+
+// tleFieldParser 按列解析 TLE 字段，记录第一个错误
+type tleFieldParser struct {
+	err error
+}
+
+func (p *tleFieldParser) field(line string, start, end int) string {
+	if end > len(line) {
+		end = len(line)
+	}
+	if start >= end {
+		return ""
+	}
+	return strings.TrimSpace(line[start:end])
+}
+
+func (p *tleFieldParser) fail(name string, err error) {
+	if p.err == nil {
+		p.err = fmt.Errorf("TLE: invalid %s: %v", name, err)
+	}
+}
+
+func (p *tleFieldParser) int(line string, start, end int, name string) int {
+	v, err := strconv.Atoi(p.field(line, start, end))
+	if err != nil {
+		p.fail(name, err)
+	}
+	return v
+}
+
+// intOpt 可选整数字段，空白或非法时返回 0
+func (p *tleFieldParser) intOpt(line string, start, end int) int {
+	v, _ := strconv.Atoi(p.field(line, start, end))
+	return v
+}
+
+func (p *tleFieldParser) float(line string, start, end int, name string) float64 {
+	s := p.field(line, start, end)
+	// 允许省略前导零，如 ".00000023"、"-.00000158"
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		p.fail(name, err)
+	}
+	return v
+}
+
+// decimal 解析隐含前导小数点的字段，如 "1859667" = 0.1859667
+func (p *tleFieldParser) decimal(line string, start, end int, name string) float64 {
+	v, err := strconv.ParseFloat("0."+p.field(line, start, end), 64)
+	if err != nil {
+		p.fail(name, err)
+	}
+	return v
+}
+
+// exp 解析隐含小数点的指数字段，如 " 28098-4" = 0.28098e-4
+func (p *tleFieldParser) exp(line string, start, end int, name string) float64 {
+	s := p.field(line, start, end)
+	if s == "" {
+		return 0
+	}
+	sign := 1.0
+	if s[0] == '-' || s[0] == '+' {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = strings.TrimSpace(s[1:])
+	}
+	i := strings.LastIndexAny(s, "+-")
+	if i <= 0 {
+		p.fail(name, fmt.Errorf("missing exponent in %q", s))
+		return 0
+	}
+	mant, err := strconv.ParseFloat("0."+strings.TrimSpace(s[:i]), 64)
+	if err != nil {
+		p.fail(name, err)
+		return 0
+	}
+	e, err := strconv.Atoi(s[i:])
+	if err != nil {
+		p.fail(name, err)
+		return 0
+	}
+	return sign * mant * math.Pow(10, float64(e))
+}
+
+// ParseTLEs 读取 TLE 文件，支持两行格式与带名称行的三行格式
+// 空行与以 '#' 开头的行被忽略；第 0 行可带 "0 " 前缀。
+func ParseTLEs(r io.Reader) ([]*TLE, error) {
+	var out []*TLE
+	var name, line1 string
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		n++
+		s := strings.TrimRight(sc.Text(), "\r\n ")
+		if strings.TrimSpace(s) == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(s, "1 ") && line1 == "":
+			line1 = s
+		case strings.HasPrefix(s, "2 ") && line1 != "":
+			tle, err := ParseTLE(line1, s)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+			tle.Name = name
+			out = append(out, tle)
+			name, line1 = "", ""
+		case line1 == "":
+			name = strings.TrimSpace(strings.TrimPrefix(s, "0 "))
+		default:
+			return nil, fmt.Errorf("line %d: expected TLE line 2", n)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if line1 != "" {
+		return nil, fmt.Errorf("TLE: missing line 2 at end of input")
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no TLE found")
+	}
+	return out, nil
+}
+
+// LoadTLEs 从本地文件读取 TLE / 3LE
+func LoadTLEs(path string) ([]*TLE, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseTLEs(f)
+}
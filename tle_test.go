@@ -0,0 +1,136 @@
+package gomap3d
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	tle00005Line1 = "1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753"
+	tle00005Line2 = "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667"
+)
+
+func TestParseTLE(t *testing.T) {
+	tle, err := ParseTLE(tle00005Line1, tle00005Line2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tle.SatNum != 5 || tle.Classification != 'U' || tle.IntlDesignator != "58002B" {
+		t.Errorf("unexpected header: %+v", tle)
+	}
+	wantEpoch := time.Date(2000, 6, 27, 18, 50, 19, 733568000, time.UTC)
+	if !tle.Epoch.Equal(wantEpoch) {
+		t.Errorf("epoch = %v, want %v", tle.Epoch, wantEpoch)
+	}
+	if tle.MeanMotionDot != 0.00000023 || tle.MeanMotionDDot != 0 ||
+		math.Abs(tle.BStar-0.28098e-4) > 1e-15 {
+		t.Errorf("drag terms: ndot=%g nddot=%g bstar=%g", tle.MeanMotionDot, tle.MeanMotionDDot, tle.BStar)
+	}
+	if tle.Inclination != 34.2682 || tle.RAAN != 348.7242 || math.Abs(tle.Eccentricity-0.1859667) > 1e-15 ||
+		tle.ArgPerigee != 331.7664 || tle.MeanAnomaly != 19.3264 || tle.MeanMotion != 10.82419157 {
+		t.Errorf("elements: %+v", tle)
+	}
+	if tle.ElementSetNo != 475 || tle.RevNumber != 41366 {
+		t.Errorf("element set %d rev %d", tle.ElementSetNo, tle.RevNumber)
+	}
+
+	// 负的二阶导数与 B*
+	tle, err = ParseTLE(
+		"1 14128U 83058A   06176.02844893 -.00000158  00000-0  10000-3 0  9627",
+		"2 14128  11.4384  35.2134 0011562  26.4582 333.5652  0.98870114 46093")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tle.MeanMotionDot != -0.00000158 || math.Abs(tle.BStar-1e-4) > 1e-15 {
+		t.Errorf("14128 drag terms: %+v", tle)
+	}
+	if tle.Epoch.Year() != 2006 || tle.Epoch.YearDay() != 176 {
+		t.Errorf("14128 epoch = %v", tle.Epoch)
+	}
+}
+
+func TestParseTLEChecksum(t *testing.T) {
+	// 修改一位数字后校验和失败
+	bad := strings.Replace(tle00005Line2, "34.2682", "34.2683", 1)
+	if _, err := ParseTLE(tle00005Line1, bad); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("expected checksum error, got %v", err)
+	}
+	if _, err := ParseTLE(tle00005Line1[:60], tle00005Line2); err == nil {
+		t.Error("expected error for short line")
+	}
+	if _, err := ParseTLE(tle00005Line2, tle00005Line1); err == nil {
+		t.Error("expected error for swapped lines")
+	}
+}
+
+func TestParseTLEs3LE(t *testing.T) {
+	input := "0 VANGUARD 1\n" + tle00005Line1 + "\n" + tle00005Line2 + "\n\n" +
+		"1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985\r\n" +
+		"2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774\r\n"
+	tles, err := ParseTLEs(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tles) != 2 || tles[0].Name != "VANGUARD 1" || tles[1].Name != "" || tles[1].SatNum != 6251 {
+		t.Errorf("unexpected result: %d TLEs, %q %q", len(tles), tles[0].Name, tles[1].Name)
+	}
+	if _, err := ParseTLEs(strings.NewReader(tle00005Line1 + "\n")); err == nil {
+		t.Error("expected error for missing line 2")
+	}
+}
+
+func TestParseOMM(t *testing.T) {
+	ref, _ := ParseTLE(tle00005Line1, tle00005Line2)
+	ref.Name = "VANGUARD 1"
+
+	kvn, err := LoadOMM("test_data/omm_sample.kvn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	xml, err := LoadOMM("test_data/omm_sample.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kvn) != 1 || len(xml) != 2 {
+		t.Fatalf("OMM count: kvn %d, xml %d", len(kvn), len(xml))
+	}
+	for name, got := range map[string]*TLE{"kvn": kvn[0], "xml": xml[0]} {
+		if *got != *ref {
+			t.Errorf("%s OMM differs from TLE:\n  got  %+v\n  want %+v", name, *got, *ref)
+		}
+	}
+	if xml[1].SatNum != 8195 || xml[1].IntlDesignator != "75081A" || xml[1].Classification != 'U' {
+		t.Errorf("second XML OMM: %+v", xml[1])
+	}
+
+	// OMM 与 TLE 传播结果一致
+	s1, _ := NewSGP4(ref)
+	s2, _ := NewSGP4(xml[0])
+	r1, _, _ := s1.propagate(720)
+	r2, _, _ := s2.propagate(720)
+	if r1 != r2 {
+		t.Errorf("OMM propagation differs: %v vs %v", r1, r2)
+	}
+
+	// 非 SGP4 平根数被拒绝
+	bad := strings.Replace(kvnText(t), "MEAN_ELEMENT_THEORY = SGP4", "MEAN_ELEMENT_THEORY = DSST", 1)
+	if _, err := ParseOMM(strings.NewReader(bad)); err == nil {
+		t.Error("expected error for DSST mean elements")
+	}
+	bad = strings.Replace(kvnText(t), "REF_FRAME = TEME", "REF_FRAME = EME2000", 1)
+	if _, err := ParseOMM(strings.NewReader(bad)); err == nil {
+		t.Error("expected error for EME2000 reference frame")
+	}
+}
+
+func kvnText(t *testing.T) string {
+	t.Helper()
+	b, err := os.ReadFile("test_data/omm_sample.kvn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
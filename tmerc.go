@@ -0,0 +1,257 @@
+package gomap3d
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================================
+// 横轴墨卡托 / 高斯-克吕格投影
+//
+// Krüger 级数展开至 n⁶ (n 为第三扁率)，距中央经线 4000 km 内精度优于 5 nm。
+// 参考: Karney, Transverse Mercator with an accuracy of a few nanometers,
+// J. Geodesy 85 (2011) 475–485
+// x 为东向坐标，y 为北向坐标 (m)；γ 为子午线收敛角，即格网北相对真北的方位 (°，顺时针为正)。
+// ============================================================
+
+// TransverseMercator 横轴墨卡托投影
+// 级数系数在 NewTransverseMercator 中计算；直接构造的零值结构体在首次调用时计算，此前不宜并发使用。
+type TransverseMercator struct {
+	Ell           *Ellipsoid
+	LonOrigin     float64 // 中央经线 (°)
+	LatOrigin     float64 // 原点纬度 (°)
+	Scale         float64 // 中央经线比例因子 k₀
+	FalseEasting  float64 // 东伪偏移 (m)
+	FalseNorthing float64 // 北伪偏移 (m)
+
+	e, e2     float64
+	a1        float64    // 矫正球半径 A
+	alp, bet  [7]float64 // 正、反算级数系数 (下标 1..6)
+	y0        float64    // 原点纬度处的北向坐标
+	initiated bool
+}
+
+// NewTransverseMercator 创建横轴墨卡托投影
+func NewTransverseMercator(ell *Ellipsoid, lon0, lat0, k0, fe, fn float64) *TransverseMercator {
+	tm := &TransverseMercator{Ell: ell, LonOrigin: lon0, LatOrigin: lat0, Scale: k0,
+		FalseEasting: fe, FalseNorthing: fn}
+	tm.init()
+	return tm
+}
+
+// init 由椭球第三扁率计算级数系数
+func (tm *TransverseMercator) init() {
+	n := tm.Ell.ThirdFlattening
+	n2 := n * n
+	n3, n4, n5, n6 := n2*n, n2*n2, n2*n2*n, n2*n2*n2
+	tm.e = tm.Ell.Eccentricity
+	tm.e2 = tm.e * tm.e
+	tm.a1 = tm.Ell.SemimajorAxis / (1 + n) * (1 + n2/4 + n4/64 + n6/256)
+	tm.alp = [7]float64{0,
+		n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180 - 127*n5/288 + 7891*n6/37800,
+		13*n2/48 - 3*n3/5 + 557*n4/1440 + 281*n5/630 - 1983433*n6/1935360,
+		61*n3/240 - 103*n4/140 + 15061*n5/26880 + 167603*n6/181440,
+		49561*n4/161280 - 179*n5/168 + 6601661*n6/7257600,
+		34729*n5/80640 - 3418889*n6/1995840,
+		212378941 * n6 / 319334400,
+	}
+	tm.bet = [7]float64{0,
+		n/2 - 2*n2/3 + 37*n3/96 - n4/360 - 81*n5/512 + 96199*n6/604800,
+		n2/48 + n3/15 - 437*n4/1440 + 46*n5/105 - 1118711*n6/3870720,
+		17*n3/480 - 37*n4/840 - 209*n5/4480 + 5569*n6/90720,
+		4397*n4/161280 - 11*n5/504 - 830251*n6/7257600,
+		4583*n5/161280 - 108847*n6/3991680,
+		20648693 * n6 / 638668800,
+	}
+	tm.y0 = 0
+	tm.initiated = true
+	if tm.LatOrigin != 0 {
+		_, y, _, _ := tm.project(tm.LatOrigin, 0)
+		tm.y0 = y
+	}
+}
+
+// taup 保角纬度正切 τ' (τ = tanφ)
+func (tm *TransverseMercator) taup(tau float64) float64 {
+	t1 := math.Hypot(1, tau)
+	sig := math.Sinh(tm.e * math.Atanh(tm.e*tau/t1))
+	return math.Hypot(1, sig)*tau - sig*t1
+}
+
+// tauf 由 τ' 牛顿迭代反解 τ
+func (tm *TransverseMercator) tauf(taup float64) float64 {
+	tau := taup
+	for i := 0; i < 10; i++ {
+		tp := tm.taup(tau)
+		dtau := (taup - tp) / math.Hypot(1, tp) *
+			(1 + (1-tm.e2)*tau*tau) / ((1 - tm.e2) * math.Hypot(1, tau))
+		tau += dtau
+		if math.Abs(dtau) < 1e-15*math.Max(1, math.Abs(tau)) {
+			break
+		}
+	}
+	return tau
+}
+
+// project 相对中央经线的经差 dlon (°) 正算，返回未加比例因子与伪偏移的 (x, y)/k₀、收敛角与比例
+func (tm *TransverseMercator) project(lat, dlon float64) (x, y, gamma, k float64) {
+	phi := lat * math.Pi / 180
+	lam := dlon * math.Pi / 180
+	slam, clam := math.Sincos(lam)
+
+	var tau float64
+	if math.Abs(lat) >= 90 {
+		tau = math.Copysign(math.Inf(1), lat)
+	} else {
+		tau = math.Tan(phi)
+	}
+	var taup, xip, etap float64
+	if math.IsInf(tau, 0) {
+		xip, etap = math.Copysign(math.Pi/2, lat), 0
+		taup = tau
+	} else {
+		taup = tm.taup(tau)
+		xip = math.Atan2(taup, clam)
+		etap = math.Asinh(slam / math.Hypot(taup, clam))
+	}
+
+	xi, eta := xip, etap
+	p, q := 1.0, 0.0
+	for j := 1; j <= 6; j++ {
+		s, c := math.Sincos(2 * float64(j) * xip)
+		sh, ch := math.Sinh(2*float64(j)*etap), math.Cosh(2*float64(j)*etap)
+		xi += tm.alp[j] * s * ch
+		eta += tm.alp[j] * c * sh
+		p += 2 * float64(j) * tm.alp[j] * c * ch
+		q += 2 * float64(j) * tm.alp[j] * s * sh
+	}
+	x = tm.a1 * eta
+	y = tm.a1 * xi
+
+	if math.IsInf(taup, 0) {
+		gamma = dlon
+		k = tm.a1 / tm.Ell.SemimajorAxis * math.Sqrt(1-tm.e2) * math.Exp(tm.e*math.Atanh(tm.e)) * math.Hypot(p, q)
+	} else {
+		gamma = (math.Atan2(taup*slam, math.Hypot(1, taup)*clam) + math.Atan2(q, p)) * 180 / math.Pi
+		k = tm.a1 / tm.Ell.SemimajorAxis * math.Sqrt(1+(1-tm.e2)*tau*tau) *
+			math.Hypot(p, q) / math.Hypot(taup, clam)
+	}
+	return
+}
+
+// Project 正算，返回东向 x、北向 y (m)、子午线收敛角 γ (°) 与点比例因子 k
+func (tm *TransverseMercator) Project(lat, lon float64) (x, y, gamma, k float64) {
+	if !tm.initiated {
+		tm.init()
+	}
+	x, y, gamma, k = tm.project(lat, math.Remainder(lon-tm.LonOrigin, 360))
+	x = tm.FalseEasting + tm.Scale*x
+	y = tm.FalseNorthing + tm.Scale*(y-tm.y0)
+	return x, y, gamma, k * tm.Scale
+}
+
+// Unproject 反算，返回纬度、经度 (°)、子午线收敛角 γ (°) 与点比例因子 k
+func (tm *TransverseMercator) Unproject(x, y float64) (lat, lon, gamma, k float64) {
+	if !tm.initiated {
+		tm.init()
+	}
+	eta := (x - tm.FalseEasting) / (tm.Scale * tm.a1)
+	xi := ((y-tm.FalseNorthing)/tm.Scale + tm.y0) / tm.a1
+
+	xip, etap := xi, eta
+	for j := 1; j <= 6; j++ {
+		s, c := math.Sincos(2 * float64(j) * xi)
+		xip -= tm.bet[j] * s * math.Cosh(2*float64(j)*eta)
+		etap -= tm.bet[j] * c * math.Sinh(2*float64(j)*eta)
+	}
+	sxip, cxip := math.Sincos(xip)
+	shetap := math.Sinh(etap)
+	r := math.Hypot(shetap, cxip)
+	var dlon float64
+	if r != 0 {
+		dlon = math.Atan2(shetap, cxip) * 180 / math.Pi
+		lat = math.Atan(tm.tauf(sxip/r)) * 180 / math.Pi
+	} else {
+		lat = math.Copysign(90, sxip)
+	}
+	lon = math.Remainder(tm.LonOrigin+dlon, 360)
+	_, _, gamma, k = tm.project(lat, dlon)
+	return lat, lon, gamma, k * tm.Scale
+}
+
+// Forward 实现 Projection
+func (tm *TransverseMercator) Forward(geo Geodetic) (x, y float64, err error) {
+	x, y, _, _ = tm.Project(geo.Latitude, geo.Longitude)
+	return x, y, nil
+}
+
+// Inverse 实现 Projection
+func (tm *TransverseMercator) Inverse(x, y float64) (Geodetic, error) {
+	lat, lon, _, _ := tm.Unproject(x, y)
+	return Geodetic{Latitude: lat, Longitude: lon, Ell: tm.Ell}, nil
+}
+
+// GaussKrugerFalseEasting 高斯-克吕格投影东伪偏移 (m)
+const GaussKrugerFalseEasting = 500000
+
+// GaussKrugerZone 经度 lon (°) 所在的 3° 或 6° 带号及中央经线 (°)
+// 6° 带: 带号 n = ⌊λ/6⌋ + 1，中央经线 6n - 3；3° 带: 带号 n = ⌊(λ + 1.5)/3⌋，中央经线 3n。
+func GaussKrugerZone(lon float64, width int) (zone int, lon0 float64, err error) {
+	lon = math.Mod(lon, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	switch width {
+	case 6:
+		zone = int(math.Floor(lon/6)) + 1
+		return zone, float64(6*zone - 3), nil
+	case 3:
+		zone = int(math.Floor((lon + 1.5) / 3))
+		if zone == 0 {
+			zone = 120
+		}
+		return zone, float64(3 * zone), nil
+	}
+	return 0, 0, fmt.Errorf("gauss-kruger: zone width must be 3 or 6, got %d", width)
+}
+
+// NewGaussKruger 创建 width (3 或 6) 度带第 zone 带的高斯-克吕格投影
+// 东伪偏移 500 km，不含带号前缀，比例因子为 1。
+func NewGaussKruger(ell *Ellipsoid, zone, width int) (*TransverseMercator, error) {
+	var lon0 float64
+	switch {
+	case width == 6 && zone >= 1 && zone <= 60:
+		lon0 = float64(6*zone - 3)
+	case width == 3 && zone >= 1 && zone <= 120:
+		lon0 = float64(3 * zone)
+	default:
+		return nil, fmt.Errorf("gauss-kruger: invalid %d° zone %d", width, zone)
+	}
+	return NewTransverseMercator(ell, math.Remainder(lon0, 360), 0, 1, GaussKrugerFalseEasting, 0), nil
+}
+
+// GaussKrugerForward 高斯-克吕格正算，按经度自动分带
+// 返回测量坐标 x (北向) 与通用坐标 y = 带号×10⁶ + 500 km + 东向坐标 (m)，以及收敛角 (°) 与比例因子。
+func GaussKrugerForward(lat, lon float64, width int, ell *Ellipsoid) (x, y float64, zone int, gamma, k float64, err error) {
+	zone, _, err = GaussKrugerZone(lon, width)
+	if err != nil {
+		return
+	}
+	tm, err := NewGaussKruger(ell, zone, width)
+	if err != nil {
+		return
+	}
+	e, n, gamma, k := tm.Project(lat, lon)
+	return n, float64(zone)*1e6 + e, zone, gamma, k, nil
+}
+
+// GaussKrugerInverse 高斯-克吕格反算，带号取自通用坐标 y 的百万位以上部分
+func GaussKrugerInverse(x, y float64, width int, ell *Ellipsoid) (lat, lon, gamma, k float64, err error) {
+	zone := int(math.Floor(y / 1e6))
+	tm, err := NewGaussKruger(ell, zone, width)
+	if err != nil {
+		return
+	}
+	lat, lon, gamma, k = tm.Unproject(y-float64(zone)*1e6, x)
+	return lat, lon, gamma, k, nil
+}
@@ -0,0 +1,110 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTransverseMercator(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	tm := NewTransverseMercator(wgs, 0, 0, 1, 0, 0)
+
+	// 子午线弧长: 赤道至极点 10001965.7293 m
+	if _, y, _, _ := tm.Project(90, 0); math.Abs(y-10001965.7293) > 1e-4 {
+		t.Errorf("quarter meridian %.4f m", y)
+	}
+	// 中央经线上比例因子为 k₀，收敛角为 0
+	if x, _, g, k := tm.Project(40, 0); x != 0 || g != 0 || math.Abs(k-1) > 1e-15 {
+		t.Errorf("central meridian: x %g, γ %g, k %.16f", x, g, k)
+	}
+
+	// 往返精度、收敛角与比例因子与数值导数一致
+	ell := wgs
+	for _, p := range [][2]float64{{0, 3}, {30, 10}, {-45, -20}, {60, 35}, {85, -60}, {10, 25}} {
+		lat, lon := p[0], p[1]
+		x, y, gamma, k := tm.Project(lat, lon)
+		la, lo, g2, k2 := tm.Unproject(x, y)
+		geo1 := Geodetic{Latitude: lat, Longitude: lon, Ell: ell}
+		geo2 := Geodetic{Latitude: la, Longitude: lo, Ell: ell}
+		e1, e2 := geo1.ToECEF(), geo2.ToECEF()
+		if d := vecDist([3]float64{e1.X, e1.Y, e1.Z}, [3]float64{e2.X, e2.Y, e2.Z}); d > 5e-9 {
+			t.Errorf("%v: round trip error %.3e m", p, d)
+		}
+		if math.Abs(g2-gamma) > 1e-12 || math.Abs(k2-k) > 1e-12 {
+			t.Errorf("%v: inverse γ %g / k %g differ from forward %g / %g", p, g2, k2, gamma, k)
+		}
+
+		const h = 1e-5
+		xn, yn, _, _ := tm.Project(lat+h, lon)
+		xs, ys, _, _ := tm.Project(lat-h, lon)
+		xe, ye, _, _ := tm.Project(lat, lon+h)
+		xw, yw, _, _ := tm.Project(lat, lon-h)
+		if g := -math.Atan2(xn-xs, yn-ys) * 180 / math.Pi; math.Abs(g-gamma) > 1e-7 {
+			t.Errorf("%v: convergence %.9f°, numerical %.9f°", p, gamma, g)
+		}
+		phi := lat * math.Pi / 180
+		n := wgs.SemimajorAxis / math.Sqrt(1-wgs.Eccentricity*wgs.Eccentricity*math.Sin(phi)*math.Sin(phi))
+		if kn := math.Hypot(xe-xw, ye-yw) / (n * math.Cos(phi) * 2 * h * math.Pi / 180); math.Abs(kn-k) > 1e-9 {
+			t.Errorf("%v: scale %.10f, numerical %.10f", p, k, kn)
+		}
+	}
+
+	// 原点纬度与伪偏移 (英国国家格网参数)
+	airy, _ := NewEllipsoid("airy")
+	osgb := NewTransverseMercator(airy, -2, 49, 0.9996012717, 400000, -100000)
+	if x, y, _, _ := osgb.Project(49, -2); math.Abs(x-400000) > 1e-9 || math.Abs(y+100000) > 1e-9 {
+		t.Errorf("true origin projects to (%.6f, %.6f)", x, y)
+	}
+	// OS 算例: 52°39'27.2531"N 1°43'4.5177"E → E 651409.903, N 313177.270
+	x, y, _, _ := osgb.Project(52+39/60.0+27.2531/3600, 1+43/60.0+4.5177/3600)
+	if math.Abs(x-651409.903) > 1e-3 || math.Abs(y-313177.270) > 1e-3 {
+		t.Errorf("OSGB example: E %.3f N %.3f", x, y)
+	}
+}
+
+func TestGaussKruger(t *testing.T) {
+	cgcs, _ := NewEllipsoid("cgcs2000")
+	cases := []struct {
+		lon   float64
+		width int
+		zone  int
+		lon0  float64
+	}{
+		{116.4, 6, 20, 117}, {116.4, 3, 39, 117}, {114, 6, 20, 117}, {113.9, 3, 38, 114},
+		{1, 3, 120, 360}, {-1, 6, 60, 357},
+	}
+	for _, c := range cases {
+		z, l0, err := GaussKrugerZone(c.lon, c.width)
+		if err != nil || z != c.zone || l0 != c.lon0 {
+			t.Errorf("GaussKrugerZone(%g, %d) = %d, %g, %v", c.lon, c.width, z, l0, err)
+		}
+	}
+	if _, _, err := GaussKrugerZone(0, 5); err == nil {
+		t.Error("expected error for 5° zones")
+	}
+
+	for _, width := range []int{3, 6} {
+		x, y, zone, gamma, k, err := GaussKrugerForward(39.9, 116.4, width, cgcs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 东向坐标位于中央经线以西，带号前缀
+		if int(y/1e6) != zone || y-float64(zone)*1e6 >= GaussKrugerFalseEasting || x < 4.4e6 || x > 4.43e6 {
+			t.Errorf("%d° zone %d: x %.3f y %.3f", width, zone, x, y)
+		}
+		// 北半球中央经线以西收敛角为负，比例因子略大于 1
+		if gamma >= 0 || k <= 1 || k > 1.0001 {
+			t.Errorf("%d°: γ %g k %g", width, gamma, k)
+		}
+		lat, lon, g2, k2, err := GaussKrugerInverse(x, y, width, cgcs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(lat-39.9) > 1e-10 || math.Abs(lon-116.4) > 1e-10 || math.Abs(g2-gamma) > 1e-10 || math.Abs(k2-k) > 1e-12 {
+			t.Errorf("%d°: inverse %.12f %.12f %g %g", width, lat, lon, g2, k2)
+		}
+	}
+	if _, _, _, _, err := GaussKrugerInverse(4.4e6, 500000, 6, cgcs); err == nil {
+		t.Error("expected error for missing zone prefix")
+	}
+}
@@ -0,0 +1,210 @@
+package gomap3d
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================
+// ECI ↔ ECEF 转换器
+//
+// Transformer 持有一组不可变的转换选项（模型、EOP、章动序列、时间尺度），
+// 可在多个 goroutine 间共享。包级函数 ECI2ECEF 等使用默认转换器，
+// SetGMSTMode / SetEOP / SetNutation2000A 以原子替换的方式修改默认转换器。
+// ============================================================
+
+// Model ECI ↔ ECEF 变换模型
+type Model int
+
+const (
+	ModelGMST     Model = iota // 仅 Rz(GMST1982)，对标 Octave 实现
+	ModelIAU2006B              // IAU 2006 岁差 + IAU 2000B 章动，分点链
+	ModelIAU2006A              // IAU 2006 岁差 + IAU 2000A 章动，CIO 链
+)
+
+func (m Model) String() string {
+	switch m {
+	case ModelGMST:
+		return "GMST"
+	case ModelIAU2006B:
+		return "IAU2006/2000B"
+	case ModelIAU2006A:
+		return "IAU2006/2000A"
+	}
+	return fmt.Sprintf("Model(%d)", int(m))
+}
+
+// Options 转换选项
+type Options struct {
+	Model     Model          // 变换模型，零值为 ModelGMST
+	EOP       *EOPTable      // EOP 数据源，nil 表示不使用 EOP（仅完整模型生效）
+	Nutation  *Nutation2000A // IAU 2000A 章动序列，ModelIAU2006A 必需
+	CIP       *CIPSeries     // CIP 坐标 X、Y 级数，给出时 CIO 链直接由级数计算 X、Y
+	TimeScale TimeScale      // 输入时刻的时间尺度，零值为 UTC
+}
+
+// Transformer ECI ↔ ECEF 转换器，创建后不可修改，可并发使用
+type Transformer struct {
+	opts Options
+}
+
+// NewTransformer 按选项创建转换器
+func NewTransformer(opts Options) (*Transformer, error) {
+	switch opts.Model {
+	case ModelGMST, ModelIAU2006B:
+	case ModelIAU2006A:
+		if opts.Nutation == nil {
+			return nil, fmt.Errorf("model %v requires IAU 2000A nutation series", opts.Model)
+		}
+	default:
+		return nil, fmt.Errorf("unknown model %v", opts.Model)
+	}
+	if opts.TimeScale < UTC || opts.TimeScale > TDB {
+		return nil, fmt.Errorf("unknown time scale %v", opts.TimeScale)
+	}
+	return &Transformer{opts: opts}, nil
+}
+
+// Options 返回转换器的选项
+func (tr *Transformer) Options() Options { return tr.opts }
+
+// defaultTransformer 包级函数使用的转换器
+var defaultTransformer atomic.Pointer[Transformer]
+
+func init() {
+	defaultTransformer.Store(&Transformer{})
+}
+
+// DefaultTransformer 返回包级函数当前使用的转换器
+func DefaultTransformer() *Transformer { return defaultTransformer.Load() }
+
+// updateDefaultOptions 原子地修改默认转换器选项
+func updateDefaultOptions(f func(o *Options)) {
+	for {
+		old := defaultTransformer.Load()
+		opts := old.opts
+		f(&opts)
+		if defaultTransformer.CompareAndSwap(old, &Transformer{opts: opts}) {
+			return
+		}
+	}
+}
+
+// utc 将输入时刻换算为 UTC
+func (tr *Transformer) utc(t time.Time) time.Time {
+	if tr.opts.TimeScale == UTC {
+		return t
+	}
+	return NewEpoch(t, tr.opts.TimeScale).to(UTC, tr.opts.EOP).T
+}
+
+// fromUTC 将 UTC 时刻换算为转换器的输入时间尺度
+func (tr *Transformer) fromUTC(t time.Time) time.Time {
+	if tr.opts.TimeScale == UTC {
+		return t
+	}
+	return NewEpoch(t, UTC).to(tr.opts.TimeScale, tr.opts.EOP).T
+}
+
+// nutation 章动 (Δψ, Δε)，单位 rad
+// ModelIAU2006B 固定使用 IAU 2000B；其余模型在给出 Nutation 时使用 IAU 2006/2000A。
+func (tr *Transformer) nutation(T float64) (dpsi, deps float64) {
+	if tr.opts.Nutation != nil && tr.opts.Model != ModelIAU2006B {
+		return tr.opts.Nutation.nutation06(T)
+	}
+	return nutation00b(T)
+}
+
+// cipModel CIP 坐标模型值 X、Y (rad)，T 为 TT 儒略世纪数
+// 章动取法同 nutation：给出 CIP 级数时直接求和，否则取 NPB 矩阵第三行。
+func (tr *Transformer) cipModel(T float64) (x, y float64) {
+	if tr.opts.CIP != nil && tr.opts.Model != ModelIAU2006B {
+		return tr.opts.CIP.XY(T)
+	}
+	dpsi, deps := tr.nutation(T)
+	m := npbMatrix(T, dpsi, deps)
+	return m[2][0], m[2][1]
+}
+
+// Matrix 时刻 t 的 ECI → ECEF 旋转矩阵
+// ModelGMST 为 Rz(GMST)；ModelIAU2006B 为分点链 GCRF2ITRFEOP；
+// ModelIAU2006A 为 CIO 链 GCRF2ITRFCIO。完整模型的岁差章动使用 TT，
+// 恒星时/地球自转角使用 UT1，并含 EOP 修正。
+func (tr *Transformer) Matrix(t time.Time) [3][3]float64 {
+	utc := tr.utc(t)
+	jd := juliandate(utc)
+	if tr.opts.Model == ModelGMST {
+		return R3(greenwichsrt(jd))
+	}
+	eop := eopFrom(tr.opts.EOP, utc)
+	jdTT := NewEpoch(utc, UTC).to(TT, tr.opts.EOP).JD()
+	jdUT1 := jd + eop.UT1UTC/86400.0
+	T := (jdTT - 2451545.0) / 36525.0
+	if tr.opts.Model == ModelIAU2006A {
+		x, y := tr.cipModel(T)
+		return gcrf2itrfCIO(jdUT1, jdTT, eop, x, y)
+	}
+	dpsi, deps := tr.nutation(T)
+	return gcrf2itrfEquinox(jdUT1, jdTT, eop, dpsi, deps)
+}
+
+// rotationRate 时刻 t 地球自转角速度 (rad/s)
+// 完整模型且有 EOP 时按日长修正: ω = We · (1 - LOD/86400)
+func (tr *Transformer) rotationRate(t time.Time) float64 {
+	if tr.opts.Model == ModelGMST {
+		return We
+	}
+	return We * (1 - eopFrom(tr.opts.EOP, tr.utc(t)).LOD/86400.0)
+}
+
+// ECI2ECEF 将ECI坐标(GCRF)转换为ECEF坐标(ITRF)
+func (tr *Transformer) ECI2ECEF(x, y, z float64, t time.Time) (xEcef, yEcef, zEcef float64) {
+	ecefVec := multiplyMatrixVector(tr.Matrix(t), [3]float64{x, y, z})
+	return ecefVec[0], ecefVec[1], ecefVec[2]
+}
+
+// ECEF2ECI 将ECEF坐标(ITRF)转换为ECI坐标(GCRF)
+func (tr *Transformer) ECEF2ECI(x, y, z float64, t time.Time) (xEci, yEci, zEci float64) {
+	eciVec := multiplyMatrixVector(transpose(tr.Matrix(t)), [3]float64{x, y, z})
+	return eciVec[0], eciVec[1], eciVec[2]
+}
+
+// ECEFVel2ECIVel 将 ECEF 速度转换为 ECI 速度
+// 公式: v_eci = M^T · (v_ecef + ω × r_ecef)
+func (tr *Transformer) ECEFVel2ECIVel(vx, vy, vz, x, y, z float64, t time.Time) (vxEci, vyEci, vzEci float64) {
+	M := transpose(tr.Matrix(t))
+	w := tr.rotationRate(t)
+
+	// v_ecef + ω × r_ecef
+	v := [3]float64{
+		vx - w*y,
+		vy + w*x,
+		vz,
+	}
+	result := multiplyMatrixVector(M, v)
+	return result[0], result[1], result[2]
+}
+
+// ECIVel2ECEFVel 将 ECI 速度转换为 ECEF 速度
+// 公式: v_ecef = M · v_eci - ω × r_ecef，其中 r_ecef = M · r_eci
+func (tr *Transformer) ECIVel2ECEFVel(vx, vy, vz, x, y, z float64, t time.Time) (vxEcef, vyEcef, vzEcef float64) {
+	M := tr.Matrix(t)
+	w := tr.rotationRate(t)
+
+	rEcef := multiplyMatrixVector(M, [3]float64{x, y, z})
+	vRot := multiplyMatrixVector(M, [3]float64{vx, vy, vz})
+	return vRot[0] + w*rEcef[1], vRot[1] - w*rEcef[0], vRot[2]
+}
+
+// TEME2ECEF TEME → ITRF 位置转换
+func (tr *Transformer) TEME2ECEF(x, y, z float64, t time.Time) (xEcef, yEcef, zEcef float64) {
+	xEcef, yEcef, zEcef, _ = tr.TransformFrame(x, y, z, TEME, ITRF, t)
+	return
+}
+
+// ECEF2TEME ITRF → TEME 位置转换
+func (tr *Transformer) ECEF2TEME(x, y, z float64, t time.Time) (xTeme, yTeme, zTeme float64) {
+	xTeme, yTeme, zTeme, _ = tr.TransformFrame(x, y, z, ITRF, TEME, t)
+	return
+}
@@ -0,0 +1,147 @@
+package gomap3d
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewTransformerValidation(t *testing.T) {
+	if _, err := NewTransformer(Options{Model: ModelIAU2006A}); err == nil {
+		t.Error("expected error for IAU2006A without nutation series")
+	}
+	if _, err := NewTransformer(Options{Model: Model(9)}); err == nil {
+		t.Error("expected error for unknown model")
+	}
+	if _, err := NewTransformer(Options{TimeScale: TimeScale(-1)}); err == nil {
+		t.Error("expected error for unknown time scale")
+	}
+	tr, err := NewTransformer(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Options().Model != ModelGMST || tr.Options().TimeScale != UTC {
+		t.Errorf("unexpected zero options: %+v", tr.Options())
+	}
+}
+
+func TestDefaultTransformerCompat(t *testing.T) {
+	nut, err := LoadNutation2000A("test_data/tab5.3a_sample.txt", "test_data/tab5.3b_sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetGMSTMode(true)
+	defer SetNutation2000A(nil)
+
+	if m := DefaultTransformer().Options().Model; m != ModelGMST {
+		t.Fatalf("default model = %v, want GMST", m)
+	}
+	SetGMSTMode(false)
+	if m := DefaultTransformer().Options().Model; m != ModelIAU2006B {
+		t.Errorf("SetGMSTMode(false) model = %v", m)
+	}
+	SetNutation2000A(nut)
+	if m := DefaultTransformer().Options().Model; m != ModelIAU2006A {
+		t.Errorf("SetNutation2000A model = %v", m)
+	}
+	SetGMSTMode(true)
+	SetGMSTMode(false)
+	if m := DefaultTransformer().Options().Model; m != ModelIAU2006A {
+		t.Errorf("SetGMSTMode(false) with nutation model = %v", m)
+	}
+
+	// 包级函数与同选项的显式转换器结果一致
+	tr, err := NewTransformer(Options{Model: ModelIAU2006A, Nutation: nut})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tUTC := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	p := [3]float64{-2.174e6, 4.389e6, 4.077e6}
+	x1, y1, z1 := ECI2ECEF(p[0], p[1], p[2], tUTC)
+	x2, y2, z2 := tr.ECI2ECEF(p[0], p[1], p[2], tUTC)
+	if x1 != x2 || y1 != y2 || z1 != z2 {
+		t.Error("default transformer differs from explicit transformer")
+	}
+}
+
+func TestTransformerTimeScale(t *testing.T) {
+	utc, _ := NewTransformer(Options{Model: ModelIAU2006B})
+	tt, _ := NewTransformer(Options{Model: ModelIAU2006B, TimeScale: TT})
+
+	tUTC := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	tTT := tUTC.Add(69184 * time.Millisecond)
+	p := [3]float64{-2.174e6, 4.389e6, 4.077e6}
+	x1, y1, z1 := utc.ECI2ECEF(p[0], p[1], p[2], tUTC)
+	x2, y2, z2 := tt.ECI2ECEF(p[0], p[1], p[2], tTT)
+	if d := vecDist([3]float64{x1, y1, z1}, [3]float64{x2, y2, z2}); d > 1e-6 {
+		t.Errorf("TT input differs from UTC input by %.3e m", d)
+	}
+}
+
+func TestTransformerConcurrent(t *testing.T) {
+	eop, err := LoadFinals2000A("test_data/finals2000A_sample.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gmst, _ := NewTransformer(Options{Model: ModelGMST})
+	full, _ := NewTransformer(Options{Model: ModelIAU2006B, EOP: eop})
+
+	tUTC := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	p := [3]float64{-2.174e6, 4.389e6, 4.077e6}
+	want := map[*Transformer][3]float64{}
+	for _, tr := range []*Transformer{gmst, full} {
+		x, y, z := tr.ECI2ECEF(p[0], p[1], p[2], tUTC)
+		want[tr] = [3]float64{x, y, z}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 64)
+	for i := 0; i < 32; i++ {
+		tr := gmst
+		if i%2 == 1 {
+			tr = full
+		}
+		wg.Add(1)
+		go func(tr *Transformer) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				x, y, z := tr.ECI2ECEF(p[0], p[1], p[2], tUTC)
+				if [3]float64{x, y, z} != want[tr] {
+					errs <- tr.Options().Model.String()
+					return
+				}
+			}
+		}(tr)
+	}
+	// 并发修改默认转换器不影响显式转换器
+	for i := 0; i < 10; i++ {
+		SetGMSTMode(i%2 == 0)
+	}
+	SetGMSTMode(true)
+	wg.Wait()
+	close(errs)
+	for m := range errs {
+		t.Errorf("%s transformer returned inconsistent result under concurrency", m)
+	}
+}
+
+func TestECIMethodsWithTransformer(t *testing.T) {
+	ell, _ := NewEllipsoid("wgs84")
+	tr, _ := NewTransformer(Options{Model: ModelIAU2006B})
+	tUTC := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	geo := Geodetic{Latitude: 30, Longitude: 120, Altitude: 100, Ell: ell}
+	eci := geo.ToECIWith(tUTC, tr)
+	back := eci.ToGeodeticWith(tr)
+	if d := vecDist([3]float64{back.Latitude, back.Longitude, back.Altitude * 1e-5},
+		[3]float64{geo.Latitude, geo.Longitude, geo.Altitude * 1e-5}); d > 1e-9 {
+		t.Errorf("geodetic roundtrip with transformer: %+v", back)
+	}
+
+	// 与默认 GMST 转换器结果不同
+	ecefDefault := eci.ToECEF()
+	ecefFull := eci.ToECEFWith(tr)
+	if vecDist([3]float64{ecefDefault.X, ecefDefault.Y, ecefDefault.Z}, [3]float64{ecefFull.X, ecefFull.Y, ecefFull.Z}) < 1000 {
+		t.Error("ToECEFWith should use the transformer model")
+	}
+}
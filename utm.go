@@ -0,0 +1,347 @@
+package gomap3d
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================================
+// UTM / UPS / MGRS
+//
+// UTM: 横轴墨卡托，k₀ = 0.9996，东伪偏移 500 km，南半球北伪偏移 10000 km，
+// 适用纬度 [-80°, 84°] (与 MGRS 约定一致)，含挪威 (32V) 与斯瓦尔巴 (31X/33X/35X/37X) 分带例外。
+// UPS: 极球面投影，k₀ = 0.994，伪偏移均为 2000 km，用于两极地区。
+// MGRS 采用 NGA 标准 100 km 格网字母 (AA 方案；Clarke 1866/1880、Bessel 椭球为 AL 方案)，
+// 坐标截断 (非四舍五入) 到指定精度。
+// ============================================================
+
+const (
+	utmScale         = 0.9996
+	utmFalseEasting  = 500000
+	utmFalseNorthing = 10000000
+	upsScale         = 0.994
+	upsFalseOrigin   = 2000000
+
+	mgrsBands   = "CDEFGHJKLMNPQRSTUVWX"
+	mgrsRows    = "ABCDEFGHJKLMNPQRSTUV"
+	mgrsUPSRows = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+)
+
+// mgrsColumns UTM 100 km 格网列字母，按 (带号 - 1) mod 3 分组
+var mgrsColumns = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+
+// mgrsUPSColumns UPS 100 km 格网列字母: 西半区 (A/Y) 与东半区 (B/Z)
+var mgrsUPSColumns = [2]string{"JKLPQRSTUXYZ", "ABCFGHJKLPQR"}
+
+// UTM 通用横轴墨卡托 / 通用极球面坐标
+type UTM struct {
+	Zone     int  // 带号 1..60，0 表示 UPS
+	North    bool // 北半球
+	Easting  float64
+	Northing float64
+	Ell      *Ellipsoid
+}
+
+// UTMZone 经纬度 (°) 所在的 UTM 带号与纬度带字母，含挪威与斯瓦尔巴例外
+// 纬度超出 [-80°, 84°] 时返回带号 0 (UPS)，纬度带字母为 A/B (南) 或 Y/Z (北)。
+func UTMZone(lat, lon float64) (zone int, band byte) {
+	lon = math.Remainder(lon, 360)
+	if lat < -80 || lat > 84 {
+		switch {
+		case lat < 0 && lon < 0:
+			return 0, 'A'
+		case lat < 0:
+			return 0, 'B'
+		case lon < 0:
+			return 0, 'Y'
+		default:
+			return 0, 'Z'
+		}
+	}
+	b := int(math.Floor((lat + 80) / 8))
+	if b > len(mgrsBands)-1 {
+		b = len(mgrsBands) - 1 // X 带跨 12°
+	}
+	band = mgrsBands[b]
+
+	zone = int(math.Floor((lon+180)/6)) + 1
+	if zone > 60 {
+		zone = 1
+	}
+	switch {
+	case band == 'V' && lon >= 3 && lon < 12:
+		zone = 32
+	case band == 'X' && lon >= 0 && lon < 42:
+		switch {
+		case lon < 9:
+			zone = 31
+		case lon < 21:
+			zone = 33
+		case lon < 33:
+			zone = 35
+		default:
+			zone = 37
+		}
+	}
+	return zone, band
+}
+
+// utmProjection 第 zone 带的横轴墨卡托投影
+func utmProjection(ell *Ellipsoid, zone int, north bool) *TransverseMercator {
+	fn := 0.0
+	if !north {
+		fn = utmFalseNorthing
+	}
+	return NewTransverseMercator(ell, float64(6*zone-183), 0, utmScale, utmFalseEasting, fn)
+}
+
+// ToUTM 转 UTM 坐标，按经纬度自动分带，两极地区为 UPS
+func (geo *Geodetic) ToUTM() UTM {
+	zone, _ := UTMZone(geo.Latitude, geo.Longitude)
+	if zone == 0 {
+		return geo.toUPS()
+	}
+	u, _ := geo.ToUTMZone(zone)
+	return u
+}
+
+// ToUTMZone 按指定带号 (1..60) 转 UTM 坐标，可用于跨带延伸计算
+func (geo *Geodetic) ToUTMZone(zone int) (UTM, error) {
+	if zone < 1 || zone > 60 {
+		return UTM{}, fmt.Errorf("utm: invalid zone %d", zone)
+	}
+	north := geo.Latitude >= 0
+	e, n, _, _ := utmProjection(geo.Ell, zone, north).Project(geo.Latitude, geo.Longitude)
+	return UTM{Zone: zone, North: north, Easting: e, Northing: n, Ell: geo.Ell}, nil
+}
+
+// toUPS 转 UPS 坐标
+func (geo *Geodetic) toUPS() UTM {
+	north := geo.Latitude >= 0
+	x, y := polarStereoForward(geo.Latitude, geo.Longitude, north, upsScale, geo.Ell)
+	return UTM{Zone: 0, North: north, Easting: upsFalseOrigin + x, Northing: upsFalseOrigin + y, Ell: geo.Ell}
+}
+
+// ToGeodetic 转大地坐标系
+func (u UTM) ToGeodetic() (Geodetic, error) {
+	var lat, lon float64
+	switch {
+	case u.Zone == 0:
+		lat, lon = polarStereoInverse(u.Easting-upsFalseOrigin, u.Northing-upsFalseOrigin, u.North, upsScale, u.Ell)
+	case u.Zone >= 1 && u.Zone <= 60:
+		lat, lon, _, _ = utmProjection(u.Ell, u.Zone, u.North).Unproject(u.Easting, u.Northing)
+	default:
+		return Geodetic{}, fmt.Errorf("utm: invalid zone %d", u.Zone)
+	}
+	return Geodetic{Latitude: lat, Longitude: lon, Ell: u.Ell}, nil
+}
+
+// polarStereoForward 极球面投影 (变体 A) 正算，返回相对极点的东向 x、北向 y (m)
+// 北极: x = ρ sinλ, y = -ρ cosλ；南极: x = ρ sinλ, y = ρ cosλ
+func polarStereoForward(lat, lon float64, north bool, k0 float64, ell *Ellipsoid) (x, y float64) {
+	phi := lat * math.Pi / 180
+	if !north {
+		phi = -phi
+	}
+	rho := 2 * ell.SemimajorAxis * k0 * conformalT(phi, ell.Eccentricity) / polarStereoC(ell.Eccentricity)
+	sl, cl := math.Sincos(lon * math.Pi / 180)
+	if north {
+		return rho * sl, -rho * cl
+	}
+	return rho * sl, rho * cl
+}
+
+// polarStereoInverse 极球面投影 (变体 A) 反算
+func polarStereoInverse(x, y float64, north bool, k0 float64, ell *Ellipsoid) (lat, lon float64) {
+	t := math.Hypot(x, y) * polarStereoC(ell.Eccentricity) / (2 * ell.SemimajorAxis * k0)
+	phi := conformalTInverse(t, ell.Eccentricity)
+	if north {
+		lon = math.Atan2(x, -y)
+	} else {
+		lon, phi = math.Atan2(x, y), -phi
+	}
+	return phi * 180 / math.Pi, lon * 180 / math.Pi
+}
+
+// polarStereoC √((1+e)^(1+e)·(1-e)^(1-e))
+func polarStereoC(e float64) float64 {
+	return math.Sqrt(math.Pow(1+e, 1+e) * math.Pow(1-e, 1-e))
+}
+
+// mgrsRowOffset 行字母偏移: 偶数带 +5，AL 方案再 +10
+func mgrsRowOffset(zone int, ell *Ellipsoid) int {
+	off := 0
+	if zone%2 == 0 {
+		off = 5
+	}
+	if ell != nil {
+		switch ell.Model {
+		case "clrk66", "clrk80", "bessel":
+			off += 10
+		}
+	}
+	return off
+}
+
+// ToMGRS 转 MGRS 字符串，digits 为每个坐标分量的位数 (0: 100 km … 5: 1 m)
+func (geo *Geodetic) ToMGRS(digits int) (string, error) {
+	if digits < 0 || digits > 5 {
+		return "", fmt.Errorf("mgrs: digits must be 0..5, got %d", digits)
+	}
+	if geo.Latitude < -90 || geo.Latitude > 90 {
+		return "", fmt.Errorf("mgrs: invalid latitude %g", geo.Latitude)
+	}
+	zone, band := UTMZone(geo.Latitude, geo.Longitude)
+	u := geo.ToUTM()
+	e, n := math.Floor(u.Easting), math.Floor(u.Northing)
+
+	var prefix string
+	var col, row byte
+	if zone == 0 {
+		west := band == 'A' || band == 'Y'
+		fe, fn := 2000000.0, 1300000.0
+		if west {
+			fe = 800000
+		}
+		if !u.North {
+			fn = 800000
+		}
+		cols := mgrsUPSColumns[1]
+		if west {
+			cols = mgrsUPSColumns[0]
+		}
+		ci := int((e - fe) / 100000)
+		ri := int((n - fn) / 100000)
+		if e < fe {
+			ci = -1
+		}
+		if ci < 0 || ci >= len(cols) || ri < 0 || ri >= len(mgrsUPSRows) {
+			return "", fmt.Errorf("mgrs: UPS coordinate outside grid")
+		}
+		prefix = string(band)
+		col, row = cols[ci], mgrsUPSRows[ri]
+	} else {
+		ci := int(e/100000) - 1
+		if ci < 0 || ci > 7 {
+			return "", fmt.Errorf("mgrs: easting %.0f outside zone grid", e)
+		}
+		ri := (int(n/100000) + mgrsRowOffset(zone, geo.Ell)) % 20
+		prefix = fmt.Sprintf("%02d%c", zone, band)
+		col, row = mgrsColumns[(zone-1)%3][ci], mgrsRows[ri]
+	}
+
+	div := math.Pow(10, float64(5-digits))
+	ee := int(math.Mod(e, 100000) / div)
+	nn := int(math.Mod(n, 100000) / div)
+	if digits == 0 {
+		return fmt.Sprintf("%s%c%c", prefix, col, row), nil
+	}
+	return fmt.Sprintf("%s%c%c%0*d%0*d", prefix, col, row, digits, ee, digits, nn), nil
+}
+
+// ParseMGRS 解析 MGRS 字符串 (允许空格，不区分大小写)，返回格网方块西南角的 UTM 坐标与精度 (m)
+func ParseMGRS(s string, ell *Ellipsoid) (UTM, float64, error) {
+	s = strings.ToUpper(strings.Join(strings.Fields(s), ""))
+	i := 0
+	for i < len(s) && i < 2 && unicode.IsDigit(rune(s[i])) {
+		i++
+	}
+	zone := 0
+	if i > 0 {
+		zone, _ = strconv.Atoi(s[:i])
+		if zone < 1 || zone > 60 {
+			return UTM{}, 0, fmt.Errorf("mgrs %q: invalid zone %d", s, zone)
+		}
+	}
+	if len(s) < i+3 {
+		return UTM{}, 0, fmt.Errorf("mgrs %q: too short", s)
+	}
+	band, col, row := s[i], s[i+1], s[i+2]
+	digits := s[i+3:]
+	if len(digits)%2 != 0 || len(digits) > 10 {
+		return UTM{}, 0, fmt.Errorf("mgrs %q: need an even number of at most 10 digits", s)
+	}
+	for _, c := range digits {
+		if !unicode.IsDigit(c) {
+			return UTM{}, 0, fmt.Errorf("mgrs %q: invalid digit %q", s, c)
+		}
+	}
+	k := len(digits) / 2
+	prec := math.Pow(10, float64(5-k))
+	var de, dn float64
+	if k > 0 {
+		a, _ := strconv.Atoi(digits[:k])
+		b, _ := strconv.Atoi(digits[k:])
+		de, dn = float64(a)*prec, float64(b)*prec
+	}
+
+	if zone == 0 {
+		var west, north bool
+		switch band {
+		case 'A':
+			west = true
+		case 'B':
+		case 'Y':
+			west, north = true, true
+		case 'Z':
+			north = true
+		default:
+			return UTM{}, 0, fmt.Errorf("mgrs %q: invalid UPS band %c", s, band)
+		}
+		cols := mgrsUPSColumns[1]
+		fe, fn := 2000000.0, 800000.0
+		if west {
+			cols, fe = mgrsUPSColumns[0], 800000
+		}
+		if north {
+			fn = 1300000
+		}
+		ci := strings.IndexByte(cols, col)
+		ri := strings.IndexByte(mgrsUPSRows, row)
+		if ci < 0 || ri < 0 {
+			return UTM{}, 0, fmt.Errorf("mgrs %q: invalid 100 km square %c%c", s, col, row)
+		}
+		return UTM{Zone: 0, North: north, Easting: fe + float64(ci)*100000 + de,
+			Northing: fn + float64(ri)*100000 + dn, Ell: ell}, prec, nil
+	}
+
+	b := strings.IndexByte(mgrsBands, band)
+	ci := strings.IndexByte(mgrsColumns[(zone-1)%3], col)
+	ri := strings.IndexByte(mgrsRows, row)
+	if b < 0 {
+		return UTM{}, 0, fmt.Errorf("mgrs %q: invalid latitude band %c", s, band)
+	}
+	if ci < 0 || ri < 0 {
+		return UTM{}, 0, fmt.Errorf("mgrs %q: invalid 100 km square %c%c", s, col, row)
+	}
+	north := band >= 'N'
+	easting := float64(ci+1)*100000 + de
+
+	// 行字母确定 2000 km 周期内的北向坐标，由纬度带最小北向坐标确定周期
+	rowN := float64((ri-mgrsRowOffset(zone, ell)+40)%20) * 100000
+	latS := -80 + 8*float64(b)
+	proj := utmProjection(ell, zone, north)
+	minN := math.Inf(1)
+	for _, dl := range []float64{0, 3} {
+		if _, y, _, _ := proj.Project(latS, proj.LonOrigin+dl); y < minN {
+			minN = y
+		}
+	}
+	minN = math.Floor(minN/100000) * 100000
+	northing := rowN + 2e6*math.Ceil((minN-rowN)/2e6) + dn
+	return UTM{Zone: zone, North: north, Easting: easting, Northing: northing, Ell: ell}, prec, nil
+}
+
+// MGRSToGeodetic 解析 MGRS 字符串，返回格网方块中心的大地坐标 (高度为 0)
+func MGRSToGeodetic(s string, ell *Ellipsoid) (Geodetic, error) {
+	u, prec, err := ParseMGRS(s, ell)
+	if err != nil {
+		return Geodetic{}, err
+	}
+	u.Easting += prec / 2
+	u.Northing += prec / 2
+	return u.ToGeodetic()
+}
@@ -0,0 +1,149 @@
+package gomap3d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestUTMZone(t *testing.T) {
+	cases := []struct {
+		lat, lon float64
+		zone     int
+		band     byte
+	}{
+		{39.9, 116.4, 50, 'S'},
+		{-33.9, 151.2, 56, 'H'},
+		{0, -180, 1, 'N'},
+		{0, 179.9, 60, 'N'},
+		{-80, 0, 31, 'C'},
+		{83.9, 0, 31, 'X'},
+		{60, 5, 32, 'V'}, // 挪威
+		{60, 2.9, 31, 'V'},
+		{78, 8, 31, 'X'}, // 斯瓦尔巴
+		{78, 15, 33, 'X'},
+		{78, 32, 35, 'X'},
+		{78, 40, 37, 'X'},
+		{85, 10, 0, 'Z'},
+		{85, -10, 0, 'Y'},
+		{-85, -10, 0, 'A'},
+		{-85, 10, 0, 'B'},
+	}
+	for _, c := range cases {
+		if z, b := UTMZone(c.lat, c.lon); z != c.zone || b != c.band {
+			t.Errorf("UTMZone(%g, %g) = %d%c, want %d%c", c.lat, c.lon, z, b, c.zone, c.band)
+		}
+	}
+}
+
+func TestUTM(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	// 中央经线与赤道交点
+	geo := Geodetic{Latitude: 0, Longitude: 3, Ell: wgs}
+	if u := geo.ToUTM(); u.Zone != 31 || !u.North || math.Abs(u.Easting-500000) > 1e-9 || math.Abs(u.Northing) > 1e-9 {
+		t.Errorf("equator on CM: %+v", u)
+	}
+	// 南半球北伪偏移
+	geo = Geodetic{Latitude: -1e-9, Longitude: 3, Ell: wgs}
+	if u := geo.ToUTM(); u.North || math.Abs(u.Northing-10000000) > 1e-3 {
+		t.Errorf("southern hemisphere: %+v", u)
+	}
+	// UPS: 极点位于 (2000 km, 2000 km)，84.5°N 处 ρ ≈ 0.994 × 5.5° 子午线弧长
+	for _, lat := range []float64{90, -90} {
+		geo = Geodetic{Latitude: lat, Longitude: 0, Ell: wgs}
+		if u := geo.ToUTM(); u.Zone != 0 || math.Abs(u.Easting-2e6) > 1e-6 || math.Abs(u.Northing-2e6) > 1e-6 {
+			t.Errorf("pole %g: %+v", lat, u)
+		}
+	}
+	geo = Geodetic{Latitude: 84.5, Longitude: 90, Ell: wgs}
+	if u := geo.ToUTM(); u.Zone != 0 || math.Abs(u.Easting-2e6-611.08e3) > 0.1e3 || math.Abs(u.Northing-2e6) > 1e-6 {
+		t.Errorf("UPS north: %+v", u)
+	}
+
+	// 往返
+	rnd := rand.New(rand.NewSource(2))
+	for i := 0; i < 500; i++ {
+		geo := Geodetic{Latitude: rnd.Float64()*180 - 90, Longitude: rnd.Float64()*360 - 180, Ell: wgs}
+		u := geo.ToUTM()
+		back, err := u.ToGeodetic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(back.Latitude-geo.Latitude) > 1e-9 ||
+			(math.Abs(geo.Latitude) < 89.9 && math.Abs(math.Remainder(back.Longitude-geo.Longitude, 360)) > 1e-9) {
+			t.Fatalf("round trip %+v → %+v → %+v", geo, u, back)
+		}
+	}
+	if _, err := (&Geodetic{Ell: wgs}).ToUTMZone(61); err == nil {
+		t.Error("expected error for zone 61")
+	}
+	if _, err := (UTM{Zone: -1, Ell: wgs}).ToGeodetic(); err == nil {
+		t.Error("expected error for zone -1")
+	}
+}
+
+func TestMGRS(t *testing.T) {
+	wgs, _ := NewEllipsoid("wgs84")
+	cases := []struct {
+		lat, lon float64
+		digits   int
+		want     string
+	}{
+		{33.3, 44.4, 2, "38SMB4484"}, // 巴格达 (GeographicLib 算例)
+		{33.3, 44.4, 0, "38SMB"},
+		{90, 0, 5, "ZAH0000000000"},
+		{-90, 0, 5, "BAN0000000000"},
+	}
+	for _, c := range cases {
+		geo := Geodetic{Latitude: c.lat, Longitude: c.lon, Ell: wgs}
+		got, err := geo.ToMGRS(c.digits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("ToMGRS(%g, %g, %d) = %s, want %s", c.lat, c.lon, c.digits, got, c.want)
+		}
+	}
+	if _, err := (&Geodetic{Ell: wgs}).ToMGRS(6); err == nil {
+		t.Error("expected error for 6 digits")
+	}
+
+	// 各精度往返: 解析结果为包含原点的格网方块
+	clrk, _ := NewEllipsoid("clrk66")
+	rnd := rand.New(rand.NewSource(3))
+	for _, ell := range []*Ellipsoid{wgs, clrk} {
+		for i := 0; i < 400; i++ {
+			geo := Geodetic{Latitude: rnd.Float64()*180 - 90, Longitude: rnd.Float64()*360 - 180, Ell: ell}
+			u := geo.ToUTM()
+			for digits := 0; digits <= 5; digits++ {
+				s, err := geo.ToMGRS(digits)
+				if err != nil {
+					t.Fatalf("%+v: %v", geo, err)
+				}
+				p, prec, err := ParseMGRS(s, ell)
+				if err != nil {
+					t.Fatalf("%s: %v", s, err)
+				}
+				if p.Zone != u.Zone || p.North != u.North || prec != math.Pow(10, float64(5-digits)) ||
+					u.Easting < p.Easting || u.Easting >= p.Easting+prec ||
+					u.Northing < p.Northing || u.Northing >= p.Northing+prec {
+					t.Fatalf("%s (%s): parsed %+v, want square containing %+v", s, ell.Model, p, u)
+				}
+			}
+		}
+	}
+
+	// 空格与小写
+	g, err := MGRSToGeodetic("38s mb 44 84", wgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(g.Latitude-33.3) > 0.01 || math.Abs(g.Longitude-44.4) > 0.01 {
+		t.Errorf("MGRSToGeodetic: %+v", g)
+	}
+	for _, s := range []string{"38SMB448", "61SMB", "38IMB", "38SIB", "CAH", "38SMB44x4", "38S"} {
+		if _, _, err := ParseMGRS(s, wgs); err == nil {
+			t.Errorf("ParseMGRS(%q): expected error", s)
+		}
+	}
+}
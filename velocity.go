@@ -13,85 +13,21 @@ const (
 
 // ===== ECEF 速度 ↔ ECI 速度 =====
 
-// ECEFVel2ECIVel 将 ECEF 速度转换为 ECI 速度。
+// ECEFVel2ECIVel 将 ECEF 速度转换为 ECI 速度（默认转换器）。
 //
 // 公式: v_eci = M^T · (v_ecef + ω × r_ecef)
-// 默认 M = Rz(GMST)（GMST 模式），SetGMSTMode(false) 后 M = GCRF2ITRF
+// 默认 M = Rz(GMST)（GMST 模式），SetGMSTMode(false) 后 M = GCRF2ITRFEOP
 func ECEFVel2ECIVel(vx, vy, vz, x, y, z float64, t time.Time) (vxEci, vyEci, vzEci float64) {
-	jd := juliandate(t)
-	var M [3][3]float64
-	if useSimpleGMST {
-		gmst := greenwichsrt(jd)
-		cosG := math.Cos(gmst)
-		sinG := math.Sin(gmst)
-		M = [3][3]float64{
-			{cosG, sinG, 0},
-			{-sinG, cosG, 0},
-			{0, 0, 1},
-		}
-	} else {
-		M = GCRF2ITRF(jd)
-		M = transpose(M)
-	}
-
-	// ω × r_ecef
-	wxr := [3]float64{
-		-We * y,
-		We * x,
-		0,
-	}
-
-	// v_ecef + ω × r_ecef
-	v := [3]float64{
-		vx + wxr[0],
-		vy + wxr[1],
-		vz + wxr[2],
-	}
-
-	// M^T · (v_ecef + ω × r_ecef)
-	result := multiplyMatrixVector(M, v)
-	return result[0], result[1], result[2]
+	return DefaultTransformer().ECEFVel2ECIVel(vx, vy, vz, x, y, z, t)
 }
 
-// ECIVel2ECEFVel 将 ECI 速度转换为 ECEF 速度。
+// ECIVel2ECEFVel 将 ECI 速度转换为 ECEF 速度（默认转换器）。
 //
 // 公式: v_ecef = M · v_eci - ω × r_ecef
 // 其中 r_ecef = M · r_eci
-// 默认 M = Rz(GMST)（GMST 模式），SetGMSTMode(false) 后 M = GCRF2ITRF
+// 默认 M = Rz(GMST)（GMST 模式），SetGMSTMode(false) 后 M = GCRF2ITRFEOP
 func ECIVel2ECEFVel(vx, vy, vz, x, y, z float64, t time.Time) (vxEcef, vyEcef, vzEcef float64) {
-	jd := juliandate(t)
-	var M [3][3]float64
-	if useSimpleGMST {
-		gmst := greenwichsrt(jd)
-		cosG := math.Cos(gmst)
-		sinG := math.Sin(gmst)
-		// Rz(+GMST)
-		M = [3][3]float64{
-			{cosG, -sinG, 0},
-			{sinG, cosG, 0},
-			{0, 0, 1},
-		}
-	} else {
-		M = GCRF2ITRF(jd)
-	}
-
-	// r_ecef = M · r_eci
-	rEci := [3]float64{x, y, z}
-	rEcef := multiplyMatrixVector(M, rEci)
-
-	// ω × r_ecef
-	wxr := [3]float64{
-		-We * rEcef[1],
-		We * rEcef[0],
-		0,
-	}
-
-	// v_ecef_rot = M · v_eci
-	vEci := [3]float64{vx, vy, vz}
-	vRot := multiplyMatrixVector(M, vEci)
-
-	// v_ecef = M · v_eci - ω × r_ecef
-	return vRot[0] - wxr[0], vRot[1] - wxr[1], vRot[2] - wxr[2]
+	return DefaultTransformer().ECIVel2ECEFVel(vx, vy, vz, x, y, z, t)
 }
 
 // ===== ENU 速度 ↔ ECEF 速度 =====
@@ -239,6 +175,11 @@ func ENUVel2AERDeriv(eVel, nVel, uVel, R, azDeg, elDeg float64) (dR, dAzDeg, dEl
 //
 // 输出: ECI 速度 (vx, vy, vz) (m/s)
 func AERDeriv2ECIVel(R, azDeg, elDeg, dR, dAzDeg, dElDeg, latDeg, lonDeg, alt float64, t time.Time) (vx, vy, vz float64) {
+	return DefaultTransformer().AERDeriv2ECIVel(R, azDeg, elDeg, dR, dAzDeg, dElDeg, latDeg, lonDeg, alt, t)
+}
+
+// AERDeriv2ECIVel 同 AERDeriv2ECIVel，使用转换器 tr 的模型与 EOP
+func (tr *Transformer) AERDeriv2ECIVel(R, azDeg, elDeg, dR, dAzDeg, dElDeg, latDeg, lonDeg, alt float64, t time.Time) (vx, vy, vz float64) {
 	// 1. AER 变化率 → ENU 速度
 	eVel, nVel, uVel := AERDeriv2ENUVel(R, azDeg, elDeg, dR, dAzDeg, dElDeg)
 
@@ -255,7 +196,7 @@ func AERDeriv2ECIVel(R, azDeg, elDeg, dR, dAzDeg, dElDeg, latDeg, lonDeg, alt fl
 	px, py, pz := ENU2ECEF(ex, nx, ux, latDeg, lonDeg, alt, ell)
 
 	// 4. ECEF 速度 → ECI 速度
-	return ECEFVel2ECIVel(vxE, vyE, vzE, px, py, pz, t)
+	return tr.ECEFVel2ECIVel(vxE, vyE, vzE, px, py, pz, t)
 }
 
 // ECIVel2AERDeriv 将 ECI 速度一步反算为 AER 变化率（RAE 导数）。
@@ -274,8 +215,13 @@ func AERDeriv2ECIVel(R, azDeg, elDeg, dR, dAzDeg, dElDeg, latDeg, lonDeg, alt fl
 //   - dAzDeg: 方位角变化率 (deg/s)
 //   - dElDeg: 俯仰角变化率 (deg/s)
 func ECIVel2AERDeriv(vx, vy, vz, Rx, Ry, Rz, latDeg, lonDeg, alt float64, t time.Time) (dR, dAzDeg, dElDeg float64) {
+	return DefaultTransformer().ECIVel2AERDeriv(vx, vy, vz, Rx, Ry, Rz, latDeg, lonDeg, alt, t)
+}
+
+// ECIVel2AERDeriv 同 ECIVel2AERDeriv，使用转换器 tr 的模型与 EOP
+func (tr *Transformer) ECIVel2AERDeriv(vx, vy, vz, Rx, Ry, Rz, latDeg, lonDeg, alt float64, t time.Time) (dR, dAzDeg, dElDeg float64) {
 	// 1. ECI 速度 → ECEF 速度
-	vxE, vyE, vzE := ECIVel2ECEFVel(vx, vy, vz, Rx, Ry, Rz, t)
+	vxE, vyE, vzE := tr.ECIVel2ECEFVel(vx, vy, vz, Rx, Ry, Rz, t)
 
 	// 2. ECEF 速度 → ENU 速度
 	eVel, nVel, uVel := ECEFVel2ENUVel(vxE, vyE, vzE, latDeg, lonDeg)
@@ -284,7 +230,7 @@ func ECIVel2AERDeriv(vx, vy, vz, Rx, Ry, Rz, latDeg, lonDeg, alt float64, t time
 	ell, _ := NewEllipsoid("wgs84")
 
 	// ECI 位置 → ECEF 位置
-	rEcefX, rEcefY, rEcefZ := ECI2ECEF(Rx, Ry, Rz, t)
+	rEcefX, rEcefY, rEcefZ := tr.ECI2ECEF(Rx, Ry, Rz, t)
 
 	// ECEF 位置 → ENU 位置（使用实际测站海拔）
 	en, nn, un := ECEF2ENU(rEcefX, rEcefY, rEcefZ, latDeg, lonDeg, alt, ell)
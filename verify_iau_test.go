@@ -1,7 +1,6 @@
 package gomap3d
 
 import (
-	"fmt"
 	"math"
 	"testing"
 )
@@ -32,12 +31,12 @@ func TestPrecessionAnglesCoefficients(t *testing.T) {
 	T := 0.0 // J2000.0
 	zeta, z, theta := precessionAngles(T)
 
-	// 在 J2000.0, 所有岁差角应为 0
-	if math.Abs(zeta) > tolPrecessionRad {
-		t.Errorf("ζ at T=0: expected 0, got %.6e rad (%.6f mas)", zeta, zeta/DAS2R*1000)
+	// 在 J2000.0, IAU 2006 的 ζ_A、z_A 含常数项 ±2.650545"，θ_A 为 0
+	if math.Abs(zeta-2.650545*DAS2R) > tolPrecessionRad {
+		t.Errorf("ζ at T=0: expected 2.650545\", got %.6e rad (%.6f mas)", zeta, zeta/DAS2R*1000)
 	}
-	if math.Abs(z) > tolPrecessionRad {
-		t.Errorf("z at T=0: expected 0, got %.6e rad (%.6f mas)", z, z/DAS2R*1000)
+	if math.Abs(z+2.650545*DAS2R) > tolPrecessionRad {
+		t.Errorf("z at T=0: expected -2.650545\", got %.6e rad (%.6f mas)", z, z/DAS2R*1000)
 	}
 	if math.Abs(theta) > tolPrecessionRad {
 		t.Errorf("θ at T=0: expected 0, got %.6e rad (%.6f mas)", theta, theta/DAS2R*1000)
@@ -155,21 +154,12 @@ func TestNutationAtJ2000(t *testing.T) {
 	t.Logf("Δψ = %.15e rad", dpsi)
 	t.Logf("Δε = %.15e rad", deps)
 
-	// SOFA 参考值（通过 SOFA iauNut00b 在 T=0 的输出）
-	// 注意: 精确值需运行 SOFA 库获取，这里用合理范围
-	// IAU 2000B 在 J2000.0 的 Δψ ≈ -0.014... arcsec, Δε ≈ -0.002... arcsec
-	if math.Abs(dpsiAS) > 0.1 {
-		t.Errorf("Δψ at J2000.0 too large: %.6f arcsec", dpsiAS)
+	// SOFA iauNut00b 在 J2000.0 的输出: Δψ ≈ -13.9317", Δε ≈ -5.7694"
+	if math.Abs(dpsiAS-(-13.9317)) > 0.001 {
+		t.Errorf("Δψ at J2000.0 mismatch: %.6f arcsec", dpsiAS)
 	}
-	if math.Abs(depsAS) > 0.1 {
-		t.Errorf("Δε at J2000.0 too large: %.6f arcsec", depsAS)
-	}
-
-	// 验证章动量级合理: 在 J2000.0, 总章动约 0.01-0.1 arcsec
-	// 更精确地说, IAU 2000B 在 J2000.0 的 Δψ ≈ -0.004  arcsec
-	// 这里需要实际 SOFA 输出对照
-	if math.Abs(dpsiAS) < 0.001 {
-		t.Logf("⚠️ Δψ = %.6f arcsec — 值偏小，请与 SOFA iauNut00b 对照", dpsiAS)
+	if math.Abs(depsAS-(-5.7694)) > 0.001 {
+		t.Errorf("Δε at J2000.0 mismatch: %.6f arcsec", depsAS)
 	}
 }
 
@@ -221,7 +211,7 @@ func TestNutationMatrixOrthogonal(t *testing.T) {
 
 func TestGCRF2ITRFMatrix(t *testing.T) {
 	jd := 2451545.0 // J2000.0
-	M := GCRF2ITRF(jd)
+	M := GCRF2ITRF(jd, jd)
 
 	t.Logf("=== GCRF→ITRF 矩阵在 J2000.0 (JD=2451545.0) ===")
 	for i := 0; i < 3; i++ {
@@ -245,12 +235,11 @@ func TestGCRF2ITRFMatrix(t *testing.T) {
 
 	// GCRF2ITRF 在 J2000.0 接近 Rz(GAST) 矩阵
 	// 因为岁差和章动在 T=0 都很小
-	gast := GAST(jd)
+	gast := GAST(jd, jd)
 	t.Logf("GAST at J2000.0 = %.10f rad = %.10f°", gast, gast*180/math.Pi)
 
 	// 验证 M ≈ Rz(GAST) × B (近似, 因为 P≈I, N≈I 在 T=0)
 	// B 接近单位矩阵, 所以 M 应接近 Rz(GAST)
-	s, c := math.Sin(gast), math.Cos(gast)
 	for i := 0; i < 3; i++ {
 		for j := 0; j < 3; j++ {
 			if i == j {
@@ -272,7 +261,7 @@ func TestMatrixChainComponents(t *testing.T) {
 	B := frameBiasMatrix()
 	P := precessionMatrix(T)
 	N := nutationMatrix(T)
-	gast := GAST(jd)
+	gast := GAST(jd, jd)
 	R := R3(gast)
 
 	t.Logf("=== 组件矩阵在 J2000.0 ===")
@@ -291,7 +280,7 @@ func TestMatrixChainComponents(t *testing.T) {
 
 	// 完整链
 	M := mul33(R, mul33(N, mul33(P, B)))
-	Mref := GCRF2ITRF(jd)
+	Mref := GCRF2ITRF(jd, jd)
 	Mdiff := matrixDiff(M, Mref)
 	if Mdiff > 1e-15 {
 		t.Errorf("M matrix chain mismatch: %.2e", Mdiff)
@@ -309,8 +298,8 @@ func TestRotationOrderConvention(t *testing.T) {
 	v := [3]float64{0, 1, 0}
 	v2 := multMv(R90x, v)
 
-	// Rx(90°) 将 (0,1,0) 映射到 (0,0,1)
-	if math.Abs(v2[0]) > 1e-15 || math.Abs(v2[1]) > 1e-12 || math.Abs(v2[2]-1.0) > 1e-12 {
+	// Rx 为坐标系旋转（被动）约定，与 SOFA iauRx 一致: Rx(90°) 将 (0,1,0) 映射到 (0,0,-1)
+	if math.Abs(v2[0]) > 1e-15 || math.Abs(v2[1]) > 1e-12 || math.Abs(v2[2]+1.0) > 1e-12 {
 		t.Errorf("Rx(90°) convention check failed: v = [%.10f, %.10f, %.10f]", v2[0], v2[1], v2[2])
 	}
 	t.Logf("Rx(90°) · (0,1,0) = (%.2f,%.2f,%.2f) — 正符号约定验证通过", v2[0], v2[1], v2[2])
@@ -337,20 +326,20 @@ func TestRotationOrderConvention(t *testing.T) {
 func TestGASTvsGMSTQuantitative(t *testing.T) {
 	jd := 2451545.0
 	gmst := greenwichsrt(jd)
-	gast := GAST(jd)
+	gast := GAST(jd, jd)
 
 	// GMST at J2000.0: 6h 41m 07.584s = 100.2816°
 	// 用弧度表示: 100.2816 × π/180 = 1.7502 rad
 	t.Logf("GMST at J2000.0 = %.10f rad (%.10f°)", gmst, gmst*180/math.Pi)
 	t.Logf("GAST at J2000.0 = %.10f rad (%.10f°)", gast, gast*180/math.Pi)
 
-	// 分点差 (equation of equinoxes) = GAST - GMST = Δψ × cos(ε)
-	eeq := gast - gmst
+	// 分点差 (equation of equinoxes) = GAST - GMST06 = Δψ × cos(ε) + 补充项
+	eeq := gast - GMST06(jd, jd)
 	eeqAS := eeq / DAS2R
 	t.Logf("Equation of equinoxes at J2000.0 = %.10f arcsec", eeqAS)
 
-	// 应该在 ~0.1 arcsec 量级
-	if math.Abs(eeqAS) > 1.0 {
+	// J2000.0 时 Δψ ≈ -13.93", 分点差 ≈ Δψ·cos ε ≈ -12.78"
+	if math.Abs(eeqAS-(-12.78)) > 0.01 {
 		t.Errorf("equation of equinoxes too large: %.4f arcsec", eeqAS)
 	}
 }
@@ -404,13 +393,13 @@ func TestPrintDetailedValues(t *testing.T) {
 	t.Logf("  ε₀ (J2000.0) = %.6f arcsec = %.10f°", eps0/DAS2R, eps0*180/math.Pi)
 	t.Logf("  ε  (2024.5)  = %.6f arcsec = %.10f°", eps2024/DAS2R, eps2024*180/math.Pi)
 	// 真黄赤交角
-	dpsi2024rad, deps2024rad := nutation(T2024)
+	_, deps2024rad := nutation(T2024)
 	epsTrue2024 := meanObliquity(T2024) + deps2024rad
 	t.Logf("  ε_true (2024.5) = %.6f arcsec = %.10f°", epsTrue2024/DAS2R, epsTrue2024*180/math.Pi)
 
 	// 完整矩阵
 	jd := 2451545.0
-	M := GCRF2ITRF(jd)
+	M := GCRF2ITRF(jd, jd)
 	t.Logf("")
 	t.Logf("[GCRF→ITRF 矩阵 - J2000.0]")
 	for i := 0; i < 3; i++ {
@@ -419,11 +408,11 @@ func TestPrintDetailedValues(t *testing.T) {
 
 	// 2024.5
 	jd2024 := 2460480.5 // 近似 2024.5 的 JD
-	M2024 := GCRF2ITRF(jd2024)
+	M2024 := GCRF2ITRF(jd2024, jd2024)
 	t.Logf("")
 	t.Logf("[GCRF→ITRF 矩阵 - 2024.5]")
 	for i := 0; i < 3; i++ {
-		t.Logf("  [%.15f, %.15f, %.15f]", M[i][0], M[i][1], M[i][2])
+		t.Logf("  [%.15f, %.15f, %.15f]", M2024[i][0], M2024[i][1], M2024[i][2])
 	}
 
 	// 输出 GCRF2ITRF 矩阵在 MATLAB dcmeci2ecef 示例用的日期
@@ -431,7 +420,7 @@ func TestPrintDetailedValues(t *testing.T) {
 	// 对应的 JD (近似) = 2451545.5 + 11.5 + (4*3600+52*60+12.4)/86400
 	// = 2451557.702921296
 	jdMatlab := 2451557.702921296
-	MMatlab := GCRF2ITRF(jdMatlab)
+	MMatlab := GCRF2ITRF(jdMatlab, jdMatlab)
 	t.Logf("")
 	t.Logf("[GCRF→ITRF 矩阵 - MATLAB 示例日期 2000-01-12 04:52:12.4 UTC]")
 	t.Logf("  (对照 MATLAB dcmeci2ecef('IAU-2000/2006',...) 输出)")
@@ -0,0 +1,175 @@
+package gomap3d
+
+import "math"
+
+// ============================================================
+// 视线可视性与雷达地平
+//
+// 标准大气中电波射线向地面弯曲，曲率约为地球曲率的 1/4，常以等效地球半径 kR
+// (k = 4/3) 代替真实地球、将射线视为直线。两点间可视性在真实椭球上计算：
+// 射线相对直线弦向上凸起 δ(s) = c·s(D-s)/2，c = (1 - 1/k)/R 为射线曲率，
+// R 取弦中点沿视线方位的法截线曲率半径；射线高度为弦上各点大地高加 δ。
+// k = 1 为几何 (光学) 视线。
+// ============================================================
+
+// DefaultKFactor 标准大气等效地球半径系数
+const DefaultKFactor = 4.0 / 3
+
+// VisibilityOptions 可视性选项，nil 等价于零值
+type VisibilityOptions struct {
+	KFactor      float64 // 等效地球半径系数，0 为 DefaultKFactor；几何视线取 1
+	MinClearance float64 // 视线沿途 (不含两端点) 需高出椭球面的最小余隙 (m)
+}
+
+func (o *VisibilityOptions) kFactor() float64 {
+	if o == nil || o.KFactor == 0 {
+		return DefaultKFactor
+	}
+	return o.KFactor
+}
+
+func (o *VisibilityOptions) minClearance() float64 {
+	if o == nil {
+		return 0
+	}
+	return o.MinClearance
+}
+
+// Visibility 两点间视线可视性
+type Visibility struct {
+	Visible   bool     // 视线沿途余隙不低于 MinClearance
+	Clearance float64  // 射线沿途最低点的大地高 (m)，被地球遮挡时为负；射线自两端单调升高时为 +Inf
+	Grazing   Geodetic // 射线沿途最低点，高度同 Clearance；无沿途最低点时为起点
+	SRange    float64  // 两点直线距离 (m)
+}
+
+// RadiusOfCurvature 椭球在纬度 lat 处方位 az (°) 法截线的曲率半径 (m)
+// Euler 公式 1/R = cos²α/M + sin²α/N，M、N 为子午圈与卯酉圈曲率半径。
+func RadiusOfCurvature(lat, az float64, ell *Ellipsoid) float64 {
+	e2 := ell.Eccentricity * ell.Eccentricity
+	s := math.Sin(lat * math.Pi / 180)
+	w := 1 - e2*s*s
+	n := ell.SemimajorAxis / math.Sqrt(w)
+	m := n * (1 - e2) / w
+	sa, ca := math.Sincos(az * math.Pi / 180)
+	return 1 / (ca*ca/m + sa*sa/n)
+}
+
+// RadarHorizon 天线高 h1 与目标高 h2 (m) 在半径 radius 的地球上的最大视线距离 (m)
+// 即两者到等效地球 (半径 kR) 切线长之和；k 为 0 时取 DefaultKFactor。
+func RadarHorizon(h1, h2, radius, k float64) float64 {
+	if k == 0 {
+		k = DefaultKFactor
+	}
+	re := k * radius
+	tangent := func(h float64) float64 {
+		if h <= 0 {
+			return 0
+		}
+		return math.Sqrt(h * (2*re + h))
+	}
+	return tangent(h1) + tangent(h2)
+}
+
+// RadarHorizon 以 geo 的高度为天线高，沿方位 az (°) 对高度 targetHeight (m) 目标的最大视线距离 (m)
+func (geo *Geodetic) RadarHorizon(targetHeight, az, k float64) float64 {
+	return RadarHorizon(geo.Altitude, targetHeight, RadiusOfCurvature(geo.Latitude, az, geo.Ell), k)
+}
+
+// sightLine 两点间的射线: 直线弦加折射弯曲
+type sightLine struct {
+	ell        *Ellipsoid
+	x1, y1, z1 float64
+	dx, dy, dz float64
+	d          float64 // 弦长 (m)
+	c          float64 // 射线曲率 (1/m)
+}
+
+func newSightLine(from, to Geodetic, k float64) sightLine {
+	ell := from.Ell
+	x1, y1, z1 := Geodetic2ECEF(from.Latitude, from.Longitude, from.Altitude, ell)
+	x2, y2, z2 := Geodetic2ECEF(to.Latitude, to.Longitude, to.Altitude, ell)
+	l := sightLine{ell: ell, x1: x1, y1: y1, z1: z1, dx: x2 - x1, dy: y2 - y1, dz: z2 - z1}
+	l.d = math.Sqrt(l.dx*l.dx + l.dy*l.dy + l.dz*l.dz)
+	if l.d > 0 {
+		// R 取弦中点沿视线方位的曲率半径
+		mlat, _, _ := ECEF2Geodetic(x1+l.dx/2, y1+l.dy/2, z1+l.dz/2, ell)
+		az := to.ToAER(from).Azimuth
+		l.c = (1 - 1/k) / RadiusOfCurvature(mlat, az, ell)
+	}
+	return l
+}
+
+// at 距起点 s (m) 处弦上点的经纬度与射线大地高
+func (l sightLine) at(s float64) (lat, lon, h float64) {
+	r := s / l.d
+	lat, lon, h = ECEF2Geodetic(l.x1+r*l.dx, l.y1+r*l.dy, l.z1+r*l.dz, l.ell)
+	return lat, lon, h + l.c*s*(l.d-s)/2
+}
+
+// VisibilityTo 计算 geo 到 target 的视线可视性，两点均视为 geo.Ell 上的坐标
+// 只有射线高度在两点之间的极小值 (地球凸起) 构成遮挡；端点自身的高度不计入余隙，
+// 低于椭球面的测站或低于 MinClearance 的天线仍可看到仰视方向的目标。
+func (geo *Geodetic) VisibilityTo(target Geodetic, opts *VisibilityOptions) Visibility {
+	l := newSightLine(*geo, target, opts.kFactor())
+	d := l.d
+	if d == 0 {
+		h := math.Min(geo.Altitude, target.Altitude)
+		g := *geo
+		g.Altitude = h
+		return Visibility{Visible: h >= opts.minClearance(), Clearance: h, Grazing: g}
+	}
+	height := func(s float64) float64 {
+		_, _, h := l.at(s)
+		return h
+	}
+
+	// 粗采样定位最低点所在区间，再黄金分割精化
+	const samples = 32
+	best, bestH := 0, height(0)
+	for i := 1; i <= samples; i++ {
+		if h := height(d * float64(i) / samples); h < bestH {
+			best, bestH = i, h
+		}
+	}
+	lo := d * float64(best-1) / samples
+	if best == 0 {
+		lo = 0
+	}
+	hi := d * float64(imin(best+1, samples)) / samples
+	const gr = 0.6180339887498949
+	a, b := hi-gr*(hi-lo), lo+gr*(hi-lo)
+	fa, fb := height(a), height(b)
+	for hi-lo > 1e-3 {
+		if fa < fb {
+			hi, b, fb = b, a, fa
+			a = hi - gr*(hi-lo)
+			fa = height(a)
+		} else {
+			lo, a, fa = a, b, fb
+			b = lo + gr*(hi-lo)
+			fb = height(b)
+		}
+	}
+	s := (lo + hi) / 2
+	if h := height(s); h > bestH {
+		s = d * float64(best) / samples
+	}
+	// 最低点落在端点: 射线自两端单调升高，沿途无地球遮挡
+	if s <= 1e-3 || s >= d-1e-3 {
+		return Visibility{Visible: true, Clearance: math.Inf(1), Grazing: *geo, SRange: d}
+	}
+	lat, lon, h := l.at(s)
+
+	return Visibility{
+		Visible:   h >= opts.minClearance(),
+		Clearance: h,
+		Grazing:   Geodetic{Latitude: lat, Longitude: lon, Altitude: h, Ell: geo.Ell},
+		SRange:    d,
+	}
+}
+
+// IsVisible 判断 geo 与 target 间视线是否不受地球曲率遮挡
+func (geo *Geodetic) IsVisible(target Geodetic, opts *VisibilityOptions) bool {
+	return geo.VisibilityTo(target, opts).Visible
+}
@@ -0,0 +1,115 @@
+package gomap3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRadarHorizon(t *testing.T) {
+	// 经验公式 d ≈ 4.12(√h1 + √h2) km (k = 4/3, R = 6371 km)
+	if d := RadarHorizon(100, 0, 6371e3, 0); math.Abs(d-41.2e3) > 100 {
+		t.Errorf("100 m antenna: %.1f", d)
+	}
+	if d := RadarHorizon(100, 400, 6371e3, 1); math.Abs(d-math.Sqrt(100*(2*6371e3+100))-math.Sqrt(400*(2*6371e3+400))) > 1e-6 {
+		t.Errorf("geometric: %.6f", d)
+	}
+	if d := RadarHorizon(0, 0, 6371e3, 0); d != 0 {
+		t.Errorf("ground level: %g", d)
+	}
+
+	wgs, _ := NewEllipsoid("wgs84")
+	a, b := wgs.SemimajorAxis, wgs.SemiminorAxis
+	e2 := wgs.Eccentricity * wgs.Eccentricity
+	if r := RadiusOfCurvature(0, 0, wgs); math.Abs(r-a*(1-e2)) > 1e-6 {
+		t.Errorf("meridian at equator: %.6f", r)
+	}
+	if r := RadiusOfCurvature(0, 90, wgs); math.Abs(r-a) > 1e-6 {
+		t.Errorf("prime vertical at equator: %.6f", r)
+	}
+	if r := RadiusOfCurvature(90, 37, wgs); math.Abs(r-a*a/b) > 1e-6 {
+		t.Errorf("pole: %.6f", r)
+	}
+	geo := Geodetic{Latitude: 0, Altitude: 100, Ell: wgs}
+	if d := geo.RadarHorizon(0, 90, 1); math.Abs(d-math.Sqrt(100*(2*a+100))) > 1e-6 {
+		t.Errorf("Geodetic.RadarHorizon: %.6f", d)
+	}
+}
+
+func TestVisibility(t *testing.T) {
+	venus, _ := NewEllipsoid("venus")
+	R := venus.SemimajorAxis
+	geometric := &VisibilityOptions{KFactor: 1}
+
+	// 球体: 等高两点间弦中点高度 (R+h)cosθ - R
+	h, theta := 2000.0, 1.0
+	a := Geodetic{Latitude: 10, Longitude: 20, Altitude: h, Ell: venus}
+	b := Geodetic{Latitude: 10 + 2*theta, Longitude: 20, Altitude: h, Ell: venus}
+	v := a.VisibilityTo(b, geometric)
+	want := (R+h)*math.Cos(theta*math.Pi/180) - R
+	if math.Abs(v.Clearance-want) > 1e-3 || v.Visible != (want >= 0) || math.Abs(v.Grazing.Latitude-11) > 1e-6 {
+		t.Errorf("sphere chord: %+v, want clearance %.4f", v, want)
+	}
+
+	// 地平处余隙为 0: 几何地平与等效地球 (kR) 地平
+	h1, h2 := 100.0, 1000.0
+	for _, k := range []float64{1, DefaultKFactor} {
+		th := k * (math.Acos(k*R/(k*R+h1)) + math.Acos(k*R/(k*R+h2))) * 180 / math.Pi
+		opts := &VisibilityOptions{KFactor: k}
+		a := Geodetic{Latitude: 0, Longitude: 0, Altitude: h1, Ell: venus}
+		at := Geodetic{Latitude: 0, Longitude: th, Altitude: h2, Ell: venus}
+		v := a.VisibilityTo(at, opts)
+		if math.Abs(v.Clearance) > 0.5 {
+			t.Errorf("k = %g horizon: clearance %.4f", k, v.Clearance)
+		}
+		if k == 1 && math.Abs(v.SRange-RadarHorizon(h1, h2, R, 1)) > 1e-3 {
+			t.Errorf("horizon range %.4f vs %.4f", v.SRange, RadarHorizon(h1, h2, R, 1))
+		}
+		near := Geodetic{Latitude: 0, Longitude: th * 0.97, Altitude: h2, Ell: venus}
+		far := Geodetic{Latitude: 0, Longitude: th * 1.03, Altitude: h2, Ell: venus}
+		if !a.IsVisible(near, opts) || a.IsVisible(far, opts) {
+			t.Errorf("k = %g: near/far visibility wrong", k)
+		}
+	}
+
+	// WGS84: 北京两点互视，北京与纽约被地球遮挡；余隙要求
+	wgs, _ := NewEllipsoid("wgs84")
+	bj := Geodetic{Latitude: 39.9042, Longitude: 116.4074, Altitude: 50, Ell: wgs}
+	tower := Geodetic{Latitude: 39.95, Longitude: 116.5, Altitude: 300, Ell: wgs}
+	v = bj.VisibilityTo(tower, nil)
+	if !v.Visible || !math.IsInf(v.Clearance, 1) || v.Grazing.Latitude != bj.Latitude {
+		t.Errorf("beijing: %+v", v)
+	}
+	// 余隙只约束沿途，不约束端点高度
+	if !bj.IsVisible(tower, &VisibilityOptions{MinClearance: 60}) {
+		t.Error("endpoint height counted against the clearance requirement")
+	}
+	// 低于椭球面的雷达仰视 7 km 外 3000 m 的飞机
+	radar := Geodetic{Latitude: 30, Longitude: 120, Altitude: -5, Ell: wgs}
+	plane := Geodetic{Latitude: 30, Longitude: 120 + 7e3/96.5e3, Altitude: 3000, Ell: wgs}
+	if v := radar.VisibilityTo(plane, nil); !v.Visible || !math.IsInf(v.Clearance, 1) {
+		t.Errorf("radar below the ellipsoid: %+v", v)
+	}
+	// 5 m 桅杆: 对高空目标满足 10 m 余隙，对 15 km 外同高桅杆因地球凸起不满足
+	mast := Geodetic{Latitude: 30, Longitude: 120, Altitude: 5, Ell: wgs}
+	if !mast.IsVisible(plane, &VisibilityOptions{MinClearance: 10}) {
+		t.Error("low mast blocked by its own height")
+	}
+	mast2 := Geodetic{Latitude: 30, Longitude: 120 + 15e3/96.5e3, Altitude: 5, Ell: wgs}
+	v = mast.VisibilityTo(mast2, nil)
+	if !v.Visible || v.Clearance > 5-1 || v.Clearance < 0 || math.Abs(v.Grazing.Longitude-(120+7.5e3/96.5e3)) > 1e-3 {
+		t.Errorf("mast to mast: %+v", v)
+	}
+	if mast.IsVisible(mast2, &VisibilityOptions{MinClearance: 10}) {
+		t.Error("expected clearance requirement to fail over the earth bulge")
+	}
+	ny := Geodetic{Latitude: 40.7128, Longitude: -74.006, Altitude: 10, Ell: wgs}
+	if v := bj.VisibilityTo(ny, nil); v.Visible || v.Clearance > -1e6 {
+		t.Errorf("beijing-new york: %+v", v)
+	}
+	if v := bj.VisibilityTo(bj, nil); !v.Visible || v.SRange != 0 {
+		t.Errorf("coincident: %+v", v)
+	}
+	if under := (Geodetic{Latitude: 10, Longitude: 20, Altitude: -5, Ell: wgs}); under.IsVisible(under, nil) {
+		t.Error("coincident points below the ellipsoid reported visible")
+	}
+}